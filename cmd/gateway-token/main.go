@@ -0,0 +1,37 @@
+// gateway-token emite tokens del gateway WebSocket firmados con
+// GATEWAY_AUTH_SECRET. Los servicios no autentican usuarios, por lo que los
+// tokens los emite quien los autentica delante de ellos; este comando los
+// genera para desarrollo y pruebas.
+//
+//	GATEWAY_AUTH_SECRET=dev-secret go run ./cmd/gateway-token -username alice -ttl 1h
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	gatewayAuth "github.com/nicodelara/microblogging-uala/internal/gateway/infrastructure/auth"
+	"github.com/nicodelara/microblogging-uala/pkg/config"
+)
+
+func main() {
+	username := flag.String("username", "", "usuario del token")
+	ttl := flag.Duration("ttl", time.Hour, "vigencia del token")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	if cfg.GatewayAuthSecret == "" {
+		log.Fatalf("GATEWAY_AUTH_SECRET is required")
+	}
+	if *username == "" || *ttl <= 0 {
+		log.Fatalf("a username and a positive ttl are required")
+	}
+
+	authenticator := gatewayAuth.NewHMACAuthenticator(cfg.GatewayAuthSecret)
+	fmt.Println(authenticator.Sign(*username, time.Now().Add(*ttl)))
+}
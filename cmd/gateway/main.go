@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"log"
+	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/events/kafka"
+	gatewayApp "github.com/nicodelara/microblogging-uala/internal/gateway/application"
+	gatewayAuth "github.com/nicodelara/microblogging-uala/internal/gateway/infrastructure/auth"
+	gatewayHTTP "github.com/nicodelara/microblogging-uala/internal/gateway/infrastructure/http"
+	gatewayMongo "github.com/nicodelara/microblogging-uala/internal/gateway/infrastructure/mongo"
+	userMongo "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/mongo"
+	"github.com/nicodelara/microblogging-uala/pkg/config"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	logger.Init()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	if cfg.GatewayAuthSecret == "" {
+		log.Fatalf("GATEWAY_AUTH_SECRET is required")
+	}
+
+	// Configurar MongoDB
+	mongoClient, err := mongo.Connect(context.Background(), options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	// Verificar conexión a MongoDB
+	if err := mongoClient.Ping(context.Background(), nil); err != nil {
+		log.Fatalf("Error verifying MongoDB connection: %v", err)
+	}
+
+	// Inicializar repositorios
+	userRepo, err := userMongo.NewMongoUserRepository(mongoClient, cfg.UsersDBName, "users")
+	if err != nil {
+		log.Fatalf("Error creating user repository: %v", err)
+	}
+
+	followRepo, err := userMongo.NewMongoFollowRepository(mongoClient, cfg.UsersDBName, "follows")
+	if err != nil {
+		log.Fatalf("Error creating follow repository: %v", err)
+	}
+
+	// Crear adaptador para UserChecker
+	userChecker := common.NewUserCheckerAdapter(userRepo, followRepo)
+	likeReader := gatewayMongo.NewMongoLikeCountReader(mongoClient, cfg.MongoDBName, "tweets")
+
+	hub := gatewayApp.NewHub(userChecker, likeReader, gatewayApp.HubConfig{
+		MaxConnections:        cfg.GatewayMaxConnections,
+		MaxConnectionsPerUser: cfg.GatewayMaxConnectionsPerUser,
+		MaxSubscriptions:      cfg.GatewayMaxSubscriptions,
+	})
+
+	// Cada instancia necesita todos los eventos para sus conexiones, por lo
+	// que usa su propio consumer group, estable entre reinicios para retomar
+	// desde su último offset, y empieza por los eventos nuevos la primera vez
+	consumer, err := kafka.NewLatestConsumer(cfg.Brokers(), cfg.GatewayConsumerGroup, events.TopicTweets, events.TopicUsers)
+	if err != nil {
+		log.Fatalf("Error creating event consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	dispatcher := events.NewDispatcher()
+	dispatcher.On(events.TweetCreated, hub.HandleTweetCreated)
+	dispatcher.On(events.TweetLiked, hub.HandleTweetLiked)
+	dispatcher.On(events.TweetUnliked, hub.HandleTweetUnliked)
+	dispatcher.On(events.UserFollowed, hub.HandleUserFollowed)
+	dispatcher.On(events.UserUnfollowed, hub.HandleUserUnfollowed)
+
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		if err := consumer.Consume(consumerCtx, dispatcher.Handle); err != nil {
+			logger.Error("event consumer stopped: " + err.Error())
+		}
+	}()
+
+	// Configurar router
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(logger.GinLogger())
+
+	// Configurar handlers
+	gatewayHandler := gatewayHTTP.NewGatewayHandler(hub, gatewayAuth.NewHMACAuthenticator(cfg.GatewayAuthSecret))
+
+	// Rutas
+	router.GET("/ws", gatewayHandler.Connect)
+
+	// Configurar servidor HTTP. Las conexiones WebSocket manejan sus propios
+	// deadlines una vez establecidas.
+	srv := &stdhttp.Server{
+		Addr:           ":" + cfg.GatewayPort,
+		Handler:        router,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	// Iniciar servidor en una goroutine
+	go func() {
+		logger.Info("Gateway service running on port " + cfg.GatewayPort)
+		if err := srv.ListenAndServe(); err != nil && err != stdhttp.ErrServerClosed {
+			log.Fatalf("Server error: %v", err)
+		}
+	}()
+
+	// Esperar señal de interrupción
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	logger.Info("Shutting down server...")
+
+	// Dar tiempo para que las conexiones se cierren. Shutdown no espera a las
+	// conexiones WebSocket, que se cierran con el código 1001 para que los
+	// clientes se reconecten a otra instancia.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+	if err := hub.Drain(ctx); err != nil {
+		logger.Error("gateway drain did not finish: " + err.Error())
+	}
+
+	stopConsumer()
+	<-consumerDone
+
+	logger.Info("Server exiting")
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"log"
+	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/events/kafka"
+	"github.com/nicodelara/microblogging-uala/internal/common/inbox"
+	inboxMongo "github.com/nicodelara/microblogging-uala/internal/common/inbox/mongo"
+	outboxMongo "github.com/nicodelara/microblogging-uala/internal/common/outbox/mongo"
+	notificationApp "github.com/nicodelara/microblogging-uala/internal/notifications/application"
+	notificationHTTP "github.com/nicodelara/microblogging-uala/internal/notifications/infrastructure/http"
+	notificationMongo "github.com/nicodelara/microblogging-uala/internal/notifications/infrastructure/mongo"
+	userMongo "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/mongo"
+	"github.com/nicodelara/microblogging-uala/pkg/config"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	logger.Init()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+
+	// Configurar MongoDB
+	mongoClient, err := mongo.Connect(context.Background(), options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	// Verificar conexión a MongoDB
+	if err := mongoClient.Ping(context.Background(), nil); err != nil {
+		log.Fatalf("Error verifying MongoDB connection: %v", err)
+	}
+
+	// Inicializar repositorios
+	notificationRepo, err := notificationMongo.NewMongoNotificationRepository(mongoClient, cfg.MongoDBName, "notifications")
+	if err != nil {
+		log.Fatalf("Error creating notification repository: %v", err)
+	}
+
+	userRepo, err := userMongo.NewMongoUserRepository(mongoClient, cfg.UsersDBName, "users")
+	if err != nil {
+		log.Fatalf("Error creating user repository: %v", err)
+	}
+
+	followRepo, err := userMongo.NewMongoFollowRepository(mongoClient, cfg.UsersDBName, "follows")
+	if err != nil {
+		log.Fatalf("Error creating follow repository: %v", err)
+	}
+
+	// Crear adaptador para UserChecker
+	userChecker := common.NewUserCheckerAdapter(userRepo, followRepo)
+
+	// Inicializar servicio
+	notificationService := notificationApp.NewNotificationService(notificationRepo, userChecker)
+
+	// Consumir eventos de tweets y usuarios para generar las notificaciones
+	inboxStore, err := inboxMongo.NewMongoInboxStore(mongoClient, cfg.MongoDBName, "notifications_inbox")
+	if err != nil {
+		log.Fatalf("Error creating inbox store: %v", err)
+	}
+	transactor := outboxMongo.NewMongoTransactor(mongoClient)
+
+	consumer, err := kafka.NewConsumer(cfg.Brokers(), "notifications", events.TopicTweets, events.TopicUsers)
+	if err != nil {
+		log.Fatalf("Error creating event consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	projector := notificationApp.NewNotificationProjector(notificationRepo)
+
+	dispatcher := events.NewDispatcher()
+	dispatcher.On(events.TweetCreated, inbox.Idempotent(transactor, inboxStore, projector.HandleTweetCreated))
+	dispatcher.On(events.TweetLiked, inbox.Idempotent(transactor, inboxStore, projector.HandleTweetLiked))
+	dispatcher.On(events.UserFollowed, inbox.Idempotent(transactor, inboxStore, projector.HandleUserFollowed))
+
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		if err := consumer.Consume(consumerCtx, dispatcher.Handle); err != nil {
+			logger.Error("event consumer stopped: " + err.Error())
+		}
+	}()
+
+	// Configurar router
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(logger.GinLogger())
+
+	// Configurar handlers
+	notificationHandler := notificationHTTP.NewNotificationHandler(notificationService)
+
+	// Rutas
+	notificationsGroup := router.Group("/notifications")
+	{
+		notificationsGroup.GET("/:username", notificationHandler.ListNotifications)
+		notificationsGroup.GET("/:username/unread-count", notificationHandler.CountUnread)
+		notificationsGroup.POST("/:username/read", notificationHandler.MarkRead)
+	}
+
+	// Configurar servidor HTTP
+	srv := &stdhttp.Server{
+		Addr:           ":" + cfg.NotificationsPort,
+		Handler:        router,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	// Iniciar servidor en una goroutine
+	go func() {
+		logger.Info("Notifications service running on port " + cfg.NotificationsPort)
+		if err := srv.ListenAndServe(); err != nil && err != stdhttp.ErrServerClosed {
+			log.Fatalf("Server error: %v", err)
+		}
+	}()
+
+	// Esperar señal de interrupción
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	logger.Info("Shutting down server...")
+
+	// Dar tiempo para que las conexiones se cierren
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+
+	stopConsumer()
+	<-consumerDone
+
+	logger.Info("Server exiting")
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"log"
 	"net/http"
 	"os"
@@ -12,8 +13,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/events/kafka"
 	timelineApp "github.com/nicodelara/microblogging-uala/internal/timeline/application"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
 	timelineHTTP "github.com/nicodelara/microblogging-uala/internal/timeline/infrastructure/http"
+	timelineMemory "github.com/nicodelara/microblogging-uala/internal/timeline/infrastructure/memory"
 	timelineMongo "github.com/nicodelara/microblogging-uala/internal/timeline/infrastructure/mongo"
 	redisCache "github.com/nicodelara/microblogging-uala/internal/timeline/infrastructure/redis"
 	userMongo "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/mongo"
@@ -66,15 +71,102 @@ func main() {
 	// Crear adaptador para UserChecker
 	userChecker := common.NewUserCheckerAdapter(userRepo, followRepo)
 
-	// Inicializar repositorio de caché con TTL
-	cacheRepo := redisCache.NewRedisCacheRepository(redisClient, cfg.CacheTTL)
+	// Inicializar repositorio de caché con TTL. Las claves vencidas se
+	// conservan CacheStaleTTL para servirlas mientras se regeneran.
+	var cacheRepo ports.CacheRepository = redisCache.NewRedisCacheRepository(redisClient, cfg.CacheTTL, cfg.CacheStaleTTL)
+
+	// Caché local delante de Redis; las réplicas se avisan por pub/sub las
+	// claves modificadas para descartar sus copias
+	if cfg.LocalCacheTTL > 0 && cfg.LocalCacheMaxBytes > 0 {
+		localCache := timelineMemory.NewLRUCacheRepository(cacheRepo, redisCache.NewRedisCacheInvalidationBus(redisClient), timelineMemory.LRUConfig{
+			TTL:      cfg.LocalCacheTTL,
+			MaxBytes: cfg.LocalCacheMaxBytes,
+		})
+		invalidationsCtx, stopInvalidations := context.WithCancel(context.Background())
+		defer stopInvalidations()
+		go localCache.ListenInvalidations(invalidationsCtx)
+		expvar.Publish("timeline_local_cache", expvar.Func(func() any { return localCache.Metrics() }))
+		cacheRepo = localCache
+	}
+
+	cacheLoader := timelineApp.NewCacheLoader(cacheRepo, redisCache.NewRedisCacheLeaseRepository(redisClient), timelineApp.CacheLoaderConfig{
+		LeaseTTL:  cfg.CacheLeaseTTL,
+		LeaseWait: cfg.CacheLeaseWait,
+	})
+	expvar.Publish("timeline_cache", expvar.Func(func() any { return cacheLoader.Metrics() }))
+
+	// Inicializar repositorios de timelines precalculados y celebridades
+	homeTimelineRepo := redisCache.NewRedisHomeTimelineRepository(redisClient, cfg.TimelineMaxLength)
+	celebrityRepo := redisCache.NewRedisCelebrityRepository(redisClient)
+	authorRepo := redisCache.NewRedisAuthorRepository(redisClient)
+	trendRepo := redisCache.NewRedisTrendRepository(redisClient, timelineApp.DefaultTrendWindows)
 
 	// Inicializar servicios
+	timelineCfg := timelineApp.TimelineConfig{
+		HomeTimelineLength:         cfg.TimelineMaxLength,
+		CelebrityFollowerThreshold: cfg.CelebrityFollowerThreshold,
+	}
 	timelineService := timelineApp.NewTimelineService(
 		timelineRepo,
 		userChecker,
-		cacheRepo,
+		cacheLoader,
+		homeTimelineRepo,
+		celebrityRepo,
+		authorRepo,
+		timelineCfg,
 	)
+	hashtagService := timelineApp.NewHashtagService(timelineRepo, trendRepo, authorRepo, timelineApp.DefaultTrendWindows)
+
+	// Inicializar los streams en tiempo real del timeline
+	streamBroker := redisCache.NewRedisStreamBroker(redisClient)
+	defer streamBroker.Close()
+	streamService := timelineApp.NewTimelineStreamService(
+		timelineService,
+		timelineRepo,
+		userChecker,
+		celebrityRepo,
+		authorRepo,
+		streamBroker,
+		timelineApp.StreamConfig{
+			BufferSize:  cfg.StreamBufferSize,
+			ResumeLimit: cfg.StreamResumeLimit,
+		},
+	)
+
+	// Consumir eventos para el fan-out de tweets y la invalidación del caché
+	consumer, err := kafka.NewConsumer(cfg.Brokers(), "timeline", events.TopicTweets, events.TopicUsers)
+	if err != nil {
+		log.Fatalf("Error creating event consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	fanoutWorker := timelineApp.NewFanoutWorker(timelineRepo, userChecker, homeTimelineRepo, celebrityRepo, timelineCfg)
+	invalidator := timelineApp.NewTimelineInvalidator(timelineRepo, userChecker, cacheRepo, homeTimelineRepo, celebrityRepo, timelineCfg)
+	authorProjector := timelineApp.NewAuthorProjector(authorRepo)
+	trendTracker := timelineApp.NewTrendTracker(trendRepo)
+	streamPublisher := timelineApp.NewStreamPublisher(userChecker, celebrityRepo, streamBroker)
+
+	// El fan-out se registra antes que la invalidación para que las lecturas
+	// posteriores ya encuentren el tweet en los timelines precalculados
+	dispatcher := events.NewDispatcher()
+	dispatcher.On(events.TweetCreated, fanoutWorker.HandleTweetCreated)
+	dispatcher.On(events.TweetCreated, invalidator.HandleTweetCreated)
+	dispatcher.On(events.TweetCreated, trendTracker.HandleTweetCreated)
+	dispatcher.On(events.TweetCreated, streamPublisher.HandleTweetCreated)
+	dispatcher.On(events.TweetDeleted, invalidator.HandleTweetDeleted)
+	dispatcher.On(events.TweetDeleted, trendTracker.HandleTweetDeleted)
+	dispatcher.On(events.UserFollowed, invalidator.HandleUserFollowed)
+	dispatcher.On(events.UserUnfollowed, invalidator.HandleUserUnfollowed)
+	dispatcher.On(events.UserUpdated, authorProjector.HandleUserUpdated)
+
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		if err := consumer.Consume(consumerCtx, dispatcher.Handle); err != nil {
+			logger.Error("event consumer stopped: " + err.Error())
+		}
+	}()
 
 	// Configurar router
 	router := gin.New()
@@ -83,14 +175,21 @@ func main() {
 
 	// Configurar handlers
 	timelineHandler := timelineHTTP.NewTimelineHandler(timelineService)
+	hashtagHandler := timelineHTTP.NewHashtagHandler(hashtagService)
+	streamHandler := timelineHTTP.NewStreamHandler(streamService, cfg.StreamHeartbeatInterval)
 
 	// Rutas
 	timelineGroup := router.Group("/timeline")
 	{
 		timelineGroup.GET("/:username", timelineHandler.GetTimeline)
+		timelineGroup.GET("/:username/stream", streamHandler.StreamTimeline)
+		timelineGroup.GET("/:username/new-count", timelineHandler.CountNewTweets)
 	}
+	router.GET("/hashtags/:tag/tweets", hashtagHandler.GetHashtagTweets)
+	router.GET("/trends", hashtagHandler.GetTrends)
 
-	// Configurar servidor HTTP
+	// Configurar servidor HTTP. WriteTimeout limita las respuestas comunes; los
+	// streams lo reemplazan por un deadline en cada escritura.
 	srv := &http.Server{
 		Addr:           ":" + cfg.TimelinePort,
 		Handler:        router,
@@ -99,6 +198,26 @@ func main() {
 		MaxHeaderBytes: 1 << 20,
 	}
 
+	// Shutdown espera a que terminen las respuestas en curso, por lo que los
+	// streams se cierran al empezar el apagado
+	srv.RegisterOnShutdown(streamHandler.Close)
+
+	// Las métricas del proceso, incluidos los contadores del caché, se sirven
+	// en un listener aparte que no se publica junto con la API
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/debug/vars", expvar.Handler())
+	adminSrv := &http.Server{
+		Addr:              cfg.TimelineAdminAddr,
+		Handler:           adminMux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		logger.Info("Timeline admin listener running on " + cfg.TimelineAdminAddr)
+		if err := adminSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("admin listener stopped: " + err.Error())
+		}
+	}()
+
 	// Iniciar servidor en una goroutine
 	go func() {
 		logger.Info("Timeline service running on port " + cfg.TimelinePort)
@@ -116,9 +235,13 @@ func main() {
 	// Dar tiempo para que las conexiones se cierren
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	_ = adminSrv.Shutdown(ctx)
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	stopConsumer()
+	<-consumerDone
+
 	logger.Info("Server exiting")
 }
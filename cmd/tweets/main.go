@@ -11,6 +11,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events/kafka"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	outboxMongo "github.com/nicodelara/microblogging-uala/internal/common/outbox/mongo"
 	tweetApp "github.com/nicodelara/microblogging-uala/internal/tweets/application"
 	tweetHTTP "github.com/nicodelara/microblogging-uala/internal/tweets/infrastructure/http"
 	tweetMongo "github.com/nicodelara/microblogging-uala/internal/tweets/infrastructure/mongo"
@@ -47,6 +50,16 @@ func main() {
 		log.Fatalf("Error creating tweet repository: %v", err)
 	}
 
+	tweetSearcher, err := tweetMongo.NewMongoTweetSearcher(mongoClient, cfg.MongoDBName, "tweets")
+	if err != nil {
+		log.Fatalf("Error creating tweet searcher: %v", err)
+	}
+
+	likeRepo, err := tweetMongo.NewMongoLikeRepository(mongoClient, cfg.MongoDBName, "likes")
+	if err != nil {
+		log.Fatalf("Error creating like repository: %v", err)
+	}
+
 	userRepo, err := userMongo.NewMongoUserRepository(mongoClient, cfg.UsersDBName, "users")
 	if err != nil {
 		log.Fatalf("Error creating user repository: %v", err)
@@ -60,8 +73,36 @@ func main() {
 	// Crear adaptador para UserChecker
 	userChecker := common.NewUserCheckerAdapter(userRepo, followRepo)
 
+	// Configurar publicador de eventos
+	publisher, err := kafka.NewPublisher(cfg.Brokers())
+	if err != nil {
+		log.Fatalf("Error creating event publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	// Configurar outbox y relay de eventos hacia Kafka
+	outboxStore, err := outboxMongo.NewMongoOutboxStore(mongoClient, cfg.MongoDBName, "tweets_outbox")
+	if err != nil {
+		log.Fatalf("Error creating outbox store: %v", err)
+	}
+	transactor := outboxMongo.NewMongoTransactor(mongoClient)
+
+	relayCfg := outbox.DefaultRelayConfig()
+	relayCfg.PollInterval = cfg.OutboxPollInterval
+	relayCfg.MaxAttempts = cfg.OutboxMaxAttempts
+	relay := outbox.NewRelay(outboxStore, publisher, relayCfg)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(relayCtx)
+	}()
+
 	// Inicializar servicio
-	tweetService := tweetApp.NewTweetService(tweetRepo, userChecker)
+	tweetService := tweetApp.NewTweetService(tweetRepo, userChecker, transactor, outboxStore)
+	likeService := tweetApp.NewLikeService(likeRepo, tweetRepo, userChecker, transactor, outboxStore)
+	searchService := tweetApp.NewSearchService(tweetSearcher)
 
 	// Configurar router
 	router := gin.New()
@@ -70,12 +111,28 @@ func main() {
 
 	// Configurar handlers
 	tweetHandler := tweetHTTP.NewTweetHandler(tweetService)
+	likeHandler := tweetHTTP.NewLikeHandler(likeService)
+	searchHandler := tweetHTTP.NewSearchHandler(searchService)
 
 	// Rutas
 	tweetsGroup := router.Group("/tweets")
 	{
 		tweetsGroup.POST("", tweetHandler.CreateTweet)
+		tweetsGroup.GET("/:id", tweetHandler.GetTweet)
+		tweetsGroup.GET("/:id/thread", tweetHandler.GetThread)
+		tweetsGroup.DELETE("/:id", tweetHandler.DeleteTweet)
+		tweetsGroup.POST("/:id/retweet", tweetHandler.Retweet)
+		tweetsGroup.DELETE("/:id/retweet", tweetHandler.UndoRetweet)
+		tweetsGroup.POST("/:id/like", likeHandler.LikeTweet)
+		tweetsGroup.DELETE("/:id/like", likeHandler.UnlikeTweet)
+		tweetsGroup.GET("/:id/likes", likeHandler.ListLikers)
+	}
+	usersGroup := router.Group("/users")
+	{
+		usersGroup.GET("/:username/tweets", tweetHandler.ListUserTweets)
+		usersGroup.GET("/:username/likes", likeHandler.ListUserLikes)
 	}
+	router.GET("/search/tweets", searchHandler.SearchTweets)
 
 	// Configurar servidor HTTP
 	srv := &stdhttp.Server{
@@ -107,5 +164,8 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	stopRelay()
+	<-relayDone
+
 	logger.Info("Server exiting")
 }
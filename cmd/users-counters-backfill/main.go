@@ -0,0 +1,52 @@
+// users-counters-backfill recalcula los contadores de seguidores y seguidos
+// de todos los usuarios contando los follows, para los usuarios creados antes
+// de que existieran los contadores. Se ejecuta una vez al desplegar los
+// contadores; volver a ejecutarlo sólo corrige los que estén desfasados.
+//
+//	MONGO_URI=mongodb://localhost:27017 go run ./cmd/users-counters-backfill
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/nicodelara/microblogging-uala/internal/users/application"
+	usermongo "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/mongo"
+	"github.com/nicodelara/microblogging-uala/pkg/config"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	logger.Init()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+
+	ctx := context.Background()
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(ctx)
+
+	userRepo, err := usermongo.NewMongoUserRepository(mongoClient, cfg.MongoDBName, "users")
+	if err != nil {
+		log.Fatalf("Error creating user repository: %v", err)
+	}
+	followRepo, err := usermongo.NewMongoFollowRepository(mongoClient, cfg.MongoDBName, "follows")
+	if err != nil {
+		log.Fatalf("Error creating follow repository: %v", err)
+	}
+
+	backfill := application.NewCounterBackfill(userRepo, followRepo)
+	processed, err := backfill.Run(ctx)
+	if err != nil {
+		log.Fatalf("Error backfilling user counters after %d users: %v", processed, err)
+	}
+	logger.Info("User counters backfilled for " + strconv.Itoa(processed) + " users")
+}
@@ -0,0 +1,55 @@
+// users-search-backfill completa las claves de búsqueda de los usuarios
+// creados antes de que existieran y los indexa en el índice de sugerencias.
+// Se ejecuta una vez al desplegar la búsqueda de usuarios; volver a
+// ejecutarlo no altera los datos.
+//
+//	MONGO_URI=mongodb://localhost:27017 REDIS_ADDR=localhost:6379 go run ./cmd/users-search-backfill
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/nicodelara/microblogging-uala/internal/users/application"
+	usermongo "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/mongo"
+	userredis "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/redis"
+	"github.com/nicodelara/microblogging-uala/pkg/config"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	logger.Init()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+
+	ctx := context.Background()
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer mongoClient.Disconnect(ctx)
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: cfg.RedisAddr,
+	})
+	defer redisClient.Close()
+
+	userRepo, err := usermongo.NewMongoUserRepository(mongoClient, cfg.MongoDBName, "users")
+	if err != nil {
+		log.Fatalf("Error creating user repository: %v", err)
+	}
+
+	backfill := application.NewSearchBackfill(userRepo, userredis.NewRedisTypeaheadIndex(redisClient))
+	processed, err := backfill.Run(ctx)
+	if err != nil {
+		log.Fatalf("Error backfilling user search after %d users: %v", processed, err)
+	}
+	logger.Info("User search backfilled for " + strconv.Itoa(processed) + " users")
+}
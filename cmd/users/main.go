@@ -10,9 +10,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/events/kafka"
+	"github.com/nicodelara/microblogging-uala/internal/common/inbox"
+	inboxMongo "github.com/nicodelara/microblogging-uala/internal/common/inbox/mongo"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	outboxMongo "github.com/nicodelara/microblogging-uala/internal/common/outbox/mongo"
 	"github.com/nicodelara/microblogging-uala/internal/users/application"
 	userhttp "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/http"
 	usermongo "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/mongo"
+	userredis "github.com/nicodelara/microblogging-uala/internal/users/infrastructure/redis"
 	"github.com/nicodelara/microblogging-uala/pkg/config"
 	"github.com/nicodelara/microblogging-uala/pkg/logger"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -41,6 +49,16 @@ func main() {
 		log.Fatalf("Error verifying MongoDB connection: %v", err)
 	}
 
+	// Configurar Redis
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: cfg.RedisAddr,
+	})
+	defer redisClient.Close()
+
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		log.Fatalf("Error connecting to Redis: %v", err)
+	}
+
 	// Inicializar repositorio de usuarios
 	userRepo, err := usermongo.NewMongoUserRepository(mongoClient, cfg.MongoDBName, "users")
 	if err != nil {
@@ -53,8 +71,71 @@ func main() {
 		log.Fatalf("Error creating follow repository: %v", err)
 	}
 
+	// Configurar publicador de eventos
+	publisher, err := kafka.NewPublisher(cfg.Brokers())
+	if err != nil {
+		log.Fatalf("Error creating event publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	// Configurar outbox y relay de eventos hacia Kafka
+	outboxStore, err := outboxMongo.NewMongoOutboxStore(mongoClient, cfg.MongoDBName, "users_outbox")
+	if err != nil {
+		log.Fatalf("Error creating outbox store: %v", err)
+	}
+	transactor := outboxMongo.NewMongoTransactor(mongoClient)
+
+	relayCfg := outbox.DefaultRelayConfig()
+	relayCfg.PollInterval = cfg.OutboxPollInterval
+	relayCfg.MaxAttempts = cfg.OutboxMaxAttempts
+	relay := outbox.NewRelay(outboxStore, publisher, relayCfg)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(relayCtx)
+	}()
+
 	// Crear servicio de usuarios
-	userSvc := application.NewUserService(userRepo, followRepo)
+	userSvc := application.NewUserService(userRepo, followRepo, transactor, outboxStore)
+
+	// Crear servicio de búsqueda de usuarios
+	typeaheadIndex := userredis.NewRedisTypeaheadIndex(redisClient)
+	searchSvc := application.NewUserSearchService(userRepo, typeaheadIndex)
+
+	// Consumir eventos de tweets para mantener los contadores de los usuarios,
+	// y los propios eventos de usuarios para mantener el índice de typeahead
+	inboxStore, err := inboxMongo.NewMongoInboxStore(mongoClient, cfg.MongoDBName, "users_inbox")
+	if err != nil {
+		log.Fatalf("Error creating inbox store: %v", err)
+	}
+
+	consumer, err := kafka.NewConsumer(cfg.Brokers(), "users", events.TopicTweets, events.TopicUsers)
+	if err != nil {
+		log.Fatalf("Error creating event consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	counterUpdater := application.NewCounterUpdater(userRepo)
+	typeaheadIndexer := application.NewTypeaheadIndexer(userRepo, typeaheadIndex)
+
+	dispatcher := events.NewDispatcher()
+	dispatcher.On(events.TweetCreated, inbox.Idempotent(transactor, inboxStore, counterUpdater.HandleTweetCreated))
+	dispatcher.On(events.TweetDeleted, inbox.Idempotent(transactor, inboxStore, counterUpdater.HandleTweetDeleted))
+	dispatcher.On(events.UserCreated, typeaheadIndexer.HandleUserCreated)
+	dispatcher.On(events.UserUpdated, typeaheadIndexer.HandleUserUpdated)
+	dispatcher.On(events.UserFollowed, typeaheadIndexer.HandleUserFollowed)
+	dispatcher.On(events.UserUnfollowed, typeaheadIndexer.HandleUserUnfollowed)
+
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		if err := consumer.Consume(consumerCtx, dispatcher.Handle); err != nil {
+			logger.Error("event consumer stopped: " + err.Error())
+		}
+	}()
 
 	// Configurar router
 	router := gin.New()
@@ -63,12 +144,20 @@ func main() {
 
 	// Configurar handlers
 	userHandler := userhttp.NewUserHandler(userSvc)
+	searchHandler := userhttp.NewSearchHandler(searchSvc)
 
 	// Configurar rutas
 	usersGroup := router.Group("/users")
 	{
 		usersGroup.POST("", userHandler.CreateUser)
+		usersGroup.GET("/search", searchHandler.SearchUsers)
+		usersGroup.GET("/typeahead", searchHandler.Typeahead)
+		usersGroup.GET("/:username", userHandler.GetUser)
+		usersGroup.PATCH("/:username", userHandler.UpdateUser)
 		usersGroup.POST("/:username/follow", userHandler.FollowUser)
+		usersGroup.DELETE("/:username/follow/:target", userHandler.UnfollowUser)
+		usersGroup.GET("/:username/following", userHandler.ListFollowings)
+		usersGroup.GET("/:username/followers", userHandler.ListFollowers)
 	}
 
 	// Configurar servidor HTTP
@@ -101,5 +190,11 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	stopConsumer()
+	<-consumerDone
+
+	stopRelay()
+	<-relayDone
+
 	logger.Info("Server exiting")
 }
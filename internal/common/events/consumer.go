@@ -0,0 +1,44 @@
+package events
+
+import (
+	"context"
+	"errors"
+)
+
+// Handler procesa un evento recibido del bus de eventos
+type Handler func(ctx context.Context, event Event) error
+
+// Consumer define la interfaz para consumir eventos del bus de eventos
+type Consumer interface {
+	// Consume entrega cada evento recibido a handler hasta que se cancele el contexto
+	Consume(ctx context.Context, handler Handler) error
+	// Close libera los recursos del consumidor
+	Close() error
+}
+
+// Dispatcher enruta cada evento a los handlers registrados para su tipo.
+// Los eventos sin handlers registrados se ignoran.
+type Dispatcher struct {
+	handlers map[string][]Handler
+}
+
+// NewDispatcher crea una nueva instancia de Dispatcher
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string][]Handler)}
+}
+
+// On registra un handler para un tipo de evento
+func (d *Dispatcher) On(eventType string, handler Handler) {
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Handle ejecuta todos los handlers registrados para el tipo del evento
+func (d *Dispatcher) Handle(ctx context.Context, event Event) error {
+	var errs []error
+	for _, handler := range d.handlers[event.Type] {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
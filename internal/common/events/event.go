@@ -0,0 +1,166 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tópicos donde se publican los eventos de dominio
+const (
+	TopicTweets = "tweets"
+	TopicUsers  = "users"
+)
+
+// Tipos de eventos de dominio
+const (
+	TweetCreated   = "TweetCreated"
+	TweetDeleted   = "TweetDeleted"
+	TweetLiked     = "TweetLiked"
+	TweetUnliked   = "TweetUnliked"
+	UserCreated    = "UserCreated"
+	UserFollowed   = "UserFollowed"
+	UserUnfollowed = "UserUnfollowed"
+	UserUpdated    = "UserUpdated"
+)
+
+// Versión actual del payload de cada tipo de evento. Se incrementa ante
+// cambios incompatibles para que los consumidores puedan distinguirlos.
+const (
+	TweetCreatedVersion   = 1
+	TweetDeletedVersion   = 1
+	TweetLikedVersion     = 1
+	TweetUnlikedVersion   = 1
+	UserCreatedVersion    = 1
+	UserFollowedVersion   = 1
+	UserUnfollowedVersion = 1
+	UserUpdatedVersion    = 1
+)
+
+// Event es el sobre común de todos los eventos de dominio
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	Topic      string          `json:"topic"`
+	Key        string          `json:"key"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// New crea un evento serializando el payload recibido.
+// La clave se usa para particionar, garantizando el orden por entidad.
+func New(topic, eventType string, version int, key string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Version:    version,
+		Topic:      topic,
+		Key:        key,
+		OccurredAt: time.Now().UTC(),
+		Payload:    data,
+	}, nil
+}
+
+// Decode deserializa el payload del evento en v
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// TweetCreatedPayload es el payload del evento TweetCreated
+type TweetCreatedPayload struct {
+	TweetID   string    `json:"tweetId"`
+	Username  string    `json:"username"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Campos agregados con las respuestas; vacíos en los tweets que no lo son.
+	// InReplyToUsername es el autor del tweet respondido.
+	InReplyToTweetID  string `json:"inReplyToTweetId,omitempty"`
+	InReplyToUsername string `json:"inReplyToUsername,omitempty"`
+	ConversationID    string `json:"conversationId,omitempty"`
+
+	// Campos agregados con los retweets y quote tweets
+	RetweetOf     string `json:"retweetOf,omitempty"`
+	QuotedTweetID string `json:"quotedTweetId,omitempty"`
+
+	// Hashtags (en minúsculas) y usuarios mencionados en el contenido, sin repetir
+	Hashtags []string `json:"hashtags,omitempty"`
+	Mentions []string `json:"mentions,omitempty"`
+}
+
+// TweetDeletedPayload es el payload del evento TweetDeleted. CreatedAt y
+// Hashtags permiten descontar el tweet de las tendencias en las que se contó.
+type TweetDeletedPayload struct {
+	TweetID   string    `json:"tweetId"`
+	Username  string    `json:"username"`
+	DeletedAt time.Time `json:"deletedAt"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	Hashtags  []string  `json:"hashtags,omitempty"`
+}
+
+// TweetLikedPayload es el payload del evento TweetLiked. Author es el autor
+// del tweet, para que los consumidores no necesiten buscarlo.
+type TweetLikedPayload struct {
+	LikeID    string    `json:"likeId"`
+	TweetID   string    `json:"tweetId"`
+	Username  string    `json:"username"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TweetUnlikedPayload es el payload del evento TweetUnliked
+type TweetUnlikedPayload struct {
+	TweetID   string    `json:"tweetId"`
+	Username  string    `json:"username"`
+	Author    string    `json:"author"`
+	UnlikedAt time.Time `json:"unlikedAt"`
+}
+
+// UserCreatedPayload es el payload del evento UserCreated
+type UserCreatedPayload struct {
+	UserID    string    `json:"userId"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UserFollowedPayload es el payload del evento UserFollowed
+type UserFollowedPayload struct {
+	FollowID  string    `json:"followId"`
+	Username  string    `json:"username"`
+	Following string    `json:"following"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UserUnfollowedPayload es el payload del evento UserUnfollowed
+type UserUnfollowedPayload struct {
+	Username     string    `json:"username"`
+	Following    string    `json:"following"`
+	UnfollowedAt time.Time `json:"unfollowedAt"`
+}
+
+// UserUpdatedPayload es el payload del evento UserUpdated. Contiene el perfil
+// completo resultante para que los consumidores no dependan del orden de las
+// actualizaciones parciales.
+type UserUpdatedPayload struct {
+	UserID      string    `json:"userId"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName"`
+	Bio         string    `json:"bio"`
+	Location    string    `json:"location"`
+	Website     string    `json:"website"`
+	AvatarURL   string    `json:"avatarUrl"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// Version crece con cada actualización del perfil del usuario. Los
+	// eventos pueden llegar desordenados, por lo que un consumidor no aplica
+	// uno con una versión menor a la del perfil que ya tiene.
+	Version int64 `json:"version,omitempty"`
+}
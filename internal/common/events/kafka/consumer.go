@@ -0,0 +1,120 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// Espera inicial y máxima entre reintentos de un evento que falló
+	minHandlerBackoff = 100 * time.Millisecond
+	maxHandlerBackoff = 30 * time.Second
+)
+
+// Consumer es la implementación de events.Consumer sobre un consumer group de Kafka
+type Consumer struct {
+	reader *kafka.Reader
+}
+
+// NewConsumer crea un consumidor del grupo groupID suscripto a los tópicos indicados
+func NewConsumer(brokers []string, groupID string, topics ...string) (*Consumer, error) {
+	return newConsumer(brokers, groupID, kafka.FirstOffset, topics)
+}
+
+// NewLatestConsumer crea un consumidor que, si el grupo todavía no confirmó
+// offsets, empieza por los eventos nuevos en lugar de por el principio de
+// cada tópico. Sirve para los consumidores que sólo reenvían eventos en vivo.
+func NewLatestConsumer(brokers []string, groupID string, topics ...string) (*Consumer, error) {
+	return newConsumer(brokers, groupID, kafka.LastOffset, topics)
+}
+
+func newConsumer(brokers []string, groupID string, startOffset int64, topics []string) (*Consumer, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("at least one kafka broker is required")
+	}
+	if groupID == "" {
+		return nil, errors.New("consumer group id is required")
+	}
+
+	return &Consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     brokers,
+			GroupID:     groupID,
+			GroupTopics: topics,
+			StartOffset: startOffset,
+		}),
+	}, nil
+}
+
+// Consume entrega los eventos a handler y confirma el offset una vez procesados.
+// Un evento que falla se reintenta hasta que se procese o se cancele ctx, lo
+// que detiene su partición: descartarlo perdería el efecto para siempre, ya
+// que el outbox garantiza la entrega at-least-once sólo hasta Kafka. Los
+// mensajes que no son eventos válidos se registran y se descartan.
+func (c *Consumer) Consume(ctx context.Context, handler events.Handler) error {
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		var evt events.Event
+		if err := json.Unmarshal(msg.Value, &evt); err != nil {
+			logger.Error(fmt.Sprintf("kafka consumer: discarding malformed message at %s/%d/%d: %v", msg.Topic, msg.Partition, msg.Offset, err))
+		} else {
+			evt.Topic = msg.Topic
+			if !handle(ctx, handler, evt) {
+				// Cancelado antes de procesarlo: sin confirmar el offset, el
+				// evento se vuelve a entregar al reiniciar
+				return nil
+			}
+		}
+
+		if err := c.reader.CommitMessages(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Close cierra la conexión con los brokers
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
+
+// handle ejecuta handler con backoff exponencial hasta que procese el evento.
+// Devuelve false si ctx se canceló antes de lograrlo.
+func handle(ctx context.Context, handler events.Handler, evt events.Event) bool {
+	backoff := minHandlerBackoff
+	for attempt := 1; ; attempt++ {
+		err := handler(ctx, evt)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		logger.Error(fmt.Sprintf("kafka consumer: event %s (%s) failed on attempt %d, retrying in %s: %v", evt.ID, evt.Type, attempt, backoff, err))
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxHandlerBackoff)
+	}
+}
@@ -0,0 +1,5 @@
+// Package kafka implementa los puertos de eventos sobre Kafka.
+//
+// El cliente de Kafka se compila sólo con el tag "kafka" (los Dockerfiles lo
+// activan), de modo que los builds locales y los tests no dependen de él.
+package kafka
@@ -0,0 +1,62 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/segmentio/kafka-go"
+)
+
+// Publisher es la implementación de events.Publisher sobre Kafka
+type Publisher struct {
+	writer *kafka.Writer
+}
+
+// NewPublisher crea un publisher conectado a los brokers indicados
+func NewPublisher(brokers []string) (*Publisher, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("at least one kafka broker is required")
+	}
+
+	return &Publisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+	}, nil
+}
+
+// Publish publica los eventos en su tópico usando la clave del evento como clave de partición
+func (p *Publisher) Publish(ctx context.Context, evts ...events.Event) error {
+	messages := make([]kafka.Message, 0, len(evts))
+	for _, evt := range evts {
+		value, err := json.Marshal(evt)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, kafka.Message{
+			Topic: evt.Topic,
+			Key:   []byte(evt.Key),
+			Value: value,
+			Time:  evt.OccurredAt,
+			Headers: []kafka.Header{
+				{Key: "type", Value: []byte(evt.Type)},
+				{Key: "version", Value: []byte(strconv.Itoa(evt.Version))},
+			},
+		})
+	}
+
+	return p.writer.WriteMessages(ctx, messages...)
+}
+
+// Close libera las conexiones con los brokers
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,54 @@
+//go:build !kafka
+
+package kafka
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+)
+
+// ErrUnavailable se devuelve cuando el binario se compiló sin el tag "kafka"
+var ErrUnavailable = errors.New("kafka support not compiled in, build with -tags kafka")
+
+// Publisher es un marcador para builds sin soporte de Kafka
+type Publisher struct{}
+
+// NewPublisher devuelve ErrUnavailable en builds sin el tag "kafka"
+func NewPublisher(brokers []string) (*Publisher, error) {
+	return nil, ErrUnavailable
+}
+
+// Publish devuelve siempre ErrUnavailable
+func (p *Publisher) Publish(ctx context.Context, evts ...events.Event) error {
+	return ErrUnavailable
+}
+
+// Close no realiza ninguna acción
+func (p *Publisher) Close() error {
+	return nil
+}
+
+// Consumer es un marcador para builds sin soporte de Kafka
+type Consumer struct{}
+
+// NewConsumer devuelve ErrUnavailable en builds sin el tag "kafka"
+func NewConsumer(brokers []string, groupID string, topics ...string) (*Consumer, error) {
+	return nil, ErrUnavailable
+}
+
+// NewLatestConsumer devuelve ErrUnavailable en builds sin el tag "kafka"
+func NewLatestConsumer(brokers []string, groupID string, topics ...string) (*Consumer, error) {
+	return nil, ErrUnavailable
+}
+
+// Consume devuelve siempre ErrUnavailable
+func (c *Consumer) Consume(ctx context.Context, handler events.Handler) error {
+	return ErrUnavailable
+}
+
+// Close no realiza ninguna acción
+func (c *Consumer) Close() error {
+	return nil
+}
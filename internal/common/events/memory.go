@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher es una implementación en memoria de Publisher pensada para tests
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []Event
+	err    error
+}
+
+// NewMemoryPublisher crea una nueva instancia de MemoryPublisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish almacena los eventos recibidos, o devuelve el error configurado con FailWith
+func (p *MemoryPublisher) Publish(ctx context.Context, events ...Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// FailWith hace que las siguientes publicaciones fallen con err
+func (p *MemoryPublisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// Events devuelve una copia de los eventos publicados
+func (p *MemoryPublisher) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make([]Event, len(p.events))
+	copy(events, p.events)
+	return events
+}
@@ -0,0 +1,9 @@
+package events
+
+import "context"
+
+// Publisher define la interfaz para publicar eventos de dominio en el bus de eventos
+type Publisher interface {
+	// Publish publica uno o más eventos
+	Publish(ctx context.Context, events ...Event) error
+}
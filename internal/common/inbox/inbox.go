@@ -0,0 +1,36 @@
+package inbox
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+)
+
+// Store registra los eventos ya procesados por un consumidor. Complementa la
+// entrega at-least-once del relay y de Kafka para que los handlers con
+// efectos no idempotentes (por ejemplo, incrementar un contador) apliquen
+// cada evento una sola vez.
+type Store interface {
+	// MarkProcessed registra el evento e indica si es la primera vez que se
+	// procesa. Devuelve false si el evento ya había sido registrado.
+	MarkProcessed(ctx context.Context, eventID string) (bool, error)
+}
+
+// Idempotent envuelve un handler para que cada evento se aplique una sola vez.
+// El registro del evento y el handler se ejecutan en la misma transacción, de
+// modo que si el handler falla el evento vuelve a quedar pendiente.
+func Idempotent(tx outbox.Transactor, store Store, handler events.Handler) events.Handler {
+	return func(ctx context.Context, evt events.Event) error {
+		return tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			first, err := store.MarkProcessed(ctx, evt.ID)
+			if err != nil {
+				return err
+			}
+			if !first {
+				return nil
+			}
+			return handler(ctx, evt)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotent(t *testing.T) {
+	evt, err := events.New(events.TopicTweets, events.TweetCreated, events.TweetCreatedVersion, "alice", events.TweetCreatedPayload{TweetID: "t1"})
+	assert.NoError(t, err)
+
+	store := NewMemoryStore()
+	calls := 0
+	handler := Idempotent(outbox.NopTransactor{}, store, func(ctx context.Context, evt events.Event) error {
+		calls++
+		return nil
+	})
+
+	assert.NoError(t, handler(context.Background(), evt))
+	assert.NoError(t, handler(context.Background(), evt))
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotent_PropagatesHandlerError(t *testing.T) {
+	evt, err := events.New(events.TopicTweets, events.TweetCreated, events.TweetCreatedVersion, "alice", events.TweetCreatedPayload{TweetID: "t1"})
+	assert.NoError(t, err)
+
+	handler := Idempotent(outbox.NopTransactor{}, NewMemoryStore(), func(ctx context.Context, evt events.Event) error {
+		return errors.New("boom")
+	})
+
+	assert.EqualError(t, handler(context.Background(), evt), "boom")
+}
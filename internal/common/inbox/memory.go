@@ -0,0 +1,29 @@
+package inbox
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore es una implementación en memoria del inbox pensada para tests
+type MemoryStore struct {
+	mu        sync.Mutex
+	processed map[string]struct{}
+}
+
+// NewMemoryStore crea una nueva instancia de MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{processed: make(map[string]struct{})}
+}
+
+// MarkProcessed registra el evento e indica si es la primera vez que se procesa
+func (s *MemoryStore) MarkProcessed(ctx context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.processed[eventID]; ok {
+		return false, nil
+	}
+	s.processed[eventID] = struct{}{}
+	return true, nil
+}
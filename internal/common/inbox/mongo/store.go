@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// processedRetention es el tiempo que se recuerda un evento procesado. Debe
+// superar holgadamente la ventana en la que el relay o Kafka pueden
+// reentregarlo.
+const processedRetention = 7 * 24 * time.Hour
+
+// inboxDocument es la estructura que representa un evento procesado en MongoDB
+type inboxDocument struct {
+	ID          string    `bson:"_id"`
+	ProcessedAt time.Time `bson:"processedAt"`
+}
+
+// mongoInboxStore es la implementación del inbox usando MongoDB.
+// Implementa inbox.Store.
+type mongoInboxStore struct {
+	collection *mongo.Collection
+}
+
+func NewMongoInboxStore(client *mongo.Client, dbName, collName string) (*mongoInboxStore, error) {
+	if client == nil {
+		return nil, errors.New("mongo client is required")
+	}
+
+	collection := client.Database(dbName).Collection(collName)
+
+	// Crear índices
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "processedAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(processedRetention.Seconds())),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoInboxStore{collection: collection}, nil
+}
+
+// MarkProcessed inserta el id del evento; el índice único de _id garantiza que
+// sólo la primera entrega lo registre
+func (s *mongoInboxStore) MarkProcessed(ctx context.Context, eventID string) (bool, error) {
+	_, err := s.collection.InsertOne(ctx, inboxDocument{ID: eventID, ProcessedAt: time.Now()})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
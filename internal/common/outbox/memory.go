@@ -0,0 +1,133 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+)
+
+// ErrRecordNotFound se devuelve al actualizar un registro inexistente
+var ErrRecordNotFound = errors.New("outbox record not found")
+
+// MemoryStore es una implementación en memoria del outbox pensada para tests.
+// Implementa Store y events.Publisher.
+type MemoryStore struct {
+	mu       sync.Mutex
+	records  map[string]*Record
+	sequence int64
+}
+
+// NewMemoryStore crea una nueva instancia de MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*Record)}
+}
+
+// Publish agrega los eventos al outbox como pendientes
+func (s *MemoryStore) Publish(ctx context.Context, evts ...events.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, evt := range evts {
+		s.sequence++
+		s.records[evt.ID] = &Record{
+			Event:         evt,
+			Sequence:      s.sequence,
+			Status:        StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+	return nil
+}
+
+// Claim reserva los registros pendientes más antiguos cuya clave no tiene
+// registros anteriores sin publicar fuera del lote
+func (s *MemoryStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var unpublished []*Record
+	for _, record := range s.records {
+		if record.Status != StatusPublished {
+			unpublished = append(unpublished, record)
+		}
+	}
+	sort.Slice(unpublished, func(i, j int) bool {
+		return unpublished[i].Sequence < unpublished[j].Sequence
+	})
+
+	now := time.Now()
+	blocked := make(map[string]bool)
+	var records []Record
+	for _, record := range unpublished {
+		if len(records) == limit {
+			break
+		}
+		key := record.Event.Key
+		if blocked[key] {
+			continue
+		}
+		if record.Status != StatusPending || record.NextAttemptAt.After(now) {
+			blocked[key] = true
+			continue
+		}
+		record.NextAttemptAt = now.Add(lease)
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+// MarkPublished marca un registro como entregado
+func (s *MemoryStore) MarkPublished(ctx context.Context, id string) error {
+	return s.update(id, func(r *Record) {
+		r.Status = StatusPublished
+		r.PublishedAt = time.Now()
+	})
+}
+
+// MarkFailed registra un intento fallido
+func (s *MemoryStore) MarkFailed(ctx context.Context, id string, attempts int, retryAt time.Time, reason string) error {
+	return s.update(id, func(r *Record) {
+		r.Attempts = attempts
+		r.NextAttemptAt = retryAt
+		r.LastError = reason
+	})
+}
+
+// MarkDead mueve un registro al estado de dead-letter
+func (s *MemoryStore) MarkDead(ctx context.Context, id string, attempts int, reason string) error {
+	return s.update(id, func(r *Record) {
+		r.Status = StatusDead
+		r.Attempts = attempts
+		r.LastError = reason
+	})
+}
+
+// Record devuelve una copia del registro con el id indicado
+func (s *MemoryStore) Record(id string) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return Record{}, false
+	}
+	return *record, true
+}
+
+func (s *MemoryStore) update(id string, fn func(*Record)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	if !ok {
+		return ErrRecordNotFound
+	}
+	fn(record)
+	return nil
+}
@@ -0,0 +1,246 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// publishedRetention es el tiempo que se conservan los registros ya publicados
+const publishedRetention = 7 * 24 * time.Hour
+
+// outboxDocument es la estructura que representa un registro del outbox en MongoDB
+type outboxDocument struct {
+	ID            string    `bson:"_id"`
+	Topic         string    `bson:"topic"`
+	Type          string    `bson:"type"`
+	Version       int       `bson:"version"`
+	Key           string    `bson:"key"`
+	OccurredAt    time.Time `bson:"occurredAt"`
+	Payload       string    `bson:"payload"`
+	Sequence      int64     `bson:"sequence"`
+	Status        string    `bson:"status"`
+	Attempts      int       `bson:"attempts"`
+	NextAttemptAt time.Time `bson:"nextAttemptAt"`
+	LastError     string    `bson:"lastError,omitempty"`
+	CreatedAt     time.Time `bson:"createdAt"`
+	PublishedAt   time.Time `bson:"publishedAt,omitempty"`
+}
+
+// mongoOutboxStore es la implementación del outbox usando MongoDB.
+// Implementa outbox.Store y events.Publisher.
+type mongoOutboxStore struct {
+	collection *mongo.Collection
+}
+
+func NewMongoOutboxStore(client *mongo.Client, dbName, collName string) (*mongoOutboxStore, error) {
+	if client == nil {
+		return nil, errors.New("mongo client is required")
+	}
+
+	collection := client.Database(dbName).Collection(collName)
+
+	// Crear índices
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "nextAttemptAt", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "sequence", Value: 1},
+			},
+		},
+		{
+			// Busca registros anteriores sin publicar de la misma clave
+			Keys: bson.D{
+				{Key: "key", Value: 1},
+				{Key: "sequence", Value: 1},
+			},
+		},
+		{
+			// Sólo los registros publicados tienen publishedAt, por lo que el
+			// TTL nunca elimina eventos pendientes ni en dead-letter
+			Keys:    bson.D{{Key: "publishedAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(publishedRetention.Seconds())),
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(context.Background(), indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoOutboxStore{
+		collection: collection,
+	}, nil
+}
+
+// Publish inserta los eventos en el outbox. Si ctx pertenece a una sesión con
+// transacción activa, la inserción forma parte de esa transacción.
+func (s *mongoOutboxStore) Publish(ctx context.Context, evts ...events.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	// createdAt se guarda con precisión de milisegundos y es igual para todo
+	// el lote; sequence conserva el orden en nanosegundos
+	now := time.Now().UTC()
+	docs := make([]interface{}, 0, len(evts))
+	for i, evt := range evts {
+		docs = append(docs, outboxDocument{
+			ID:            evt.ID,
+			Topic:         evt.Topic,
+			Type:          evt.Type,
+			Version:       evt.Version,
+			Key:           evt.Key,
+			OccurredAt:    evt.OccurredAt,
+			Payload:       string(evt.Payload),
+			Sequence:      now.UnixNano() + int64(i),
+			Status:        outbox.StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	_, err := s.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// Claim reserva registros pendientes adelantando su nextAttemptAt, lo que
+// funciona como un lock con vencimiento entre réplicas del relay. Un registro
+// sólo se reserva si su clave no tiene registros anteriores sin publicar
+// fuera de los ya reservados en este lote; como los reservados por otra
+// réplica siguen pendientes, dos réplicas no publican la misma clave a la vez.
+func (s *mongoOutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]outbox.Record, error) {
+	now := time.Now().UTC()
+	cursor, err := s.collection.Find(ctx, bson.M{
+		"status":        outbox.StatusPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}, options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}, {Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blocked := make(map[string]bool)
+	// claimed guarda los ids reservados por clave; nunca es nil porque $nin
+	// requiere un array
+	claimed := make(map[string][]string)
+	var records []outbox.Record
+	for len(records) < limit && cursor.Next(ctx) {
+		var doc outboxDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return records, err
+		}
+		if blocked[doc.Key] {
+			continue
+		}
+		if claimed[doc.Key] == nil {
+			claimed[doc.Key] = []string{}
+		}
+
+		// Un registro anterior de la misma clave pendiente, reservado por otra
+		// réplica o en dead-letter bloquea la clave
+		err := s.collection.FindOne(ctx, bson.M{
+			"key":      doc.Key,
+			"status":   bson.M{"$ne": outbox.StatusPublished},
+			"sequence": bson.M{"$lt": doc.Sequence},
+			"_id":      bson.M{"$nin": claimed[doc.Key]},
+		}).Err()
+		if err == nil {
+			blocked[doc.Key] = true
+			continue
+		}
+		if err != mongo.ErrNoDocuments {
+			return records, err
+		}
+
+		result, err := s.collection.UpdateOne(ctx, bson.M{
+			"_id":           doc.ID,
+			"status":        outbox.StatusPending,
+			"nextAttemptAt": bson.M{"$lte": now},
+		}, bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}})
+		if err != nil {
+			return records, err
+		}
+		if result.ModifiedCount == 0 {
+			// Otra réplica lo reservó primero
+			blocked[doc.Key] = true
+			continue
+		}
+
+		doc.NextAttemptAt = now.Add(lease)
+		claimed[doc.Key] = append(claimed[doc.Key], doc.ID)
+		records = append(records, doc.toRecord())
+	}
+
+	return records, cursor.Err()
+}
+
+// MarkPublished marca un registro como entregado
+func (s *mongoOutboxStore) MarkPublished(ctx context.Context, id string) error {
+	return s.update(ctx, id, bson.M{
+		"status":      outbox.StatusPublished,
+		"publishedAt": time.Now().UTC(),
+	})
+}
+
+// MarkFailed registra un intento fallido y reprograma el registro
+func (s *mongoOutboxStore) MarkFailed(ctx context.Context, id string, attempts int, retryAt time.Time, reason string) error {
+	return s.update(ctx, id, bson.M{
+		"attempts":      attempts,
+		"nextAttemptAt": retryAt.UTC(),
+		"lastError":     reason,
+	})
+}
+
+// MarkDead mueve un registro al estado de dead-letter
+func (s *mongoOutboxStore) MarkDead(ctx context.Context, id string, attempts int, reason string) error {
+	return s.update(ctx, id, bson.M{
+		"status":    outbox.StatusDead,
+		"attempts":  attempts,
+		"lastError": reason,
+	})
+}
+
+func (s *mongoOutboxStore) update(ctx context.Context, id string, set bson.M) error {
+	result, err := s.collection.UpdateByID(ctx, id, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return outbox.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (d outboxDocument) toRecord() outbox.Record {
+	return outbox.Record{
+		Event: events.Event{
+			ID:         d.ID,
+			Type:       d.Type,
+			Version:    d.Version,
+			Topic:      d.Topic,
+			Key:        d.Key,
+			OccurredAt: d.OccurredAt,
+			Payload:    []byte(d.Payload),
+		},
+		Sequence:      d.Sequence,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     d.LastError,
+		CreatedAt:     d.CreatedAt,
+		PublishedAt:   d.PublishedAt,
+	}
+}
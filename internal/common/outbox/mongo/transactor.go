@@ -0,0 +1,33 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongoTransactor implementa outbox.Transactor con transacciones de MongoDB.
+// Requiere que MongoDB corra como replica set.
+type mongoTransactor struct {
+	client *mongo.Client
+}
+
+func NewMongoTransactor(client *mongo.Client) *mongoTransactor {
+	return &mongoTransactor{client: client}
+}
+
+// WithinTransaction ejecuta fn dentro de una transacción. El driver reintenta
+// la transacción completa ante errores transitorios, por lo que fn debe ser
+// idempotente respecto de su propio estado.
+func (t *mongoTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
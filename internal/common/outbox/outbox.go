@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+)
+
+// Estados posibles de un registro del outbox
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+	StatusDead      = "dead"
+)
+
+// Record es un evento almacenado en el outbox junto con su estado de entrega
+type Record struct {
+	Event events.Event
+	// Sequence ordena los registros según se escribieron, incluidos los de una
+	// misma transacción, que comparten CreatedAt
+	Sequence      int64
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	PublishedAt   time.Time
+}
+
+// Store define la interfaz del almacenamiento del outbox usada por el relay.
+// La escritura de eventos se realiza a través de events.Publisher, dentro de
+// la misma transacción que la entidad.
+type Store interface {
+	// Claim reserva hasta limit registros pendientes durante lease, de modo
+	// que otras réplicas del relay no los procesen en paralelo. Para mantener
+	// el orden por clave omite los registros cuya clave tiene otro anterior
+	// sin publicar, ya sea reservado, esperando un reintento o en
+	// dead-letter, salvo que ese anterior se reserve en el mismo lote.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Record, error)
+	// MarkPublished marca un registro como entregado
+	MarkPublished(ctx context.Context, id string) error
+	// MarkFailed registra un intento fallido y reprograma el registro para retryAt
+	MarkFailed(ctx context.Context, id string, attempts int, retryAt time.Time, reason string) error
+	// MarkDead mueve un registro al estado de dead-letter
+	MarkDead(ctx context.Context, id string, attempts int, reason string) error
+}
+
+// Transactor ejecuta una función dentro de una transacción. El contexto
+// recibido por fn debe propagarse a los repositorios para que sus escrituras
+// formen parte de la transacción.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NopTransactor ejecuta la función sin transacción. Útil en tests.
+type NopTransactor struct{}
+
+// WithinTransaction ejecuta fn directamente
+func (NopTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+)
+
+// RelayConfig agrupa los parámetros del relay
+type RelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+// DefaultRelayConfig devuelve la configuración por defecto del relay
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		BatchSize:    100,
+		PollInterval: 500 * time.Millisecond,
+		Lease:        30 * time.Second,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// Relay drena el outbox hacia el publisher de eventos con entrega
+// at-least-once: un evento se marca como publicado sólo después de que el
+// publisher lo confirma, por lo que los consumidores deben ser idempotentes.
+type Relay struct {
+	store     Store
+	publisher events.Publisher
+	cfg       RelayConfig
+}
+
+// NewRelay crea una nueva instancia de Relay
+func NewRelay(store Store, publisher events.Publisher, cfg RelayConfig) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+}
+
+// Run procesa el outbox periódicamente hasta que se cancele el contexto
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Vaciar el outbox mientras haya lotes completos
+		for {
+			n, err := r.Flush(ctx)
+			if err != nil {
+				logger.Error("outbox relay: " + err.Error())
+			}
+			if err != nil || n < r.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush procesa un lote de registros pendientes y devuelve cuántos se
+// reservaron. Si una publicación falla el lote se detiene, porque los
+// registros siguientes pueden compartir clave con el fallido y no deben
+// adelantarlo; los que quedan sin procesar vuelven a reservarse al vencer el
+// lease.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	records, err := r.store.Claim(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range records {
+		published, err := r.deliver(ctx, record)
+		if err != nil {
+			return len(records), err
+		}
+		if !published {
+			break
+		}
+	}
+
+	return len(records), nil
+}
+
+// deliver publica un registro e indica si se publicó
+func (r *Relay) deliver(ctx context.Context, record Record) (bool, error) {
+	publishErr := r.publisher.Publish(ctx, record.Event)
+	if publishErr == nil {
+		return true, r.store.MarkPublished(ctx, record.Event.ID)
+	}
+
+	attempts := record.Attempts + 1
+	if attempts >= r.cfg.MaxAttempts {
+		logger.Error(fmt.Sprintf("outbox relay: event %s moved to dead-letter after %d attempts: %v", record.Event.ID, attempts, publishErr))
+		return false, r.store.MarkDead(ctx, record.Event.ID, attempts, publishErr.Error())
+	}
+
+	return false, r.store.MarkFailed(ctx, record.Event.ID, attempts, time.Now().Add(r.backoff(attempts)), publishErr.Error())
+}
+
+// backoff calcula la espera exponencial para el intento indicado
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,197 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEvent(t *testing.T) events.Event {
+	return newTestEventWithKey(t, "testuser")
+}
+
+func newTestEventWithKey(t *testing.T, key string) events.Event {
+	evt, err := events.New(events.TopicTweets, events.TweetCreated, events.TweetCreatedVersion, key, events.TweetCreatedPayload{TweetID: "1"})
+	assert.NoError(t, err)
+	return evt
+}
+
+func TestRelay_Flush(t *testing.T) {
+	tests := []struct {
+		name             string
+		maxAttempts      int
+		publishErr       error
+		expectedStatus   string
+		expectedAttempts int
+		expectedRetry    bool
+	}{
+		{
+			name:             "publishes pending event",
+			maxAttempts:      3,
+			expectedStatus:   StatusPublished,
+			expectedAttempts: 0,
+		},
+		{
+			name:             "schedules retry with backoff on publish error",
+			maxAttempts:      3,
+			publishErr:       errors.New("broker unavailable"),
+			expectedStatus:   StatusPending,
+			expectedAttempts: 1,
+			expectedRetry:    true,
+		},
+		{
+			name:             "moves event to dead-letter after max attempts",
+			maxAttempts:      1,
+			publishErr:       errors.New("broker unavailable"),
+			expectedStatus:   StatusDead,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			publisher := events.NewMemoryPublisher()
+			if tt.publishErr != nil {
+				publisher.FailWith(tt.publishErr)
+			}
+
+			evt := newTestEvent(t)
+			assert.NoError(t, store.Publish(context.Background(), evt))
+
+			cfg := DefaultRelayConfig()
+			cfg.MaxAttempts = tt.maxAttempts
+			relay := NewRelay(store, publisher, cfg)
+
+			start := time.Now()
+			n, err := relay.Flush(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			record, ok := store.Record(evt.ID)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedStatus, record.Status)
+			assert.Equal(t, tt.expectedAttempts, record.Attempts)
+			if tt.publishErr != nil {
+				assert.Equal(t, tt.publishErr.Error(), record.LastError)
+			} else {
+				assert.Equal(t, []events.Event{evt}, publisher.Events())
+			}
+			if tt.expectedRetry {
+				assert.WithinDuration(t, start.Add(cfg.BaseBackoff), record.NextAttemptAt, 100*time.Millisecond)
+			}
+
+			// Un segundo flush no vuelve a procesar el registro
+			n, err = relay.Flush(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 0, n)
+		})
+	}
+}
+
+func TestRelay_Flush_StopsBatchOnPublishError(t *testing.T) {
+	store := NewMemoryStore()
+	publisher := events.NewMemoryPublisher()
+	publisher.FailWith(errors.New("broker unavailable"))
+
+	first := newTestEventWithKey(t, "alice")
+	second := newTestEventWithKey(t, "bob")
+	assert.NoError(t, store.Publish(context.Background(), first, second))
+
+	relay := NewRelay(store, publisher, DefaultRelayConfig())
+	n, err := relay.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	record, _ := store.Record(first.ID)
+	assert.Equal(t, 1, record.Attempts)
+	// El segundo registro no se intenta y queda reservado hasta que venza el lease
+	record, _ = store.Record(second.ID)
+	assert.Equal(t, 0, record.Attempts)
+	assert.Equal(t, StatusPending, record.Status)
+}
+
+func TestMemoryStore_Claim_KeepsKeyOrder(t *testing.T) {
+	ctx := context.Background()
+	lease := time.Minute
+
+	tests := []struct {
+		name     string
+		setup    func(store *MemoryStore, first events.Event)
+		expected func(first, second, other events.Event) []string
+	}{
+		{
+			name:  "claims records of the same key in order within a batch",
+			setup: func(store *MemoryStore, first events.Event) {},
+			expected: func(first, second, other events.Event) []string {
+				return []string{first.ID, second.ID, other.ID}
+			},
+		},
+		{
+			name: "skips records behind a failed one",
+			setup: func(store *MemoryStore, first events.Event) {
+				assert.NoError(t, store.MarkFailed(ctx, first.ID, 1, time.Now().Add(time.Hour), "broker unavailable"))
+			},
+			expected: func(first, second, other events.Event) []string {
+				return []string{other.ID}
+			},
+		},
+		{
+			name: "skips records behind a dead one",
+			setup: func(store *MemoryStore, first events.Event) {
+				assert.NoError(t, store.MarkDead(ctx, first.ID, 10, "broker unavailable"))
+			},
+			expected: func(first, second, other events.Event) []string {
+				return []string{other.ID}
+			},
+		},
+		{
+			name: "skips records behind one claimed by another relay",
+			setup: func(store *MemoryStore, first events.Event) {
+				claimed, err := store.Claim(ctx, 1, lease)
+				assert.NoError(t, err)
+				assert.Len(t, claimed, 1)
+			},
+			expected: func(first, second, other events.Event) []string {
+				return []string{other.ID}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			first := newTestEventWithKey(t, "alice")
+			second := newTestEventWithKey(t, "alice")
+			other := newTestEventWithKey(t, "bob")
+			assert.NoError(t, store.Publish(ctx, first, second, other))
+			tt.setup(store, first)
+
+			records, err := store.Claim(ctx, 10, lease)
+			assert.NoError(t, err)
+
+			var ids []string
+			for _, record := range records {
+				ids = append(ids, record.Event.ID)
+			}
+			assert.Equal(t, tt.expected(first, second, other), ids)
+		})
+	}
+}
+
+func TestRelay_Backoff(t *testing.T) {
+	cfg := DefaultRelayConfig()
+	cfg.BaseBackoff = time.Second
+	cfg.MaxBackoff = 10 * time.Second
+	relay := NewRelay(NewMemoryStore(), events.NewMemoryPublisher(), cfg)
+
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 2*time.Second, relay.backoff(2))
+	assert.Equal(t, 8*time.Second, relay.backoff(4))
+	assert.Equal(t, 10*time.Second, relay.backoff(5))
+	assert.Equal(t, 10*time.Second, relay.backoff(30))
+}
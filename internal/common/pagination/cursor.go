@@ -0,0 +1,58 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor se devuelve cuando un cursor no puede decodificarse
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor identifica una posición en un listado ordenado por fecha de
+// creación descendente y, ante fechas iguales, por id descendente
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode devuelve la representación opaca del cursor
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixMilli(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor decodifica un cursor generado por Encode
+func DecodeCursor(encoded string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	millis, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.UnixMilli(ms).UTC(), ID: id}, nil
+}
+
+// ParseCursor decodifica el cursor recibido como parámetro de una request.
+// Un valor vacío indica que se pide la primera página y devuelve nil.
+func ParseCursor(encoded string) (*Cursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	cursor, err := DecodeCursor(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,25 @@
+package pagination
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Límite por defecto y máximo de los listados paginados
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ParseLimit interpreta el límite recibido como parámetro de una request. Un
+// valor vacío devuelve defaultLimit; cualquier otro debe estar entre 1 y max.
+func ParseLimit(raw string, defaultLimit, max int) (int, error) {
+	if raw == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 || limit > max {
+		return 0, fmt.Errorf("limit must be between 1 and %d", max)
+	}
+	return limit, nil
+}
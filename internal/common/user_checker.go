@@ -2,13 +2,20 @@ package common
 
 import (
 	"context"
+	"errors"
 
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 )
 
+// ErrUserNotFound es el error de GetUser cuando el usuario no existe; los
+// demás errores son fallas al consultarlo.
+var ErrUserNotFound = errors.New("user does not exist")
+
 // UserChecker define la interfaz para verificar la existencia de un usuario
-// y obtener, además, la lista de usuarios a los que sigue.
+// y obtener, además, la lista de usuarios a los que sigue y que lo siguen.
 type UserChecker interface {
 	GetUser(username string) (*domain.User, error)
 	GetFollowings(ctx context.Context, username string) ([]string, error)
+	GetFollowers(ctx context.Context, username string) ([]string, error)
+	CountFollowers(ctx context.Context, username string) (int64, error)
 }
@@ -2,7 +2,6 @@ package common
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
@@ -38,7 +37,7 @@ func (a *UserCheckerAdapter) GetUser(username string) (*domain.User, error) {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user does not exist")
+		return nil, ErrUserNotFound
 	}
 
 	return user, nil
@@ -48,3 +47,13 @@ func (a *UserCheckerAdapter) GetUser(username string) (*domain.User, error) {
 func (a *UserCheckerAdapter) GetFollowings(ctx context.Context, username string) ([]string, error) {
 	return a.followRepo.GetFollowings(ctx, username)
 }
+
+// GetFollowers obtiene los usuarios que siguen a un usuario
+func (a *UserCheckerAdapter) GetFollowers(ctx context.Context, username string) ([]string, error) {
+	return a.followRepo.GetFollowers(ctx, username)
+}
+
+// CountFollowers obtiene la cantidad de seguidores de un usuario
+func (a *UserCheckerAdapter) CountFollowers(ctx context.Context, username string) (int64, error) {
+	return a.followRepo.CountFollowers(ctx, username)
+}
@@ -0,0 +1,419 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain/ports"
+	notificationsdomain "github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+)
+
+// HubConfig agrupa los límites del gateway
+type HubConfig struct {
+	// MaxConnections es la cantidad máxima de conexiones de la instancia
+	MaxConnections int
+	// MaxConnectionsPerUser es la cantidad máxima de conexiones de un usuario
+	MaxConnectionsPerUser int
+	// MaxSubscriptions es la cantidad máxima de tópicos por conexión
+	MaxSubscriptions int
+}
+
+// Hub registra las conexiones de una instancia del gateway y les reparte los
+// eventos del bus según los tópicos a los que están suscritas. Cada instancia
+// consume todos los eventos, por lo que una conexión recibe los suyos sin
+// importar a qué instancia esté conectada.
+type Hub struct {
+	userChecker common.UserChecker
+	likes       ports.LikeCountReader
+	cfg         HubConfig
+
+	mu      sync.Mutex
+	clients map[ports.Client]*clientState
+	// connections cuenta las conexiones de cada usuario
+	connections map[string]int
+	// topics indexa las conexiones suscritas a cada tópico público y, con la
+	// clave notificationsKey, a las notificaciones de cada usuario
+	topics map[string]map[ports.Client]struct{}
+	// timelines indexa, para cada autor, las conexiones suscritas al
+	// timeline de alguno de sus seguidores
+	timelines map[string]map[ports.Client]struct{}
+
+	draining bool
+	drained  chan struct{}
+}
+
+type clientState struct {
+	topics map[string]bool
+	// followings son las cuentas que sigue el usuario, cargadas al
+	// suscribirse al timeline
+	followings map[string]bool
+}
+
+func NewHub(userChecker common.UserChecker, likes ports.LikeCountReader, cfg HubConfig) *Hub {
+	return &Hub{
+		userChecker: userChecker,
+		likes:       likes,
+		cfg:         cfg,
+		clients:     make(map[ports.Client]*clientState),
+		connections: make(map[string]int),
+		topics:      make(map[string]map[ports.Client]struct{}),
+		timelines:   make(map[string]map[ports.Client]struct{}),
+		drained:     make(chan struct{}),
+	}
+}
+
+// notificationsKey es la clave de topics para las notificaciones de un usuario
+func notificationsKey(username string) string {
+	return domain.TopicNotifications + ":" + username
+}
+
+func (h *Hub) Connect(ctx context.Context, client ports.Client) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.draining {
+		return domain.ErrShuttingDown
+	}
+	if len(h.clients) >= h.cfg.MaxConnections || h.connections[client.Username()] >= h.cfg.MaxConnectionsPerUser {
+		return domain.ErrTooManyConnections
+	}
+
+	h.clients[client] = &clientState{topics: make(map[string]bool)}
+	h.connections[client.Username()]++
+	return nil
+}
+
+func (h *Hub) Disconnect(client ports.Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(client)
+}
+
+// Subscribe carga las cuentas seguidas al suscribirse al timeline; a partir
+// de ahí se mantienen con los eventos de follows del usuario
+func (h *Hub) Subscribe(ctx context.Context, client ports.Client, topic string) error {
+	if err := domain.ValidateTopic(topic); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	state, ok := h.clients[client]
+	switch {
+	case !ok || state.topics[topic]:
+		h.mu.Unlock()
+		return nil
+	case len(state.topics) >= h.cfg.MaxSubscriptions:
+		h.mu.Unlock()
+		return domain.ErrTooManySubscriptions
+	}
+	h.mu.Unlock()
+
+	var followings []string
+	if topic == domain.TopicTimeline {
+		var err error
+		followings, err = h.userChecker.GetFollowings(ctx, client.Username())
+		if err != nil {
+			return err
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// La conexión pudo cerrarse mientras se leían las cuentas seguidas
+	state, ok = h.clients[client]
+	if !ok || state.topics[topic] {
+		return nil
+	}
+	state.topics[topic] = true
+
+	switch topic {
+	case domain.TopicTimeline:
+		state.followings = make(map[string]bool, len(followings))
+		for _, following := range followings {
+			state.followings[following] = true
+			addIndex(h.timelines, following, client)
+		}
+	case domain.TopicNotifications:
+		addIndex(h.topics, notificationsKey(client.Username()), client)
+	default:
+		addIndex(h.topics, topic, client)
+	}
+	return nil
+}
+
+func (h *Hub) Unsubscribe(client ports.Client, topic string) error {
+	if err := domain.ValidateTopic(topic); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.clients[client]
+	if !ok || !state.topics[topic] {
+		return nil
+	}
+	h.unsubscribeLocked(client, state, topic)
+	return nil
+}
+
+// Drain cierra todas las conexiones y espera a que se den de baja o a que
+// venza el contexto. Desde que se llama no se aceptan conexiones nuevas.
+func (h *Hub) Drain(ctx context.Context) error {
+	h.mu.Lock()
+	if !h.draining {
+		h.draining = true
+		if len(h.clients) == 0 {
+			close(h.drained)
+		}
+	}
+	clients := make([]ports.Client, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.Unlock()
+
+	for _, client := range clients {
+		client.Close(domain.ErrShuttingDown)
+	}
+
+	select {
+	case <-h.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HandleTweetCreated envía el tweet a los timelines de los seguidores del
+// autor y a los suscritos al autor, y la respuesta o las menciones a las
+// notificaciones de los usuarios involucrados
+func (h *Hub) HandleTweetCreated(ctx context.Context, evt events.Event) error {
+	var payload events.TweetCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sendLocked(h.timelines[payload.Username], domain.ServerMessage{
+		Type:  domain.MessageEvent,
+		Topic: domain.TopicTimeline,
+		Event: evt.Type,
+		Data:  evt.Payload,
+	})
+	h.sendTopicLocked(domain.UserTopic(payload.Username), evt.Type, evt.Payload)
+
+	activities := notificationsdomain.TweetActivities(payload.Username, payload.TweetID, payload.InReplyToUsername, payload.Mentions, payload.CreatedAt)
+	for _, activity := range activities {
+		h.notifyLocked(activity)
+	}
+	return nil
+}
+
+// HandleTweetLiked envía la nueva cantidad de likes a los suscritos al tweet
+// y la notificación al autor
+func (h *Hub) HandleTweetLiked(ctx context.Context, evt events.Event) error {
+	var payload events.TweetLikedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	if err := h.sendLikeCount(ctx, payload.TweetID); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.notifyLocked(notificationsdomain.Activity{
+		Recipient:  payload.Author,
+		Actor:      payload.Username,
+		Type:       notificationsdomain.TypeLike,
+		TweetID:    payload.TweetID,
+		OccurredAt: payload.CreatedAt,
+	})
+	return nil
+}
+
+// HandleTweetUnliked envía la nueva cantidad de likes a los suscritos al tweet
+func (h *Hub) HandleTweetUnliked(ctx context.Context, evt events.Event) error {
+	var payload events.TweetUnlikedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return h.sendLikeCount(ctx, payload.TweetID)
+}
+
+// HandleUserFollowed actualiza las cuentas seguidas de los timelines
+// suscritos del seguidor y envía el follow a los suscritos a ambos usuarios y
+// la notificación al seguido
+func (h *Hub) HandleUserFollowed(ctx context.Context, evt events.Event) error {
+	var payload events.UserFollowedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.updateFollowingLocked(payload.Username, payload.Following, true)
+	h.sendTopicLocked(domain.UserTopic(payload.Username), evt.Type, evt.Payload)
+	h.sendTopicLocked(domain.UserTopic(payload.Following), evt.Type, evt.Payload)
+	h.notifyLocked(notificationsdomain.Activity{
+		Recipient:  payload.Following,
+		Actor:      payload.Username,
+		Type:       notificationsdomain.TypeFollow,
+		OccurredAt: payload.CreatedAt,
+	})
+	return nil
+}
+
+// HandleUserUnfollowed actualiza las cuentas seguidas de los timelines
+// suscritos del usuario y envía el unfollow a los suscritos a ambos usuarios
+func (h *Hub) HandleUserUnfollowed(ctx context.Context, evt events.Event) error {
+	var payload events.UserUnfollowedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.updateFollowingLocked(payload.Username, payload.Following, false)
+	h.sendTopicLocked(domain.UserTopic(payload.Username), evt.Type, evt.Payload)
+	h.sendTopicLocked(domain.UserTopic(payload.Following), evt.Type, evt.Payload)
+	return nil
+}
+
+// sendLikeCount lee la cantidad de likes sólo si alguien está suscrito al
+// tweet. Los tweets eliminados se ignoran.
+func (h *Hub) sendLikeCount(ctx context.Context, tweetID string) error {
+	topic := domain.TweetTopic(tweetID)
+
+	h.mu.Lock()
+	subscribed := len(h.topics[topic]) > 0
+	h.mu.Unlock()
+	if !subscribed {
+		return nil
+	}
+
+	count, err := h.likes.GetLikeCount(ctx, tweetID)
+	if errors.Is(err, domain.ErrTweetNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sendTopicLocked(topic, domain.EventLikeCount, domain.LikeCountData{TweetID: tweetID, LikeCount: count})
+	return nil
+}
+
+func (h *Hub) updateFollowingLocked(username, following string, follows bool) {
+	for client, state := range h.clients {
+		if client.Username() != username || !state.topics[domain.TopicTimeline] {
+			continue
+		}
+		if follows {
+			state.followings[following] = true
+			addIndex(h.timelines, following, client)
+		} else {
+			delete(state.followings, following)
+			removeIndex(h.timelines, following, client)
+		}
+	}
+}
+
+func (h *Hub) notifyLocked(activity notificationsdomain.Activity) {
+	if !activity.Notifies() {
+		return
+	}
+	h.sendLocked(h.topics[notificationsKey(activity.Recipient)], domain.ServerMessage{
+		Type:  domain.MessageEvent,
+		Topic: domain.TopicNotifications,
+		Event: domain.EventNotification,
+		Data: domain.NotificationData{
+			Type:       string(activity.Type),
+			Actor:      activity.Actor,
+			TweetID:    activity.TweetID,
+			OccurredAt: activity.OccurredAt,
+		},
+	})
+}
+
+func (h *Hub) sendTopicLocked(topic, event string, data any) {
+	h.sendLocked(h.topics[topic], domain.ServerMessage{
+		Type:  domain.MessageEvent,
+		Topic: topic,
+		Event: event,
+		Data:  data,
+	})
+}
+
+// sendLocked encola el mensaje en cada conexión. Las conexiones con la cola
+// llena se cierran para no demorar al resto; el cliente debe reconectarse.
+func (h *Hub) sendLocked(clients map[ports.Client]struct{}, msg domain.ServerMessage) {
+	for client := range clients {
+		if !client.Send(msg) {
+			client.Close(domain.ErrSlowClient)
+			h.removeLocked(client)
+		}
+	}
+}
+
+func (h *Hub) unsubscribeLocked(client ports.Client, state *clientState, topic string) {
+	delete(state.topics, topic)
+	switch topic {
+	case domain.TopicTimeline:
+		for following := range state.followings {
+			removeIndex(h.timelines, following, client)
+		}
+		state.followings = nil
+	case domain.TopicNotifications:
+		removeIndex(h.topics, notificationsKey(client.Username()), client)
+	default:
+		removeIndex(h.topics, topic, client)
+	}
+}
+
+func (h *Hub) removeLocked(client ports.Client) {
+	state, ok := h.clients[client]
+	if !ok {
+		return
+	}
+	for topic := range state.topics {
+		h.unsubscribeLocked(client, state, topic)
+	}
+	delete(h.clients, client)
+
+	username := client.Username()
+	if h.connections[username]--; h.connections[username] == 0 {
+		delete(h.connections, username)
+	}
+
+	if h.draining && len(h.clients) == 0 {
+		close(h.drained)
+	}
+}
+
+func addIndex(index map[string]map[ports.Client]struct{}, key string, client ports.Client) {
+	if index[key] == nil {
+		index[key] = make(map[ports.Client]struct{})
+	}
+	index[key][client] = struct{}{}
+}
+
+func removeIndex(index map[string]map[ports.Client]struct{}, key string, client ports.Client) {
+	delete(index[key], client)
+	if len(index[key]) == 0 {
+		delete(index, key)
+	}
+}
@@ -0,0 +1,269 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+	usersdomain "github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUserChecker struct {
+	mock.Mock
+}
+
+func (m *mockUserChecker) GetUser(username string) (*usersdomain.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usersdomain.User), args.Error(1)
+}
+
+func (m *mockUserChecker) GetFollowings(ctx context.Context, username string) ([]string, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserChecker) GetFollowers(ctx context.Context, username string) ([]string, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserChecker) CountFollowers(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type mockLikeCountReader struct {
+	mock.Mock
+}
+
+func (m *mockLikeCountReader) GetLikeCount(ctx context.Context, tweetID string) (int64, error) {
+	args := m.Called(ctx, tweetID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// fakeClient guarda los mensajes recibidos; con capacity 0 no tiene límite
+type fakeClient struct {
+	username string
+	capacity int
+	messages []domain.ServerMessage
+	closed   error
+}
+
+func (c *fakeClient) Username() string { return c.username }
+
+func (c *fakeClient) Send(msg domain.ServerMessage) bool {
+	if c.capacity > 0 && len(c.messages) == c.capacity {
+		return false
+	}
+	c.messages = append(c.messages, msg)
+	return true
+}
+
+func (c *fakeClient) Close(reason error) { c.closed = reason }
+
+// events devuelve los nombres de los eventos recibidos con su tópico
+func (c *fakeClient) events() []string {
+	var names []string
+	for _, msg := range c.messages {
+		names = append(names, msg.Topic+" "+msg.Event)
+	}
+	return names
+}
+
+var testHubConfig = HubConfig{MaxConnections: 10, MaxConnectionsPerUser: 2, MaxSubscriptions: 3}
+
+func newTestEvent(t *testing.T, eventType string, payload any) events.Event {
+	evt, err := events.New(events.TopicTweets, eventType, 1, "key", payload)
+	assert.NoError(t, err)
+	return evt
+}
+
+func TestHub_Connect(t *testing.T) {
+	hub := NewHub(new(mockUserChecker), new(mockLikeCountReader), testHubConfig)
+	ctx := context.Background()
+
+	assert.NoError(t, hub.Connect(ctx, &fakeClient{username: "alice"}))
+	assert.NoError(t, hub.Connect(ctx, &fakeClient{username: "alice"}))
+	assert.Equal(t, domain.ErrTooManyConnections, hub.Connect(ctx, &fakeClient{username: "alice"}))
+
+	bob := &fakeClient{username: "bob"}
+	assert.NoError(t, hub.Connect(ctx, bob))
+	hub.Disconnect(bob)
+	assert.NoError(t, hub.Connect(ctx, bob))
+
+	full := NewHub(new(mockUserChecker), new(mockLikeCountReader), HubConfig{MaxConnections: 1, MaxConnectionsPerUser: 1})
+	assert.NoError(t, full.Connect(ctx, &fakeClient{username: "alice"}))
+	assert.Equal(t, domain.ErrTooManyConnections, full.Connect(ctx, &fakeClient{username: "bob"}))
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	hub := NewHub(new(mockUserChecker), new(mockLikeCountReader), testHubConfig)
+	ctx := context.Background()
+	client := &fakeClient{username: "alice"}
+	assert.NoError(t, hub.Connect(ctx, client))
+
+	assert.Equal(t, domain.ErrInvalidTopic, hub.Subscribe(ctx, client, "everything"))
+	assert.Equal(t, domain.ErrInvalidTopic, hub.Subscribe(ctx, client, "tweet:"))
+	assert.NoError(t, hub.Subscribe(ctx, client, "tweet:1"))
+	assert.NoError(t, hub.Subscribe(ctx, client, "tweet:1"))
+	assert.NoError(t, hub.Subscribe(ctx, client, "tweet:2"))
+	assert.NoError(t, hub.Subscribe(ctx, client, "user:bob"))
+	assert.Equal(t, domain.ErrTooManySubscriptions, hub.Subscribe(ctx, client, "tweet:3"))
+
+	assert.NoError(t, hub.Unsubscribe(client, "tweet:2"))
+	assert.NoError(t, hub.Subscribe(ctx, client, "tweet:3"))
+}
+
+func TestHub_Timeline(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	checker := new(mockUserChecker)
+	checker.On("GetFollowings", mock.Anything, "alice").Return([]string{"bob"}, nil)
+
+	hub := NewHub(checker, new(mockLikeCountReader), testHubConfig)
+	alice := &fakeClient{username: "alice"}
+	assert.NoError(t, hub.Connect(ctx, alice))
+	assert.NoError(t, hub.Subscribe(ctx, alice, domain.TopicTimeline))
+
+	tweet := func(id, author string) events.Event {
+		return newTestEvent(t, events.TweetCreated, events.TweetCreatedPayload{TweetID: id, Username: author, CreatedAt: now})
+	}
+
+	assert.NoError(t, hub.HandleTweetCreated(ctx, tweet("1", "bob")))
+	assert.NoError(t, hub.HandleTweetCreated(ctx, tweet("2", "carla")))
+
+	// Los follows del usuario actualizan las cuentas de su timeline
+	assert.NoError(t, hub.HandleUserFollowed(ctx, newTestEvent(t, events.UserFollowed,
+		events.UserFollowedPayload{Username: "alice", Following: "carla", CreatedAt: now})))
+	assert.NoError(t, hub.HandleUserUnfollowed(ctx, newTestEvent(t, events.UserUnfollowed,
+		events.UserUnfollowedPayload{Username: "alice", Following: "bob", UnfollowedAt: now})))
+
+	assert.NoError(t, hub.HandleTweetCreated(ctx, tweet("3", "bob")))
+	assert.NoError(t, hub.HandleTweetCreated(ctx, tweet("4", "carla")))
+
+	var ids []string
+	for _, msg := range alice.messages {
+		var payload events.TweetCreatedPayload
+		assert.NoError(t, json.Unmarshal(msg.Data.(json.RawMessage), &payload))
+		assert.Equal(t, domain.TopicTimeline, msg.Topic)
+		ids = append(ids, payload.TweetID)
+	}
+	assert.Equal(t, []string{"1", "4"}, ids)
+	checker.AssertExpectations(t)
+}
+
+func TestHub_Notifications(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	hub := NewHub(new(mockUserChecker), new(mockLikeCountReader), testHubConfig)
+	alice := &fakeClient{username: "alice"}
+	assert.NoError(t, hub.Connect(ctx, alice))
+	assert.NoError(t, hub.Subscribe(ctx, alice, domain.TopicNotifications))
+
+	// Respuesta que además menciona a alice: una sola notificación
+	assert.NoError(t, hub.HandleTweetCreated(ctx, newTestEvent(t, events.TweetCreated, events.TweetCreatedPayload{
+		TweetID: "2", Username: "bob", InReplyToTweetID: "1", InReplyToUsername: "alice", Mentions: []string{"alice"}, CreatedAt: now,
+	})))
+	// Las acciones propias no notifican
+	assert.NoError(t, hub.HandleTweetLiked(ctx, newTestEvent(t, events.TweetLiked, events.TweetLikedPayload{
+		TweetID: "1", Username: "alice", Author: "alice", CreatedAt: now,
+	})))
+	assert.NoError(t, hub.HandleTweetLiked(ctx, newTestEvent(t, events.TweetLiked, events.TweetLikedPayload{
+		TweetID: "1", Username: "bob", Author: "alice", CreatedAt: now,
+	})))
+	assert.NoError(t, hub.HandleUserFollowed(ctx, newTestEvent(t, events.UserFollowed, events.UserFollowedPayload{
+		Username: "bob", Following: "alice", CreatedAt: now,
+	})))
+
+	assert.Equal(t, []domain.ServerMessage{
+		{Type: domain.MessageEvent, Topic: domain.TopicNotifications, Event: domain.EventNotification, Data: domain.NotificationData{Type: "reply", Actor: "bob", TweetID: "2", OccurredAt: now}},
+		{Type: domain.MessageEvent, Topic: domain.TopicNotifications, Event: domain.EventNotification, Data: domain.NotificationData{Type: "like", Actor: "bob", TweetID: "1", OccurredAt: now}},
+		{Type: domain.MessageEvent, Topic: domain.TopicNotifications, Event: domain.EventNotification, Data: domain.NotificationData{Type: "follow", Actor: "bob", OccurredAt: now}},
+	}, alice.messages)
+}
+
+func TestHub_LikeCount(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	likes := new(mockLikeCountReader)
+	likes.On("GetLikeCount", mock.Anything, "1").Return(int64(5), nil).Once()
+
+	hub := NewHub(new(mockUserChecker), likes, testHubConfig)
+	alice := &fakeClient{username: "alice"}
+	assert.NoError(t, hub.Connect(ctx, alice))
+	assert.NoError(t, hub.Subscribe(ctx, alice, domain.TweetTopic("1")))
+
+	assert.NoError(t, hub.HandleTweetLiked(ctx, newTestEvent(t, events.TweetLiked, events.TweetLikedPayload{
+		TweetID: "1", Username: "bob", Author: "carla", CreatedAt: now,
+	})))
+	// Sin suscriptores no se lee el contador
+	assert.NoError(t, hub.HandleTweetUnliked(ctx, newTestEvent(t, events.TweetUnliked, events.TweetUnlikedPayload{
+		TweetID: "2", Username: "bob", Author: "carla", UnlikedAt: now,
+	})))
+
+	assert.Equal(t, []domain.ServerMessage{
+		{Type: domain.MessageEvent, Topic: "tweet:1", Event: domain.EventLikeCount, Data: domain.LikeCountData{TweetID: "1", LikeCount: 5}},
+	}, alice.messages)
+	likes.AssertExpectations(t)
+}
+
+func TestHub_ClosesSlowClients(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	hub := NewHub(new(mockUserChecker), new(mockLikeCountReader), testHubConfig)
+	slow := &fakeClient{username: "alice", capacity: 1}
+	fast := &fakeClient{username: "carla"}
+	for _, client := range []*fakeClient{slow, fast} {
+		assert.NoError(t, hub.Connect(ctx, client))
+		assert.NoError(t, hub.Subscribe(ctx, client, domain.UserTopic("bob")))
+	}
+
+	for _, id := range []string{"1", "2", "3"} {
+		assert.NoError(t, hub.HandleTweetCreated(ctx, newTestEvent(t, events.TweetCreated,
+			events.TweetCreatedPayload{TweetID: id, Username: "bob", CreatedAt: now})))
+	}
+
+	assert.Equal(t, domain.ErrSlowClient, slow.closed)
+	assert.Len(t, slow.messages, 1)
+	assert.Nil(t, fast.closed)
+	assert.Equal(t, []string{"user:bob TweetCreated", "user:bob TweetCreated", "user:bob TweetCreated"}, fast.events())
+}
+
+func TestHub_Drain(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub(new(mockUserChecker), new(mockLikeCountReader), testHubConfig)
+	alice := &fakeClient{username: "alice"}
+	assert.NoError(t, hub.Connect(ctx, alice))
+
+	drained := make(chan error)
+	go func() { drained <- hub.Drain(ctx) }()
+
+	// La conexión se da de baja al recibir el cierre
+	assert.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return hub.draining
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, domain.ErrShuttingDown, hub.Connect(ctx, &fakeClient{username: "bob"}))
+	hub.Disconnect(alice)
+
+	assert.NoError(t, <-drained)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	stuck := NewHub(new(mockUserChecker), new(mockLikeCountReader), testHubConfig)
+	assert.NoError(t, stuck.Connect(ctx, &fakeClient{username: "alice"}))
+	assert.Equal(t, context.DeadlineExceeded, stuck.Drain(timeout))
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidToken se devuelve cuando el token de la conexión no es válido o venció
+	ErrInvalidToken = errors.New("invalid or expired token")
+
+	// ErrTooManyConnections se devuelve cuando se alcanzó el límite de
+	// conexiones de la instancia o del usuario
+	ErrTooManyConnections = errors.New("too many connections")
+
+	// ErrTooManySubscriptions se devuelve cuando una conexión alcanzó el
+	// límite de tópicos suscritos
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+
+	// ErrUnknownAction se devuelve cuando un mensaje del cliente tiene una
+	// acción desconocida
+	ErrUnknownAction = errors.New("unknown action")
+
+	// ErrSlowClient cierra las conexiones que no leen los mensajes al ritmo
+	// en que se generan
+	ErrSlowClient = errors.New("client is too slow")
+
+	// ErrShuttingDown cierra las conexiones cuando la instancia se apaga
+	ErrShuttingDown = errors.New("server is shutting down")
+
+	// ErrTweetNotFound se devuelve cuando el tweet de un like ya no existe
+	ErrTweetNotFound = errors.New("tweet not found")
+)
+
+// Acciones que puede enviar el cliente
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// ClientMessage es un mensaje enviado por el cliente
+type ClientMessage struct {
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
+}
+
+// Tipos de mensajes que envía el gateway
+const (
+	MessageSubscribed   = "subscribed"
+	MessageUnsubscribed = "unsubscribed"
+	MessageEvent        = "event"
+	MessageError        = "error"
+)
+
+// Eventos propios del gateway; los demás usan el tipo del evento de dominio
+const (
+	EventNotification = "Notification"
+	EventLikeCount    = "LikeCount"
+)
+
+// ServerMessage es un mensaje enviado por el gateway. Los mensajes de tipo
+// event llevan el tópico en el que se recibieron, el nombre del evento y sus
+// datos.
+type ServerMessage struct {
+	Type  string `json:"type"`
+	Topic string `json:"topic,omitempty"`
+	Event string `json:"event,omitempty"`
+	Data  any    `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NotificationData son los datos de un evento Notification
+type NotificationData struct {
+	Type       string    `json:"type"`
+	Actor      string    `json:"actor"`
+	TweetID    string    `json:"tweetId,omitempty"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// LikeCountData son los datos de un evento LikeCount
+type LikeCountData struct {
+	TweetID   string `json:"tweetId"`
+	LikeCount int64  `json:"likeCount"`
+}
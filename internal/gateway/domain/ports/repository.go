@@ -0,0 +1,16 @@
+package ports
+
+import "context"
+
+// Authenticator define la interfaz para validar el token de una conexión
+type Authenticator interface {
+	// Authenticate devuelve el usuario del token o domain.ErrInvalidToken
+	Authenticate(token string) (string, error)
+}
+
+// LikeCountReader define la interfaz para leer la cantidad de likes de un tweet
+type LikeCountReader interface {
+	// GetLikeCount devuelve la cantidad de likes del tweet o
+	// domain.ErrTweetNotFound si no existe
+	GetLikeCount(ctx context.Context, tweetID string) (int64, error)
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+)
+
+// Client es una conexión abierta con el gateway
+type Client interface {
+	// Username devuelve el usuario autenticado de la conexión
+	Username() string
+
+	// Send encola un mensaje sin bloquear. Devuelve false si la cola de
+	// envío está llena.
+	Send(msg domain.ServerMessage) bool
+
+	// Close empieza a cerrar la conexión por el motivo indicado, sin bloquear
+	Close(reason error)
+}
+
+// Gateway define la interfaz para registrar conexiones y sus suscripciones
+type Gateway interface {
+	// Connect registra una conexión nueva, respetando los límites de conexiones
+	Connect(ctx context.Context, client Client) error
+
+	// Disconnect da de baja una conexión y todas sus suscripciones
+	Disconnect(client Client)
+
+	// Subscribe suscribe la conexión a un tópico
+	Subscribe(ctx context.Context, client Client, topic string) error
+
+	// Unsubscribe cancela la suscripción de la conexión a un tópico
+	Unsubscribe(client Client, topic string) error
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// Tópicos a los que puede suscribirse una conexión. timeline y notifications
+// se refieren siempre al usuario autenticado; tweet:<id> y user:<username>
+// son públicos.
+const (
+	// TopicTimeline recibe los tweets nuevos de las cuentas que sigue el usuario
+	TopicTimeline = "timeline"
+	// TopicNotifications recibe las actividades que le generan notificaciones
+	TopicNotifications = "notifications"
+
+	// tweetTopicPrefix recibe la cantidad de likes de un tweet cuando cambia
+	tweetTopicPrefix = "tweet:"
+	// userTopicPrefix recibe los tweets nuevos y los follows de un usuario
+	userTopicPrefix = "user:"
+)
+
+// ErrInvalidTopic se devuelve cuando una suscripción pide un tópico desconocido
+var ErrInvalidTopic = errors.New("invalid topic")
+
+// TweetTopic devuelve el tópico de los likes de un tweet
+func TweetTopic(tweetID string) string {
+	return tweetTopicPrefix + tweetID
+}
+
+// UserTopic devuelve el tópico de la actividad pública de un usuario
+func UserTopic(username string) string {
+	return userTopicPrefix + username
+}
+
+// ValidateTopic verifica que el tópico sea uno de los admitidos
+func ValidateTopic(topic string) error {
+	switch {
+	case topic == TopicTimeline, topic == TopicNotifications:
+		return nil
+	case strings.HasPrefix(topic, tweetTopicPrefix) && len(topic) > len(tweetTopicPrefix):
+		return nil
+	case strings.HasPrefix(topic, userTopicPrefix) && len(topic) > len(userTopicPrefix):
+		return nil
+	}
+	return ErrInvalidTopic
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+)
+
+// HMACAuthenticator valida tokens firmados con HMAC-SHA256 con un secreto
+// compartido con el servicio que los emite. Un token tiene la forma
+// <username>.<vencimiento en segundos unix>.<firma>, con la firma en base64
+// url sobre <username>.<vencimiento>.
+type HMACAuthenticator struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewHMACAuthenticator crea un autenticador con el secreto indicado
+func NewHMACAuthenticator(secret string) *HMACAuthenticator {
+	return &HMACAuthenticator{
+		secret: []byte(secret),
+		now:    time.Now,
+	}
+}
+
+// Sign emite un token para el usuario que vence en expiresAt
+func (a *HMACAuthenticator) Sign(username string, expiresAt time.Time) string {
+	claims := username + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return claims + "." + a.signature(claims)
+}
+
+func (a *HMACAuthenticator) Authenticate(token string) (string, error) {
+	// La firma y el vencimiento no contienen puntos, por lo que se separan
+	// desde el final
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return "", domain.ErrInvalidToken
+	}
+	claims, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(a.signature(claims))) {
+		return "", domain.ErrInvalidToken
+	}
+
+	j := strings.LastIndex(claims, ".")
+	if j <= 0 {
+		return "", domain.ErrInvalidToken
+	}
+	expiresAt, err := strconv.ParseInt(claims[j+1:], 10, 64)
+	if err != nil || a.now().Unix() >= expiresAt {
+		return "", domain.ErrInvalidToken
+	}
+	return claims[:j], nil
+}
+
+func (a *HMACAuthenticator) signature(claims string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(claims))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
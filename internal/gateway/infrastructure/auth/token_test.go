@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMACAuthenticator_Authenticate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	authenticator := NewHMACAuthenticator("secret")
+	authenticator.now = func() time.Time { return now }
+
+	valid := authenticator.Sign("alice", now.Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		token         string
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "valid token",
+			token:    valid,
+			expected: "alice",
+		},
+		{
+			name:     "username with dots",
+			token:    authenticator.Sign("alice.smith", now.Add(time.Hour)),
+			expected: "alice.smith",
+		},
+		{
+			name:          "expired token",
+			token:         authenticator.Sign("alice", now.Add(-time.Second)),
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name:          "tampered username",
+			token:         "bob" + valid[len("alice"):],
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name:          "signed with another secret",
+			token:         NewHMACAuthenticator("other").Sign("alice", now.Add(time.Hour)),
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name:          "malformed token",
+			token:         "alice",
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name:          "empty token",
+			expectedError: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, err := authenticator.Authenticate(tt.token)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, username)
+		})
+	}
+}
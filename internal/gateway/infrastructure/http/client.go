@@ -0,0 +1,100 @@
+package http
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+)
+
+// wsClient es una conexión WebSocket. Todas las escrituras las hace
+// writePump, que envía los mensajes encolados, los pings y el cierre.
+type wsClient struct {
+	conn     *websocket.Conn
+	username string
+	send     chan domain.ServerMessage
+
+	closeOnce sync.Once
+	done      chan struct{}
+	// reason es el motivo del cierre; se escribe antes de cerrar done
+	reason error
+}
+
+func newWSClient(conn *websocket.Conn, username string) *wsClient {
+	return &wsClient{
+		conn:     conn,
+		username: username,
+		send:     make(chan domain.ServerMessage, sendBufferSize),
+		done:     make(chan struct{}),
+	}
+}
+
+func (c *wsClient) Username() string {
+	return c.username
+}
+
+func (c *wsClient) Send(msg domain.ServerMessage) bool {
+	select {
+	case <-c.done:
+		// Los mensajes a una conexión que se está cerrando se descartan
+		return true
+	default:
+	}
+
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *wsClient) Close(reason error) {
+	c.closeOnce.Do(func() {
+		c.reason = reason
+		close(c.done)
+	})
+}
+
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				c.Close(nil)
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.Close(nil)
+				return
+			}
+		case <-c.done:
+			code, text := closeCode(c.reason)
+			_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(writeWait))
+			return
+		}
+	}
+}
+
+// closeCode devuelve el código y el texto del mensaje de cierre según el motivo
+func closeCode(reason error) (int, string) {
+	switch reason {
+	case nil:
+		return websocket.CloseNormalClosure, ""
+	case domain.ErrShuttingDown:
+		return websocket.CloseGoingAway, reason.Error()
+	case domain.ErrSlowClient, domain.ErrTooManyConnections:
+		return websocket.CloseTryAgainLater, reason.Error()
+	default:
+		return websocket.CloseInternalServerErr, reason.Error()
+	}
+}
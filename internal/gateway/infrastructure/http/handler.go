@@ -0,0 +1,130 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain/ports"
+)
+
+const (
+	// writeWait es el tiempo máximo de cada escritura en la conexión
+	writeWait = 10 * time.Second
+
+	// pongWait es el tiempo máximo sin recibir mensajes ni pongs del cliente
+	pongWait = 60 * time.Second
+
+	// pingPeriod es cada cuánto se envía un ping; debe ser menor que pongWait
+	pingPeriod = pongWait * 9 / 10
+
+	// maxMessageSize es el tamaño máximo de un mensaje del cliente
+	maxMessageSize = 4096
+
+	// sendBufferSize es la cantidad de mensajes pendientes de enviar que admite
+	// una conexión antes de cerrarse por lenta
+	sendBufferSize = 256
+)
+
+type GatewayHandler struct {
+	gateway  ports.Gateway
+	auth     ports.Authenticator
+	upgrader websocket.Upgrader
+}
+
+func NewGatewayHandler(gateway ports.Gateway, auth ports.Authenticator) *GatewayHandler {
+	return &GatewayHandler{
+		gateway: gateway,
+		auth:    auth,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// La conexión se autentica con un token y no con cookies, por lo
+			// que se aceptan conexiones desde cualquier origen
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Connect autentica la conexión y la convierte en un WebSocket. Los
+// navegadores no permiten enviar headers al abrir un WebSocket, por lo que el
+// token también se acepta en el parámetro token.
+func (h *GatewayHandler) Connect(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token = c.Query("token")
+	}
+	username, err := h.auth.Authenticate(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// El upgrader ya respondió con el error
+		return
+	}
+
+	client := newWSClient(conn, username)
+	go client.writePump()
+
+	ctx := c.Request.Context()
+	if err := h.gateway.Connect(ctx, client); err != nil {
+		client.Close(err)
+		return
+	}
+	defer h.gateway.Disconnect(client)
+	defer client.Close(nil)
+
+	conn.SetReadLimit(maxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		// Un mensaje inválido se rechaza sin cerrar la conexión
+		var msg domain.ClientMessage
+		reply := domain.ServerMessage{Type: domain.MessageError, Error: "invalid message"}
+		if err := json.Unmarshal(data, &msg); err == nil {
+			reply = h.handleMessage(ctx, client, msg)
+		}
+		if !client.Send(reply) {
+			client.Close(domain.ErrSlowClient)
+			return
+		}
+	}
+}
+
+// handleMessage aplica la acción de un mensaje del cliente y devuelve la respuesta
+func (h *GatewayHandler) handleMessage(ctx context.Context, client *wsClient, msg domain.ClientMessage) domain.ServerMessage {
+	var err error
+	reply := domain.ServerMessage{Topic: msg.Topic}
+	switch msg.Action {
+	case domain.ActionSubscribe:
+		err = h.gateway.Subscribe(ctx, client, msg.Topic)
+		reply.Type = domain.MessageSubscribed
+	case domain.ActionUnsubscribe:
+		err = h.gateway.Unsubscribe(client, msg.Topic)
+		reply.Type = domain.MessageUnsubscribed
+	default:
+		err = domain.ErrUnknownAction
+	}
+	if err != nil {
+		reply.Type = domain.MessageError
+		reply.Error = err.Error()
+	}
+	return reply
+}
@@ -0,0 +1,40 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain"
+	"github.com/nicodelara/microblogging-uala/internal/gateway/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoLikeCountReader lee el contador de likes que el servicio de tweets
+// mantiene en cada tweet
+type mongoLikeCountReader struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLikeCountReader(client *mongo.Client, dbName, collName string) ports.LikeCountReader {
+	return &mongoLikeCountReader{
+		collection: client.Database(dbName).Collection(collName),
+	}
+}
+
+func (r *mongoLikeCountReader) GetLikeCount(ctx context.Context, tweetID string) (int64, error) {
+	var doc struct {
+		LikeCount int64 `bson:"likeCount"`
+	}
+	filter := bson.M{"_id": tweetID, "deletedAt": bson.M{"$exists": false}}
+	opts := options.FindOne().SetProjection(bson.M{"likeCount": 1})
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, domain.ErrTweetNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return doc.LikeCount, nil
+}
@@ -0,0 +1,7 @@
+package application
+
+import "errors"
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
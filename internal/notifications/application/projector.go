@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain/ports"
+)
+
+// NotificationProjector genera las notificaciones a partir de los eventos de
+// tweets y usuarios. Los usuarios no reciben notificaciones de sus propias
+// acciones. Sus handlers deben registrarse envueltos con inbox.Idempotent para
+// que una reentrega no cuente dos veces la misma actividad.
+type NotificationProjector struct {
+	repo ports.NotificationRepository
+}
+
+func NewNotificationProjector(repo ports.NotificationRepository) *NotificationProjector {
+	return &NotificationProjector{repo: repo}
+}
+
+// HandleTweetCreated notifica al autor del tweet respondido y a los usuarios
+// mencionados. Quien recibe la notificación de respuesta no recibe además la
+// de mención por el mismo tweet.
+func (p *NotificationProjector) HandleTweetCreated(ctx context.Context, evt events.Event) error {
+	var payload events.TweetCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	activities := domain.TweetActivities(payload.Username, payload.TweetID, payload.InReplyToUsername, payload.Mentions, payload.CreatedAt)
+	for _, activity := range activities {
+		if err := p.record(ctx, activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleTweetLiked notifica al autor del tweet likeado
+func (p *NotificationProjector) HandleTweetLiked(ctx context.Context, evt events.Event) error {
+	var payload events.TweetLikedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return p.record(ctx, domain.Activity{
+		Recipient:  payload.Author,
+		Actor:      payload.Username,
+		Type:       domain.TypeLike,
+		TweetID:    payload.TweetID,
+		OccurredAt: payload.CreatedAt,
+	})
+}
+
+// HandleUserFollowed notifica al usuario seguido
+func (p *NotificationProjector) HandleUserFollowed(ctx context.Context, evt events.Event) error {
+	var payload events.UserFollowedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return p.record(ctx, domain.Activity{
+		Recipient:  payload.Following,
+		Actor:      payload.Username,
+		Type:       domain.TypeFollow,
+		OccurredAt: payload.CreatedAt,
+	})
+}
+
+func (p *NotificationProjector) record(ctx context.Context, activity domain.Activity) error {
+	if !activity.Notifies() {
+		return nil
+	}
+	return p.repo.Record(ctx, activity)
+}
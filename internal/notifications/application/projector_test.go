@@ -0,0 +1,98 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/inbox"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationProjector(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   any
+		handle    func(*NotificationProjector) events.Handler
+		expected  []domain.Activity
+	}{
+		{
+			name:      "mentions notify each mentioned user",
+			eventType: events.TweetCreated,
+			payload:   events.TweetCreatedPayload{TweetID: "t1", Username: "ana", Mentions: []string{"beto", "carla"}, CreatedAt: now},
+			handle:    func(p *NotificationProjector) events.Handler { return p.HandleTweetCreated },
+			expected: []domain.Activity{
+				{Recipient: "beto", Actor: "ana", Type: domain.TypeMention, TweetID: "t1", OccurredAt: now},
+				{Recipient: "carla", Actor: "ana", Type: domain.TypeMention, TweetID: "t1", OccurredAt: now},
+			},
+		},
+		{
+			name:      "reply notifies the parent author once and skips self mentions",
+			eventType: events.TweetCreated,
+			payload: events.TweetCreatedPayload{
+				TweetID:           "t2",
+				Username:          "ana",
+				InReplyToTweetID:  "t1",
+				InReplyToUsername: "beto",
+				Mentions:          []string{"beto", "ana"},
+				CreatedAt:         now,
+			},
+			handle: func(p *NotificationProjector) events.Handler { return p.HandleTweetCreated },
+			expected: []domain.Activity{
+				{Recipient: "beto", Actor: "ana", Type: domain.TypeReply, TweetID: "t2", OccurredAt: now},
+			},
+		},
+		{
+			name:      "like notifies the tweet author",
+			eventType: events.TweetLiked,
+			payload:   events.TweetLikedPayload{LikeID: "l1", TweetID: "t1", Username: "beto", Author: "ana", CreatedAt: now},
+			handle:    func(p *NotificationProjector) events.Handler { return p.HandleTweetLiked },
+			expected: []domain.Activity{
+				{Recipient: "ana", Actor: "beto", Type: domain.TypeLike, TweetID: "t1", OccurredAt: now},
+			},
+		},
+		{
+			name:      "liking your own tweet does not notify",
+			eventType: events.TweetLiked,
+			payload:   events.TweetLikedPayload{LikeID: "l1", TweetID: "t1", Username: "ana", Author: "ana", CreatedAt: now},
+			handle:    func(p *NotificationProjector) events.Handler { return p.HandleTweetLiked },
+		},
+		{
+			name:      "follow notifies the followed user",
+			eventType: events.UserFollowed,
+			payload:   events.UserFollowedPayload{FollowID: "f1", Username: "beto", Following: "ana", CreatedAt: now},
+			handle:    func(p *NotificationProjector) events.Handler { return p.HandleUserFollowed },
+			expected: []domain.Activity{
+				{Recipient: "ana", Actor: "beto", Type: domain.TypeFollow, OccurredAt: now},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockNotificationRepository)
+			for _, activity := range tt.expected {
+				repo.On("Record", mock.Anything, activity).Return(nil).Once()
+			}
+
+			evt, err := events.New(events.TopicTweets, tt.eventType, 1, "key", tt.payload)
+			assert.NoError(t, err)
+
+			// Una reentrega del mismo evento no vuelve a registrar la actividad
+			projector := NewNotificationProjector(repo)
+			handler := inbox.Idempotent(outbox.NopTransactor{}, inbox.NewMemoryStore(), tt.handle(projector))
+			assert.NoError(t, handler(context.Background(), evt))
+			assert.NoError(t, handler(context.Background(), evt))
+
+			repo.AssertExpectations(t)
+			repo.AssertNumberOfCalls(t, "Record", len(tt.expected))
+		})
+	}
+}
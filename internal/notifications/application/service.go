@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain/ports"
+)
+
+type notificationService struct {
+	repo    ports.NotificationRepository
+	checker common.UserChecker
+}
+
+// NewNotificationService crea el servicio de lectura de notificaciones
+func NewNotificationService(repo ports.NotificationRepository, checker common.UserChecker) ports.NotificationService {
+	return &notificationService{
+		repo:    repo,
+		checker: checker,
+	}
+}
+
+func (s *notificationService) ListNotifications(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.NotificationPage, error) {
+	// Verificar que el usuario exista
+	_, err := s.checker.GetUser(username)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	notifications, err := s.repo.List(ctx, username, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.repo.CountUnread(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewNotificationPage(notifications, unread, limit), nil
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, username string) (int64, error) {
+	// Verificar que el usuario exista
+	_, err := s.checker.GetUser(username)
+	if err != nil {
+		return 0, ErrUserNotFound
+	}
+
+	return s.repo.CountUnread(ctx, username)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, username string, ids []string) error {
+	// Verificar que el usuario exista
+	_, err := s.checker.GetUser(username)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	return s.repo.MarkRead(ctx, username, ids)
+}
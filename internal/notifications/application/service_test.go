@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+	usersdomain "github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *mockNotificationRepository) Record(ctx context.Context, activity domain.Activity) error {
+	args := m.Called(ctx, activity)
+	return args.Error(0)
+}
+
+func (m *mockNotificationRepository) List(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Notification, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	return args.Get(0).([]domain.Notification), args.Error(1)
+}
+
+func (m *mockNotificationRepository) CountUnread(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockNotificationRepository) MarkRead(ctx context.Context, username string, ids []string) error {
+	args := m.Called(ctx, username, ids)
+	return args.Error(0)
+}
+
+type mockUserChecker struct {
+	mock.Mock
+}
+
+func (m *mockUserChecker) GetUser(username string) (*usersdomain.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usersdomain.User), args.Error(1)
+}
+
+func (m *mockUserChecker) GetFollowings(ctx context.Context, username string) ([]string, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserChecker) GetFollowers(ctx context.Context, username string) ([]string, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserChecker) CountFollowers(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestNotificationService_ListNotifications(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	notifications := []domain.Notification{
+		{ID: "n2", Username: "ana", Type: domain.TypeLike, TweetID: "t1", Actors: []string{"beto", "carla"}, ActorCount: 5, UpdatedAt: now},
+		{ID: "n1", Username: "ana", Type: domain.TypeFollow, Actors: []string{"beto"}, ActorCount: 1, Read: true, UpdatedAt: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name            string
+		limit           int
+		checkerSetup    func(*mockUserChecker)
+		repoSetup       func(*mockNotificationRepository)
+		expectedSummary []string
+		expectedUnread  int64
+		expectedNext    string
+		expectedError   error
+	}{
+		{
+			name:  "full page with summaries and unread count",
+			limit: 2,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "ana").Return(&usersdomain.User{Username: "ana"}, nil)
+			},
+			repoSetup: func(m *mockNotificationRepository) {
+				m.On("List", mock.Anything, "ana", (*pagination.Cursor)(nil), 2).Return(notifications, nil)
+				m.On("CountUnread", mock.Anything, "ana").Return(int64(1), nil)
+			},
+			expectedSummary: []string{"beto and 4 others liked your tweet", "beto followed you"},
+			expectedUnread:  1,
+			expectedNext:    notifications[1].Cursor().Encode(),
+		},
+		{
+			name:  "empty inbox",
+			limit: 20,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "ana").Return(&usersdomain.User{Username: "ana"}, nil)
+			},
+			repoSetup: func(m *mockNotificationRepository) {
+				m.On("List", mock.Anything, "ana", (*pagination.Cursor)(nil), 20).Return([]domain.Notification(nil), nil)
+				m.On("CountUnread", mock.Anything, "ana").Return(int64(0), nil)
+			},
+			expectedSummary: []string{},
+		},
+		{
+			name:  "user not found",
+			limit: 20,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "ana").Return(nil, errors.New("not found"))
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockNotificationRepository)
+			checker := new(mockUserChecker)
+			tt.checkerSetup(checker)
+			if tt.repoSetup != nil {
+				tt.repoSetup(repo)
+			}
+
+			service := NewNotificationService(repo, checker)
+			page, err := service.ListNotifications(context.Background(), "ana", nil, tt.limit)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, page)
+			} else {
+				assert.NoError(t, err)
+				summaries := make([]string, 0, len(page.Notifications))
+				for _, n := range page.Notifications {
+					summaries = append(summaries, n.Summary)
+				}
+				assert.Equal(t, tt.expectedSummary, summaries)
+				assert.Equal(t, tt.expectedUnread, page.UnreadCount)
+				assert.Equal(t, tt.expectedNext, page.NextCursor)
+			}
+
+			repo.AssertExpectations(t)
+			checker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	repo := new(mockNotificationRepository)
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "ana").Return(&usersdomain.User{Username: "ana"}, nil)
+	checker.On("GetUser", "ghost").Return(nil, errors.New("not found"))
+	repo.On("MarkRead", mock.Anything, "ana", []string{"n1"}).Return(nil)
+	repo.On("MarkRead", mock.Anything, "ana", []string(nil)).Return(nil)
+
+	service := NewNotificationService(repo, checker)
+	assert.NoError(t, service.MarkRead(context.Background(), "ana", []string{"n1"}))
+	assert.NoError(t, service.MarkRead(context.Background(), "ana", nil))
+	assert.Equal(t, ErrUserNotFound, service.MarkRead(context.Background(), "ghost", nil))
+
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+)
+
+// Type es el tipo de una notificación
+type Type string
+
+const (
+	TypeFollow  Type = "follow"
+	TypeMention Type = "mention"
+	TypeLike    Type = "like"
+	TypeReply   Type = "reply"
+)
+
+// MaxActors es la cantidad de usuarios más recientes que se conservan en una
+// notificación agregada; ActorCount sigue contando a todos
+const MaxActors = 10
+
+// Activity es una acción de un usuario que genera una notificación para otro
+type Activity struct {
+	// Recipient es el usuario notificado y Actor el que realizó la acción
+	Recipient string
+	Actor     string
+	Type      Type
+	// TweetID es el tweet likeado, o la respuesta o el tweet con la mención;
+	// vacío en los follows
+	TweetID    string
+	OccurredAt time.Time
+}
+
+// GroupKey identifica las actividades que se agregan en una misma
+// notificación: los follows se agrupan entre sí, los likes por tweet, y cada
+// respuesta o mención genera su propia notificación
+func (a Activity) GroupKey() string {
+	if a.TweetID == "" {
+		return string(a.Type)
+	}
+	return string(a.Type) + ":" + a.TweetID
+}
+
+// Notifies indica si la actividad genera una notificación. Los usuarios no
+// reciben notificaciones de sus propias acciones.
+func (a Activity) Notifies() bool {
+	return a.Recipient != "" && a.Recipient != a.Actor
+}
+
+// TweetActivities devuelve las actividades de un tweet nuevo: la respuesta al
+// autor del tweet respondido y una mención para cada usuario mencionado.
+// Quien recibe la respuesta no recibe además la mención por el mismo tweet.
+func TweetActivities(author, tweetID, inReplyToUsername string, mentions []string, at time.Time) []Activity {
+	var activities []Activity
+	if inReplyToUsername != "" {
+		activities = append(activities, Activity{
+			Recipient:  inReplyToUsername,
+			Actor:      author,
+			Type:       TypeReply,
+			TweetID:    tweetID,
+			OccurredAt: at,
+		})
+	}
+	for _, mentioned := range mentions {
+		if mentioned == inReplyToUsername {
+			continue
+		}
+		activities = append(activities, Activity{
+			Recipient:  mentioned,
+			Actor:      author,
+			Type:       TypeMention,
+			TweetID:    tweetID,
+			OccurredAt: at,
+		})
+	}
+	return activities
+}
+
+// Notification es una notificación para un usuario que agrega las
+// actividades del mismo grupo mientras no se lea
+type Notification struct {
+	ID       string `bson:"_id" json:"id"`
+	Username string `bson:"username" json:"username"`
+	Type     Type   `bson:"type" json:"type"`
+	GroupKey string `bson:"groupKey" json:"-"`
+	TweetID  string `bson:"tweetId,omitempty" json:"tweetId,omitempty"`
+	// Actors son los usuarios más recientes primero, hasta MaxActors
+	Actors     []string  `bson:"actors" json:"actors"`
+	ActorCount int64     `bson:"actorCount" json:"actorCount"`
+	Read       bool      `bson:"read" json:"read"`
+	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+	// UpdatedAt es la fecha de la última actividad agregada; las
+	// notificaciones se listan por esta fecha
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// NewNotification crea la notificación que inicia el grupo de la actividad
+func NewNotification(activity Activity) *Notification {
+	return &Notification{
+		ID:         uuid.New().String(),
+		Username:   activity.Recipient,
+		Type:       activity.Type,
+		GroupKey:   activity.GroupKey(),
+		TweetID:    activity.TweetID,
+		Actors:     []string{activity.Actor},
+		ActorCount: 1,
+		CreatedAt:  activity.OccurredAt,
+		UpdatedAt:  activity.OccurredAt,
+	}
+}
+
+// Cursor devuelve el cursor que apunta a esta notificación dentro de un listado
+func (n *Notification) Cursor() pagination.Cursor {
+	return pagination.Cursor{CreatedAt: n.UpdatedAt, ID: n.ID}
+}
+
+// Summary describe la notificación en una línea, por ejemplo
+// "ana and 4 others liked your tweet"
+func (n *Notification) Summary() string {
+	if len(n.Actors) == 0 {
+		return ""
+	}
+
+	who := n.Actors[0]
+	switch others := n.ActorCount - 1; {
+	case others == 1:
+		who += " and 1 other"
+	case others > 1:
+		who += fmt.Sprintf(" and %d others", others)
+	}
+
+	switch n.Type {
+	case TypeFollow:
+		return who + " followed you"
+	case TypeMention:
+		return who + " mentioned you"
+	case TypeLike:
+		return who + " liked your tweet"
+	case TypeReply:
+		return who + " replied to your tweet"
+	}
+	return ""
+}
+
+// NotificationPage es una página de notificaciones ordenada de la actividad
+// más reciente a la más antigua, junto con la cantidad total sin leer
+type NotificationPage struct {
+	Notifications []NotificationView `json:"notifications"`
+	UnreadCount   int64              `json:"unreadCount"`
+	NextCursor    string             `json:"nextCursor,omitempty"`
+}
+
+// NotificationView es la representación de una notificación en un listado
+type NotificationView struct {
+	Notification
+	Summary string `json:"summary"`
+}
+
+// NewNotificationPage arma la página a partir de las notificaciones
+// obtenidas. Una página incompleta indica que no hay más resultados.
+func NewNotificationPage(notifications []Notification, unread int64, limit int) *NotificationPage {
+	page := &NotificationPage{
+		Notifications: make([]NotificationView, 0, len(notifications)),
+		UnreadCount:   unread,
+	}
+	for _, n := range notifications {
+		page.Notifications = append(page.Notifications, NotificationView{Notification: n, Summary: n.Summary()})
+	}
+	if limit > 0 && len(notifications) == limit {
+		page.NextCursor = notifications[len(notifications)-1].Cursor().Encode()
+	}
+	return page
+}
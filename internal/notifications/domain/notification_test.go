@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_Summary(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification Notification
+		expected     string
+	}{
+		{
+			name:         "single follow",
+			notification: Notification{Type: TypeFollow, Actors: []string{"ana"}, ActorCount: 1},
+			expected:     "ana followed you",
+		},
+		{
+			name:         "two likes",
+			notification: Notification{Type: TypeLike, Actors: []string{"ana", "beto"}, ActorCount: 2},
+			expected:     "ana and 1 other liked your tweet",
+		},
+		{
+			name:         "count beyond the stored actors",
+			notification: Notification{Type: TypeLike, Actors: []string{"ana", "beto"}, ActorCount: 40},
+			expected:     "ana and 39 others liked your tweet",
+		},
+		{
+			name:         "reply",
+			notification: Notification{Type: TypeReply, Actors: []string{"ana"}, ActorCount: 1},
+			expected:     "ana replied to your tweet",
+		},
+		{
+			name:         "mention",
+			notification: Notification{Type: TypeMention, Actors: []string{"ana"}, ActorCount: 1},
+			expected:     "ana mentioned you",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.notification.Summary())
+		})
+	}
+}
+
+func TestActivity_GroupKey(t *testing.T) {
+	assert.Equal(t, "follow", Activity{Type: TypeFollow, Actor: "ana"}.GroupKey())
+	assert.Equal(t, "like:t1", Activity{Type: TypeLike, TweetID: "t1", Actor: "ana"}.GroupKey())
+}
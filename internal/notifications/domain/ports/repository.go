@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+)
+
+// NotificationRepository define la interfaz para el repositorio de notificaciones
+type NotificationRepository interface {
+	// Record agrega la actividad a la notificación sin leer de su grupo, o
+	// crea una nueva si no hay ninguna. Si el actor ya figura en la
+	// notificación sin leer, la actividad se ignora.
+	Record(ctx context.Context, activity domain.Activity) error
+	// List obtiene las notificaciones de un usuario, de la actividad más
+	// reciente a la más antigua, siguientes al cursor si no es nil
+	List(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Notification, error)
+	// CountUnread cuenta las notificaciones sin leer de un usuario
+	CountUnread(ctx context.Context, username string) (int64, error)
+	// MarkRead marca como leídas las notificaciones indicadas de un usuario,
+	// o todas si ids está vacío
+	MarkRead(ctx context.Context, username string, ids []string) error
+}
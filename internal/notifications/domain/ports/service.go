@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+)
+
+// NotificationService define la interfaz para el servicio de notificaciones
+type NotificationService interface {
+	// ListNotifications lista las notificaciones de un usuario junto con la
+	// cantidad sin leer
+	ListNotifications(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.NotificationPage, error)
+	// CountUnread cuenta las notificaciones sin leer de un usuario
+	CountUnread(ctx context.Context, username string) (int64, error)
+	// MarkRead marca como leídas las notificaciones indicadas, o todas si ids
+	// está vacío
+	MarkRead(ctx context.Context, username string, ids []string) error
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/application"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain/ports"
+)
+
+type NotificationHandler struct {
+	service ports.NotificationService
+}
+
+func NewNotificationHandler(service ports.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+	}
+}
+
+type unreadCountResponse struct {
+	UnreadCount int64 `json:"unreadCount"`
+}
+
+// markReadRequest indica las notificaciones a marcar como leídas; sin ids se
+// marcan todas
+type markReadRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	limit, err := pagination.ParseLimit(c.Query("limit"), pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cursor, err := pagination.ParseCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListNotifications(c.Request.Context(), c.Param("username"), cursor, limit)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *NotificationHandler) CountUnread(c *gin.Context) {
+	count, err := h.service.CountUnread(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, unreadCountResponse{UnreadCount: count})
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	// El body es opcional
+	var req markReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.service.MarkRead(c.Request.Context(), c.Param("username"), req.IDs)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
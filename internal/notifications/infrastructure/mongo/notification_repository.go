@@ -0,0 +1,137 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain"
+	"github.com/nicodelara/microblogging-uala/internal/notifications/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoNotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoNotificationRepository(client *mongo.Client, dbName, collName string) (ports.NotificationRepository, error) {
+	collection := client.Database(dbName).Collection(collName)
+
+	// Crear índices
+	indexModels := []mongo.IndexModel{
+		{
+			// Un grupo tiene a lo sumo una notificación sin leer, que es la
+			// que recibe las actividades nuevas
+			Keys: bson.D{
+				{Key: "username", Value: 1},
+				{Key: "groupKey", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"read": false}),
+		},
+		{
+			// Notificaciones de un usuario por última actividad
+			Keys: bson.D{
+				{Key: "username", Value: 1},
+				{Key: "updatedAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			// Conteo de notificaciones sin leer
+			Keys: bson.D{
+				{Key: "username", Value: 1},
+				{Key: "read", Value: 1},
+			},
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(context.Background(), indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoNotificationRepository{
+		collection: collection,
+	}, nil
+}
+
+func (r *mongoNotificationRepository) Record(ctx context.Context, activity domain.Activity) error {
+	group := bson.M{"username": activity.Recipient, "groupKey": activity.GroupKey(), "read": false}
+
+	// Agregar al actor a la notificación sin leer del grupo si todavía no figura
+	filter := bson.M{"actors": bson.M{"$ne": activity.Actor}}
+	for k, v := range group {
+		filter[k] = v
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$push": bson.M{"actors": bson.M{
+			"$each":     bson.A{activity.Actor},
+			"$position": 0,
+			"$slice":    domain.MaxActors,
+		}},
+		"$inc": bson.M{"actorCount": 1},
+		"$max": bson.M{"updatedAt": activity.OccurredAt},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Crear la notificación del grupo. Si ya existe sin leer es porque el
+	// actor ya figuraba en ella y la actividad se descarta. Dos creaciones
+	// concurrentes chocan con el índice único y el evento se reintenta.
+	notification := domain.NewNotification(activity)
+	_, err = r.collection.UpdateOne(ctx, group, bson.M{"$setOnInsert": bson.M{
+		"_id":        notification.ID,
+		"type":       notification.Type,
+		"tweetId":    notification.TweetID,
+		"actors":     notification.Actors,
+		"actorCount": notification.ActorCount,
+		"createdAt":  notification.CreatedAt,
+		"updatedAt":  notification.UpdatedAt,
+	}}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *mongoNotificationRepository) List(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Notification, error) {
+	filter := bson.M{"username": username}
+	if cursor != nil {
+		filter["$or"] = bson.A{
+			bson.M{"updatedAt": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"updatedAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	result, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	var notifications []domain.Notification
+	if err := result.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *mongoNotificationRepository) CountUnread(ctx context.Context, username string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"username": username, "read": false})
+}
+
+func (r *mongoNotificationRepository) MarkRead(ctx context.Context, username string, ids []string) error {
+	filter := bson.M{"username": username, "read": false}
+	if len(ids) > 0 {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read": true}})
+	return err
+}
@@ -0,0 +1,35 @@
+package application
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+)
+
+// AuthorProjector mantiene la proyección de perfiles de autores que usa el
+// timeline para mostrar el nombre visible y el avatar junto a cada tweet
+type AuthorProjector struct {
+	authors ports.AuthorRepository
+}
+
+func NewAuthorProjector(authors ports.AuthorRepository) *AuthorProjector {
+	return &AuthorProjector{authors: authors}
+}
+
+// HandleUserUpdated reemplaza el perfil del autor. Como el payload contiene el
+// perfil completo, reprocesar el evento es inocuo, y un evento atrasado no
+// pisa un perfil más nuevo porque el repositorio compara las versiones.
+func (p *AuthorProjector) HandleUserUpdated(ctx context.Context, evt events.Event) error {
+	var payload events.UserUpdatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return p.authors.SaveAuthor(ctx, domain.Author{
+		Username:    payload.Username,
+		DisplayName: payload.DisplayName,
+		AvatarURL:   payload.AvatarURL,
+		Version:     payload.Version,
+	})
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	timelinedomain "github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	usersdomain "github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorProjector_HandleUserUpdated(t *testing.T) {
+	evt, err := events.New(events.TopicUsers, events.UserUpdated, events.UserUpdatedVersion, "alice", events.UserUpdatedPayload{
+		Username:    "alice",
+		DisplayName: "Alice",
+		AvatarURL:   "https://cdn.example.com/alice.png",
+		Version:     4,
+	})
+	assert.NoError(t, err)
+
+	// La versión llega al repositorio, que descarta el perfil si ya tiene uno más nuevo
+	authors := new(mockAuthorRepository)
+	authors.On("SaveAuthor", mock.Anything, timelinedomain.Author{
+		Username:    "alice",
+		DisplayName: "Alice",
+		AvatarURL:   "https://cdn.example.com/alice.png",
+		Version:     4,
+	}).Return(nil)
+
+	assert.NoError(t, NewAuthorProjector(authors).HandleUserUpdated(context.Background(), evt))
+	authors.AssertExpectations(t)
+}
+
+func TestTimelineService_GetTimeline_DecoratesCachedTweets(t *testing.T) {
+	now := time.Now()
+	cachedTweets, err := json.Marshal([]timelinedomain.Tweet{
+		{ID: "2", Username: "alice", Content: "Tweet 2", CreatedAt: now},
+		{ID: "1", Username: "bob", Content: "Tweet 1", CreatedAt: now},
+	})
+	assert.NoError(t, err)
+
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+	checker.On("GetFollowings", mock.Anything, "testuser").Return([]string{"alice", "bob"}, nil)
+
+	cache := new(mockCacheRepository)
+	cache.On("Lookup", mock.Anything, "timeline:testuser:offset=0:limit=10").Return(string(cachedTweets), true, nil)
+
+	// El perfil se lee después del caché, por lo que un cambio de nombre se
+	// refleja sin invalidar las páginas cacheadas
+	authors := new(mockAuthorRepository)
+	authors.On("GetAuthors", mock.Anything, []string{"alice", "bob"}).Return(map[string]timelinedomain.Author{
+		"alice": {Username: "alice", DisplayName: "Alice"},
+	}, nil)
+
+	service := NewTimelineService(new(mockTimelineRepository), checker, newTestCacheLoader(cache), new(mockHomeTimelineRepository), new(mockCelebrityRepository), authors, TimelineConfig{HomeTimelineLength: 100})
+	timeline, err := service.GetTimeline(context.Background(), "testuser", timelinedomain.Page{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", timeline.Tweets[0].DisplayName)
+	assert.Empty(t, timeline.Tweets[1].DisplayName)
+	authors.AssertExpectations(t)
+}
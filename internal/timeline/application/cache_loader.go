@@ -0,0 +1,205 @@
+package application
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// loadTimeout limita cada regeneración de una clave, que no depende de la
+	// request que la inició
+	loadTimeout = 10 * time.Second
+
+	// leasePollInterval es cada cuánto se consulta el caché mientras otra
+	// réplica regenera una clave
+	leasePollInterval = 25 * time.Millisecond
+)
+
+// CacheLoaderConfig agrupa los parámetros de la regeneración de claves del caché
+type CacheLoaderConfig struct {
+	// LeaseTTL es el tiempo máximo que una réplica retiene la regeneración de
+	// una clave; sólo se alcanza si la réplica cae antes de liberarla
+	LeaseTTL time.Duration
+
+	// LeaseWait es cada cuánto una réplica sin el lease, mientras espera a que
+	// la que lo tiene guarde el valor, vuelve a intentar tomarlo por si esta
+	// lo liberó sin guardarlo
+	LeaseWait time.Duration
+}
+
+// CacheMetrics son los contadores de las lecturas del caché. Cada lectura
+// suma en exactamente uno de ellos.
+type CacheMetrics struct {
+	// Hits son las lecturas resueltas con un valor vigente
+	Hits int64 `json:"hits"`
+	// StaleHits son las lecturas resueltas con un valor vencido mientras se regenera
+	StaleHits int64 `json:"staleHits"`
+	// Misses son las lecturas que regeneraron el valor
+	Misses int64 `json:"misses"`
+	// Coalesced son las lecturas que esperaron la regeneración de otra del mismo proceso
+	Coalesced int64 `json:"coalesced"`
+}
+
+// CacheLoader implementa ports.CacheLoader. Lee claves del caché y las regenera cuando faltan, evitando que
+// las lecturas concurrentes de una clave popular vayan todas a MongoDB al
+// vencer:
+//   - dentro del proceso, las lecturas de una clave faltante comparten una
+//     única regeneración (single-flight)
+//   - entre réplicas, sólo la que obtiene el lease de la clave la regenera y
+//     las demás esperan a que aparezca en el caché
+//   - los valores vencidos se siguen sirviendo mientras una única lectura los
+//     regenera en segundo plano
+type CacheLoader struct {
+	cache  ports.CacheRepository
+	leases ports.CacheLeaseRepository
+	cfg    CacheLoaderConfig
+	group  singleflight.Group
+
+	hits      atomic.Int64
+	staleHits atomic.Int64
+	misses    atomic.Int64
+	coalesced atomic.Int64
+}
+
+// NewCacheLoader crea una nueva instancia de CacheLoader
+func NewCacheLoader(cache ports.CacheRepository, leases ports.CacheLeaseRepository, cfg CacheLoaderConfig) *CacheLoader {
+	return &CacheLoader{
+		cache:  cache,
+		leases: leases,
+		cfg:    cfg,
+	}
+}
+
+// Load devuelve el valor de key. Si no está en el caché lo obtiene con load y
+// lo guarda asociado a los tags que load devuelve.
+func (l *CacheLoader) Load(ctx context.Context, key string, load ports.LoadFunc) (string, error) {
+	value, fresh, err := l.cache.Lookup(ctx, key)
+	if err == nil && value != "" {
+		if fresh {
+			l.hits.Add(1)
+		} else {
+			l.staleHits.Add(1)
+			l.refresh(ctx, key, load)
+		}
+		return value, nil
+	}
+
+	// La regeneración la ejecuta la primera lectura; las demás reciben su resultado
+	leader := false
+	results := l.group.DoChan(key, func() (interface{}, error) {
+		leader = true
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		return l.fill(ctx, key, load)
+	})
+
+	select {
+	case res := <-results:
+		if leader {
+			l.misses.Add(1)
+		} else {
+			l.coalesced.Add(1)
+		}
+		if res.Err != nil {
+			return "", res.Err
+		}
+		return res.Val.(string), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Metrics devuelve los contadores acumulados de las lecturas
+func (l *CacheLoader) Metrics() CacheMetrics {
+	return CacheMetrics{
+		Hits:      l.hits.Load(),
+		StaleHits: l.staleHits.Load(),
+		Misses:    l.misses.Load(),
+		Coalesced: l.coalesced.Load(),
+	}
+}
+
+// fill obtiene el valor de una clave faltante y lo guarda. Si otra réplica
+// tiene el lease de la clave espera a que lo guarde, volviendo a intentar
+// tomar el lease cada LeaseWait, de modo que si la que lo tiene falla sólo
+// una de las que esperan la regenera. Obtiene el valor sin el lease si no se
+// puede consultar o si pasa LeaseTTL sin que el valor aparezca.
+func (l *CacheLoader) fill(ctx context.Context, key string, load ports.LoadFunc) (string, error) {
+	deadline := time.Now().Add(l.cfg.LeaseTTL)
+	for {
+		token, acquired, err := l.leases.AcquireLease(ctx, key, l.cfg.LeaseTTL)
+		if err != nil {
+			break
+		}
+		if acquired {
+			defer func() { _ = l.leases.ReleaseLease(ctx, key, token) }()
+			break
+		}
+		if value, ok := l.waitForValue(ctx, key, time.Until(deadline)); ok {
+			return value, nil
+		}
+		if ctx.Err() != nil || !time.Now().Before(deadline) {
+			break
+		}
+	}
+
+	value, tags, err := load(ctx)
+	if err != nil {
+		return "", err
+	}
+	_ = l.cache.Set(ctx, key, value, tags...)
+	return value, nil
+}
+
+// waitForValue espera hasta LeaseWait, sin pasar de remaining, a que otra
+// réplica guarde el valor de key
+func (l *CacheLoader) waitForValue(ctx context.Context, key string, remaining time.Duration) (string, bool) {
+	timeout := time.NewTimer(min(l.cfg.LeaseWait, remaining))
+	defer timeout.Stop()
+	ticker := time.NewTicker(leasePollInterval)
+	defer ticker.Stop()
+
+	// El caché se consulta también al vencer la espera, para no perder un
+	// valor guardado después de la última consulta
+	for {
+		select {
+		case <-ticker.C:
+			if value, _, err := l.cache.Lookup(ctx, key); err == nil && value != "" {
+				return value, true
+			}
+		case <-timeout.C:
+			value, _, err := l.cache.Lookup(ctx, key)
+			return value, err == nil && value != ""
+		case <-ctx.Done():
+			return "", false
+		}
+	}
+}
+
+// refresh regenera en segundo plano un valor vencido. Lo hace una sola lectura
+// por proceso, y sólo si obtiene el lease; si no, otra réplica ya lo está
+// regenerando y se sigue sirviendo el valor vencido.
+func (l *CacheLoader) refresh(ctx context.Context, key string, load ports.LoadFunc) {
+	// Usa su propia clave para que una lectura que no encuentra el valor no
+	// reciba el resultado de un refresco que no obtuvo el lease
+	l.group.DoChan("refresh:"+key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		token, acquired, err := l.leases.AcquireLease(ctx, key, l.cfg.LeaseTTL)
+		if err != nil || !acquired {
+			return nil, err
+		}
+		defer func() { _ = l.leases.ReleaseLease(ctx, key, token) }()
+
+		value, tags, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return nil, l.cache.Set(ctx, key, value, tags...)
+	})
+}
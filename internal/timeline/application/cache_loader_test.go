@@ -0,0 +1,272 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLeases es un repositorio de leases en memoria. Con denied, otra réplica
+// tiene todos los leases.
+type fakeLeases struct {
+	mu     sync.Mutex
+	denied bool
+	held   map[string]string
+}
+
+func (l *fakeLeases) AcquireLease(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[string]string)
+	}
+	if _, ok := l.held[key]; ok || l.denied {
+		return "", false, nil
+	}
+	l.held[key] = "token-" + key
+	return l.held[key], true, nil
+}
+
+func (l *fakeLeases) ReleaseLease(ctx context.Context, key, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] == token {
+		delete(l.held, key)
+	}
+	return nil
+}
+
+// newTestCacheLoader crea un CacheLoader sobre el caché indicado que, como una
+// única réplica, siempre obtiene el lease
+func newTestCacheLoader(cache ports.CacheRepository) *CacheLoader {
+	return NewCacheLoader(cache, &fakeLeases{}, CacheLoaderConfig{LeaseTTL: time.Second, LeaseWait: time.Second})
+}
+
+type memoryEntry struct {
+	value string
+	fresh bool
+}
+
+// memoryCache es un caché en memoria cuyas entradas se pueden marcar como vencidas
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string]memoryEntry)}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	value, _, err := c.Lookup(ctx, key)
+	return value, err
+}
+
+func (c *memoryCache) Lookup(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.entries[key]
+	return entry.value, entry.fresh, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value string, tags ...string) error {
+	c.put(key, value, true)
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *memoryCache) InvalidateTag(ctx context.Context, tag string) error {
+	return nil
+}
+
+func (c *memoryCache) TagKeys(ctx context.Context, tag string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *memoryCache) put(key, value string, fresh bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = memoryEntry{value: value, fresh: fresh}
+}
+
+func TestCacheLoader_ServesFreshValues(t *testing.T) {
+	cache := newMemoryCache()
+	cache.put("key", "cached", true)
+	loader := newTestCacheLoader(cache)
+
+	value, err := loader.Load(context.Background(), "key", func(ctx context.Context) (string, []string, error) {
+		t.Fatal("fresh values must not be loaded")
+		return "", nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", value)
+	assert.Equal(t, CacheMetrics{Hits: 1}, loader.Metrics())
+}
+
+func TestCacheLoader_CoalescesConcurrentMisses(t *testing.T) {
+	cache := newMemoryCache()
+	loader := newTestCacheLoader(cache)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (string, []string, error) {
+		loads.Add(1)
+		<-release
+		return "loaded", []string{"tag"}, nil
+	}
+
+	const readers = 20
+	var wg sync.WaitGroup
+	values := make([]string, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], _ = loader.Load(context.Background(), "key", load)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Las lecturas que llegan después de la regeneración encuentran el valor guardado
+	metrics := loader.Metrics()
+	assert.Equal(t, int32(1), loads.Load())
+	assert.Equal(t, int64(1), metrics.Misses)
+	assert.Equal(t, int64(readers-1), metrics.Coalesced+metrics.Hits)
+	for _, value := range values {
+		assert.Equal(t, "loaded", value)
+	}
+	cached, fresh, _ := cache.Lookup(context.Background(), "key")
+	assert.Equal(t, "loaded", cached)
+	assert.True(t, fresh)
+}
+
+func TestCacheLoader_ServesStaleValuesWhileRefreshing(t *testing.T) {
+	cache := newMemoryCache()
+	cache.put("key", "stale", false)
+	loader := newTestCacheLoader(cache)
+
+	var loads atomic.Int32
+	load := func(ctx context.Context) (string, []string, error) {
+		loads.Add(1)
+		return "refreshed", nil, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := loader.Load(context.Background(), "key", load)
+		assert.NoError(t, err)
+		assert.Contains(t, []string{"stale", "refreshed"}, value)
+	}
+
+	assert.Eventually(t, func() bool {
+		value, fresh, _ := cache.Lookup(context.Background(), "key")
+		return fresh && value == "refreshed"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), loads.Load())
+	assert.GreaterOrEqual(t, loader.Metrics().StaleHits, int64(1))
+}
+
+func TestCacheLoader_StaleValuesAreNotRefreshedWithoutLease(t *testing.T) {
+	cache := newMemoryCache()
+	cache.put("key", "stale", false)
+	loader := NewCacheLoader(cache, &fakeLeases{denied: true}, CacheLoaderConfig{LeaseTTL: time.Second})
+
+	value, err := loader.Load(context.Background(), "key", func(ctx context.Context) (string, []string, error) {
+		t.Error("only the lease holder refreshes the value")
+		return "", nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "stale", value)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, CacheMetrics{StaleHits: 1}, loader.Metrics())
+}
+
+func TestCacheLoader_WaitsForLeaseHolder(t *testing.T) {
+	cache := newMemoryCache()
+	loader := NewCacheLoader(cache, &fakeLeases{denied: true}, CacheLoaderConfig{LeaseTTL: time.Second, LeaseWait: time.Second})
+
+	// Otra réplica guarda el valor mientras esta espera
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		cache.put("key", "from replica", true)
+	}()
+
+	value, err := loader.Load(context.Background(), "key", func(ctx context.Context) (string, []string, error) {
+		t.Error("the value must come from the lease holder")
+		return "", nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "from replica", value)
+}
+
+func TestCacheLoader_KeepsWaitingPastLeaseWait(t *testing.T) {
+	cache := newMemoryCache()
+	loader := NewCacheLoader(cache, &fakeLeases{denied: true}, CacheLoaderConfig{LeaseTTL: time.Second, LeaseWait: 20 * time.Millisecond})
+
+	// La réplica con el lease tarda más que LeaseWait en guardar el valor
+	go func() {
+		time.Sleep(80 * time.Millisecond)
+		cache.put("key", "from replica", true)
+	}()
+
+	value, err := loader.Load(context.Background(), "key", func(ctx context.Context) (string, []string, error) {
+		t.Error("the value must come from the lease holder")
+		return "", nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "from replica", value)
+}
+
+func TestCacheLoader_TakesOverReleasedLease(t *testing.T) {
+	cache := newMemoryCache()
+	// Otra réplica tiene el lease y lo libera sin guardar el valor
+	leases := &fakeLeases{held: map[string]string{"key": "other"}}
+	loader := NewCacheLoader(cache, leases, CacheLoaderConfig{LeaseTTL: time.Second, LeaseWait: 20 * time.Millisecond})
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = leases.ReleaseLease(context.Background(), "key", "other")
+	}()
+
+	var loads atomic.Int64
+	value, err := loader.Load(context.Background(), "key", func(ctx context.Context) (string, []string, error) {
+		loads.Add(1)
+		return "loaded", nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.Equal(t, int64(1), loads.Load())
+}
+
+func TestCacheLoader_LoadsWhenLeaseExpires(t *testing.T) {
+	cache := newMemoryCache()
+	loader := NewCacheLoader(cache, &fakeLeases{denied: true}, CacheLoaderConfig{LeaseTTL: 100 * time.Millisecond, LeaseWait: 20 * time.Millisecond})
+
+	start := time.Now()
+	value, err := loader.Load(context.Background(), "key", func(ctx context.Context) (string, []string, error) {
+		return "loaded", []string{"tag"}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, CacheMetrics{Misses: 1}, loader.Metrics())
+}
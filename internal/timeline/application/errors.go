@@ -3,5 +3,9 @@ package application
 import "errors"
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrTweetNotFound = errors.New("tweet not found")
+
+	ErrInvalidHashtag     = errors.New("hashtag is required")
+	ErrUnknownTrendWindow = errors.New("unknown trend window")
 )
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+)
+
+// fanoutBatchSize es la cantidad máxima de timelines actualizados por escritura
+const fanoutBatchSize = 500
+
+// FanoutWorker implementa el fan-out on write: cada tweet nuevo se agrega al
+// timeline precalculado de los seguidores de su autor. Los autores con más
+// seguidores que el umbral configurado se clasifican como celebridades y sus
+// tweets se mezclan en el momento de la lectura.
+type FanoutWorker struct {
+	repo          ports.TimelineRepository
+	userChecker   common.UserChecker
+	homeTimelines ports.HomeTimelineRepository
+	celebrities   ports.CelebrityRepository
+	cfg           TimelineConfig
+}
+
+// NewFanoutWorker crea una nueva instancia de FanoutWorker
+func NewFanoutWorker(
+	repo ports.TimelineRepository,
+	userChecker common.UserChecker,
+	homeTimelines ports.HomeTimelineRepository,
+	celebrities ports.CelebrityRepository,
+	cfg TimelineConfig,
+) *FanoutWorker {
+	return &FanoutWorker{
+		repo:          repo,
+		userChecker:   userChecker,
+		homeTimelines: homeTimelines,
+		celebrities:   celebrities,
+		cfg:           cfg,
+	}
+}
+
+// HandleTweetCreated procesa un evento TweetCreated. Es idempotente, ya que
+// volver a agregar un tweet a un sorted set no lo duplica.
+func (w *FanoutWorker) HandleTweetCreated(ctx context.Context, evt events.Event) error {
+	var payload events.TweetCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	followerCount, err := w.userChecker.CountFollowers(ctx, payload.Username)
+	if err != nil {
+		return err
+	}
+
+	celebrity := followerCount > w.cfg.CelebrityFollowerThreshold
+	if celebrity {
+		return w.celebrities.SetCelebrity(ctx, payload.Username, true)
+	}
+
+	followers, err := w.userChecker.GetFollowers(ctx, payload.Username)
+	if err != nil {
+		return err
+	}
+
+	// Una celebridad que baja del umbral deja de mezclarse en la lectura, por
+	// lo que antes se agregan a los timelines de sus seguidores los tweets
+	// que publicó como celebridad. Si falla sigue siendo celebridad y la
+	// reentrega lo reintenta.
+	wasCelebrity, err := w.celebrities.FilterCelebrities(ctx, []string{payload.Username})
+	if err != nil {
+		return err
+	}
+	if len(wasCelebrity) > 0 {
+		if err := w.backfill(ctx, payload.Username, followers); err != nil {
+			return err
+		}
+	}
+	if err := w.celebrities.SetCelebrity(ctx, payload.Username, false); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(followers); start += fanoutBatchSize {
+		end := min(start+fanoutBatchSize, len(followers))
+		if err := w.homeTimelines.AddTweet(ctx, followers[start:end], payload.TweetID, payload.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfill agrega los tweets recientes del autor a los timelines precalculados
+// de sus seguidores, igual que al seguirlo
+func (w *FanoutWorker) backfill(ctx context.Context, username string, followers []string) error {
+	tweets, err := w.repo.GetTweetsForUsers(ctx, []string{username}, 0, w.cfg.HomeTimelineLength)
+	if err != nil {
+		return err
+	}
+	for _, follower := range followers {
+		if err := w.homeTimelines.AddTweets(ctx, follower, tweets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
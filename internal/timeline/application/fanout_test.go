@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	timelinedomain "github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFanoutWorker_HandleTweetCreated(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	manyFollowers := make([]string, fanoutBatchSize+1)
+	for i := range manyFollowers {
+		manyFollowers[i] = fmt.Sprintf("follower%d", i)
+	}
+
+	const testCelebrityThreshold = 10000
+
+	authorTweets := []timelinedomain.Tweet{
+		{ID: "old2", Username: "author", Content: "Old 2", CreatedAt: createdAt.Add(-time.Hour)},
+		{ID: "old1", Username: "author", Content: "Old 1", CreatedAt: createdAt.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name          string
+		checkerSetup  func(*mockUserChecker)
+		repoSetup     func(*mockTimelineRepository)
+		homeSetup     func(*mockHomeTimelineRepository)
+		celebSetup    func(*mockCelebrityRepository)
+		expectedError error
+	}{
+		{
+			name: "adds tweet to every follower timeline",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("CountFollowers", mock.Anything, "author").Return(int64(2), nil)
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{"user1", "user2"}, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{}, nil)
+				m.On("SetCelebrity", mock.Anything, "author", false).Return(nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("AddTweet", mock.Anything, []string{"user1", "user2"}, "tweet1", createdAt).Return(nil)
+			},
+		},
+		{
+			name: "splits large follower lists in batches",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("CountFollowers", mock.Anything, "author").Return(int64(len(manyFollowers)), nil)
+				m.On("GetFollowers", mock.Anything, "author").Return(manyFollowers, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{}, nil)
+				m.On("SetCelebrity", mock.Anything, "author", false).Return(nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("AddTweet", mock.Anything, manyFollowers[:fanoutBatchSize], "tweet1", createdAt).Return(nil).Once()
+				m.On("AddTweet", mock.Anything, manyFollowers[fanoutBatchSize:], "tweet1", createdAt).Return(nil).Once()
+			},
+		},
+		{
+			name: "skips fan-out for celebrities",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("CountFollowers", mock.Anything, "author").Return(int64(testCelebrityThreshold+1), nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("SetCelebrity", mock.Anything, "author", true).Return(nil)
+			},
+		},
+		{
+			name: "demoted celebrity backfills followers before fan-out",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("CountFollowers", mock.Anything, "author").Return(int64(testCelebrityThreshold), nil)
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{"user1", "user2"}, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{"author"}, nil)
+				m.On("SetCelebrity", mock.Anything, "author", false).Return(nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsForUsers", mock.Anything, []string{"author"}, 0, 100).Return(authorTweets, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("AddTweets", mock.Anything, "user1", authorTweets).Return(nil)
+				m.On("AddTweets", mock.Anything, "user2", authorTweets).Return(nil)
+				m.On("AddTweet", mock.Anything, []string{"user1", "user2"}, "tweet1", createdAt).Return(nil)
+			},
+		},
+		{
+			name: "failed backfill keeps the author as celebrity",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("CountFollowers", mock.Anything, "author").Return(int64(1), nil)
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{"user1"}, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{"author"}, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsForUsers", mock.Anything, []string{"author"}, 0, 100).Return(authorTweets, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("AddTweets", mock.Anything, "user1", authorTweets).Return(errors.New("redis down"))
+			},
+			expectedError: errors.New("redis down"),
+		},
+		{
+			name: "no followers",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("CountFollowers", mock.Anything, "author").Return(int64(0), nil)
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{}, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{}, nil)
+				m.On("SetCelebrity", mock.Anything, "author", false).Return(nil)
+			},
+		},
+		{
+			name: "timeline store error",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("CountFollowers", mock.Anything, "author").Return(int64(1), nil)
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{"user1"}, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{}, nil)
+				m.On("SetCelebrity", mock.Anything, "author", false).Return(nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("AddTweet", mock.Anything, []string{"user1"}, "tweet1", createdAt).Return(errors.New("redis down"))
+			},
+			expectedError: errors.New("redis down"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := new(mockUserChecker)
+			repo := new(mockTimelineRepository)
+			home := new(mockHomeTimelineRepository)
+			celebrities := new(mockCelebrityRepository)
+			tt.checkerSetup(checker)
+			if tt.repoSetup != nil {
+				tt.repoSetup(repo)
+			}
+			if tt.homeSetup != nil {
+				tt.homeSetup(home)
+			}
+			if tt.celebSetup != nil {
+				tt.celebSetup(celebrities)
+			}
+
+			evt, err := events.New(events.TopicTweets, events.TweetCreated, events.TweetCreatedVersion, "author", events.TweetCreatedPayload{
+				TweetID:   "tweet1",
+				Username:  "author",
+				Content:   "Hello",
+				CreatedAt: createdAt,
+			})
+			assert.NoError(t, err)
+
+			worker := NewFanoutWorker(repo, checker, home, celebrities, TimelineConfig{HomeTimelineLength: 100, CelebrityFollowerThreshold: testCelebrityThreshold})
+			err = worker.HandleTweetCreated(context.Background(), evt)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			checker.AssertExpectations(t)
+			repo.AssertExpectations(t)
+			home.AssertExpectations(t)
+			celebrities.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+)
+
+// DefaultTrendWindows son las ventanas de tendencias disponibles. La de una
+// hora usa intervalos de 5 minutos y la de un día intervalos de una hora.
+var DefaultTrendWindows = []domain.TrendWindow{
+	{Name: "1h", Bucket: 5 * time.Minute, Buckets: 12, HalfLife: 30 * time.Minute},
+	{Name: "24h", Bucket: time.Hour, Buckets: 24, HalfLife: 6 * time.Hour},
+}
+
+type hashtagService struct {
+	repo    ports.TimelineRepository
+	trends  ports.TrendRepository
+	authors ports.AuthorRepository
+	windows map[string]domain.TrendWindow
+	now     func() time.Time
+}
+
+// NewHashtagService crea el servicio de búsqueda por hashtag y tendencias
+func NewHashtagService(
+	repo ports.TimelineRepository,
+	trends ports.TrendRepository,
+	authors ports.AuthorRepository,
+	windows []domain.TrendWindow,
+) ports.HashtagService {
+	byName := make(map[string]domain.TrendWindow, len(windows))
+	for _, window := range windows {
+		byName[window.Name] = window
+	}
+	return &hashtagService{
+		repo:    repo,
+		trends:  trends,
+		authors: authors,
+		windows: byName,
+		now:     time.Now,
+	}
+}
+
+func (s *hashtagService) GetHashtagTweets(ctx context.Context, tag string, cursor *domain.Cursor, limit int) (*domain.HashtagPage, error) {
+	tag = domain.NormalizeHashtag(tag)
+	if tag == "" {
+		return nil, ErrInvalidHashtag
+	}
+
+	tweets, err := s.repo.GetTweetsByHashtag(ctx, tag, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.HashtagPage{Hashtag: tag, Tweets: tweets}
+	if page.Tweets == nil {
+		page.Tweets = make([]domain.Tweet, 0)
+	}
+	if limit > 0 && len(tweets) == limit {
+		page.NextCursor = domain.CursorFor(tweets[len(tweets)-1]).Encode()
+	}
+
+	decorateAuthors(ctx, s.authors, page.Tweets)
+	return page, nil
+}
+
+func (s *hashtagService) GetTrends(ctx context.Context, window string, limit int) ([]domain.Trend, error) {
+	trendWindow, ok := s.windows[window]
+	if !ok {
+		return nil, ErrUnknownTrendWindow
+	}
+
+	trends, err := s.trends.TopHashtags(ctx, trendWindow, s.now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	if trends == nil {
+		trends = make([]domain.Trend, 0)
+	}
+	return trends, nil
+}
+
+// TrendTracker cuenta los hashtags de los tweets nuevos para calcular las
+// tendencias y los descuenta cuando esos tweets se eliminan
+type TrendTracker struct {
+	trends ports.TrendRepository
+}
+
+func NewTrendTracker(trends ports.TrendRepository) *TrendTracker {
+	return &TrendTracker{trends: trends}
+}
+
+// HandleTweetCreated suma los hashtags del tweet en el intervalo de su fecha
+// de creación. El repositorio ignora los tweets ya contados, por lo que
+// reprocesar el evento no infla las tendencias.
+func (t *TrendTracker) HandleTweetCreated(ctx context.Context, evt events.Event) error {
+	var payload events.TweetCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	if len(payload.Hashtags) == 0 {
+		return nil
+	}
+	return t.trends.RecordHashtags(ctx, payload.TweetID, payload.Hashtags, payload.CreatedAt)
+}
+
+// HandleTweetDeleted descuenta los hashtags del tweet eliminado del intervalo
+// de su fecha de creación. Los eventos sin hashtags, como los de retweets o
+// los publicados antes de incluirlos en el payload, no afectan las tendencias.
+func (t *TrendTracker) HandleTweetDeleted(ctx context.Context, evt events.Event) error {
+	var payload events.TweetDeletedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	if len(payload.Hashtags) == 0 {
+		return nil
+	}
+	return t.trends.RemoveHashtags(ctx, payload.TweetID, payload.Hashtags, payload.CreatedAt)
+}
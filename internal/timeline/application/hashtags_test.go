@@ -0,0 +1,262 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	timelinedomain "github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTrendRepository struct {
+	mock.Mock
+}
+
+func (m *mockTrendRepository) RecordHashtags(ctx context.Context, tweetID string, tags []string, at time.Time) error {
+	args := m.Called(ctx, tweetID, tags, at)
+	return args.Error(0)
+}
+
+func (m *mockTrendRepository) RemoveHashtags(ctx context.Context, tweetID string, tags []string, at time.Time) error {
+	args := m.Called(ctx, tweetID, tags, at)
+	return args.Error(0)
+}
+
+func (m *mockTrendRepository) TopHashtags(ctx context.Context, window timelinedomain.TrendWindow, now time.Time, limit int) ([]timelinedomain.Trend, error) {
+	args := m.Called(ctx, window, now, limit)
+	return args.Get(0).([]timelinedomain.Trend), args.Error(1)
+}
+
+func TestHashtagService_GetHashtagTweets(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tweets := []timelinedomain.Tweet{
+		{ID: "2", Username: "user1", Content: "Hola #Go", CreatedAt: now},
+		{ID: "1", Username: "user2", Content: "Chau #go", CreatedAt: now.Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		name          string
+		tag           string
+		limit         int
+		repoSetup     func(*mockTimelineRepository)
+		expectedTag   string
+		expectedIDs   []string
+		expectedNext  string
+		expectedError error
+	}{
+		{
+			name:  "normalizes the hashtag and returns the next cursor on a full page",
+			tag:   "#Go",
+			limit: 2,
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsByHashtag", mock.Anything, "go", (*timelinedomain.Cursor)(nil), 2).Return(tweets, nil)
+			},
+			expectedTag:  "go",
+			expectedIDs:  []string{"2", "1"},
+			expectedNext: timelinedomain.CursorFor(tweets[1]).Encode(),
+		},
+		{
+			name:  "last page has no next cursor",
+			tag:   "go",
+			limit: 10,
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsByHashtag", mock.Anything, "go", (*timelinedomain.Cursor)(nil), 10).Return([]timelinedomain.Tweet(nil), nil)
+			},
+			expectedTag: "go",
+			expectedIDs: []string{},
+		},
+		{
+			name:          "empty hashtag",
+			tag:           "#",
+			limit:         10,
+			expectedError: ErrInvalidHashtag,
+		},
+		{
+			name:  "repository error",
+			tag:   "go",
+			limit: 10,
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsByHashtag", mock.Anything, "go", (*timelinedomain.Cursor)(nil), 10).Return([]timelinedomain.Tweet(nil), errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockTimelineRepository)
+			authors := new(mockAuthorRepository)
+			if tt.repoSetup != nil {
+				tt.repoSetup(repo)
+			}
+			authors.On("GetAuthors", mock.Anything, mock.Anything).Return(map[string]timelinedomain.Author{}, nil).Maybe()
+
+			service := NewHashtagService(repo, new(mockTrendRepository), authors, DefaultTrendWindows)
+			page, err := service.GetHashtagTweets(context.Background(), tt.tag, nil, tt.limit)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+				assert.Nil(t, page)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedTag, page.Hashtag)
+				ids := make([]string, 0, len(page.Tweets))
+				for _, tweet := range page.Tweets {
+					ids = append(ids, tweet.ID)
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+				assert.Equal(t, tt.expectedNext, page.NextCursor)
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHashtagService_GetTrends(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	trends := []timelinedomain.Trend{{Hashtag: "go", Score: 3}, {Hashtag: "golang", Score: 1.5}}
+
+	tests := []struct {
+		name           string
+		window         string
+		trendsSetup    func(*mockTrendRepository)
+		expectedTrends []timelinedomain.Trend
+		expectedError  error
+	}{
+		{
+			name:   "returns the top hashtags of the window",
+			window: "1h",
+			trendsSetup: func(m *mockTrendRepository) {
+				m.On("TopHashtags", mock.Anything, DefaultTrendWindows[0], now, 10).Return(trends, nil)
+			},
+			expectedTrends: trends,
+		},
+		{
+			name:   "no trends returns an empty list",
+			window: "24h",
+			trendsSetup: func(m *mockTrendRepository) {
+				m.On("TopHashtags", mock.Anything, DefaultTrendWindows[1], now, 10).Return([]timelinedomain.Trend(nil), nil)
+			},
+			expectedTrends: []timelinedomain.Trend{},
+		},
+		{
+			name:          "unknown window",
+			window:        "7d",
+			expectedError: ErrUnknownTrendWindow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trendRepo := new(mockTrendRepository)
+			if tt.trendsSetup != nil {
+				tt.trendsSetup(trendRepo)
+			}
+
+			service := NewHashtagService(new(mockTimelineRepository), trendRepo, new(mockAuthorRepository), DefaultTrendWindows)
+			service.(*hashtagService).now = func() time.Time { return now }
+			result, err := service.GetTrends(context.Background(), tt.window, 10)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedTrends, result)
+			}
+
+			trendRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrendTracker_HandleTweetCreated(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		payload     events.TweetCreatedPayload
+		trendsSetup func(*mockTrendRepository)
+	}{
+		{
+			name:    "records the tweet hashtags",
+			payload: events.TweetCreatedPayload{TweetID: "1", Username: "user1", Hashtags: []string{"go", "golang"}, CreatedAt: now},
+			trendsSetup: func(m *mockTrendRepository) {
+				m.On("RecordHashtags", mock.Anything, "1", []string{"go", "golang"}, now).Return(nil)
+			},
+		},
+		{
+			name:    "tweet without hashtags is ignored",
+			payload: events.TweetCreatedPayload{TweetID: "2", Username: "user1", CreatedAt: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trendRepo := new(mockTrendRepository)
+			if tt.trendsSetup != nil {
+				tt.trendsSetup(trendRepo)
+			}
+
+			evt, err := events.New(events.TopicTweets, events.TweetCreated, events.TweetCreatedVersion, tt.payload.Username, tt.payload)
+			assert.NoError(t, err)
+
+			tracker := NewTrendTracker(trendRepo)
+			assert.NoError(t, tracker.HandleTweetCreated(context.Background(), evt))
+
+			trendRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrendTracker_HandleTweetDeleted(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		payload     events.TweetDeletedPayload
+		trendsSetup func(*mockTrendRepository)
+	}{
+		{
+			name:    "removes the tweet hashtags",
+			payload: events.TweetDeletedPayload{TweetID: "1", Username: "user1", Hashtags: []string{"go"}, CreatedAt: now, DeletedAt: now.Add(time.Hour)},
+			trendsSetup: func(m *mockTrendRepository) {
+				m.On("RemoveHashtags", mock.Anything, "1", []string{"go"}, now).Return(nil)
+			},
+		},
+		{
+			name:    "tweet without hashtags is ignored",
+			payload: events.TweetDeletedPayload{TweetID: "2", Username: "user1", DeletedAt: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trendRepo := new(mockTrendRepository)
+			if tt.trendsSetup != nil {
+				tt.trendsSetup(trendRepo)
+			}
+
+			evt, err := events.New(events.TopicTweets, events.TweetDeleted, events.TweetDeletedVersion, tt.payload.Username, tt.payload)
+			assert.NoError(t, err)
+
+			tracker := NewTrendTracker(trendRepo)
+			assert.NoError(t, tracker.HandleTweetDeleted(context.Background(), evt))
+
+			trendRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrendWindow_Weight(t *testing.T) {
+	window := DefaultTrendWindows[0]
+	assert.Equal(t, 1.0, window.Weight(0))
+	assert.Equal(t, 0.5, window.Weight(6))
+	assert.Equal(t, time.Hour, window.Duration())
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC), window.BucketStart(time.Date(2024, 1, 1, 12, 7, 30, 0, time.UTC)))
+}
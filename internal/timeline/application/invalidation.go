@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+)
+
+// TimelineInvalidator mantiene el caché y los timelines precalculados
+// consistentes con los eventos de tweets y follows, en lugar de depender
+// únicamente del TTL del caché
+type TimelineInvalidator struct {
+	repo          ports.TimelineRepository
+	userChecker   common.UserChecker
+	cache         ports.CacheRepository
+	homeTimelines ports.HomeTimelineRepository
+	celebrities   ports.CelebrityRepository
+	cfg           TimelineConfig
+}
+
+// NewTimelineInvalidator crea una nueva instancia de TimelineInvalidator
+func NewTimelineInvalidator(
+	repo ports.TimelineRepository,
+	userChecker common.UserChecker,
+	cache ports.CacheRepository,
+	homeTimelines ports.HomeTimelineRepository,
+	celebrities ports.CelebrityRepository,
+	cfg TimelineConfig,
+) *TimelineInvalidator {
+	return &TimelineInvalidator{
+		repo:          repo,
+		userChecker:   userChecker,
+		cache:         cache,
+		homeTimelines: homeTimelines,
+		celebrities:   celebrities,
+		cfg:           cfg,
+	}
+}
+
+// HandleTweetCreated invalida el caché de los seguidores del autor. Para las
+// celebridades se invalida su tag, que agrupa las páginas de sus seguidores.
+func (i *TimelineInvalidator) HandleTweetCreated(ctx context.Context, evt events.Event) error {
+	var payload events.TweetCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	celebrities, err := i.celebrities.FilterCelebrities(ctx, []string{payload.Username})
+	if err != nil {
+		return err
+	}
+	if len(celebrities) > 0 {
+		return i.cache.InvalidateTag(ctx, celebrityCacheTag(payload.Username))
+	}
+
+	followers, err := i.userChecker.GetFollowers(ctx, payload.Username)
+	if err != nil {
+		return err
+	}
+
+	return i.invalidate(ctx, followers...)
+}
+
+// HandleTweetDeleted quita el tweet eliminado de los timelines precalculados
+// de los seguidores del autor e invalida su caché. Los tweets de celebridades
+// no están en los timelines precalculados y las lecturas de MongoDB ya los
+// excluyen, por lo que alcanza con invalidar el tag de la celebridad.
+func (i *TimelineInvalidator) HandleTweetDeleted(ctx context.Context, evt events.Event) error {
+	var payload events.TweetDeletedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	celebrities, err := i.celebrities.FilterCelebrities(ctx, []string{payload.Username})
+	if err != nil {
+		return err
+	}
+	if len(celebrities) > 0 {
+		return i.cache.InvalidateTag(ctx, celebrityCacheTag(payload.Username))
+	}
+
+	followers, err := i.userChecker.GetFollowers(ctx, payload.Username)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(followers); start += fanoutBatchSize {
+		end := min(start+fanoutBatchSize, len(followers))
+		if err := i.homeTimelines.RemoveTweet(ctx, followers[start:end], payload.TweetID); err != nil {
+			return err
+		}
+	}
+
+	return i.invalidate(ctx, followers...)
+}
+
+// HandleUserFollowed agrega los tweets recientes del usuario seguido al
+// timeline precalculado del seguidor e invalida su caché
+func (i *TimelineInvalidator) HandleUserFollowed(ctx context.Context, evt events.Event) error {
+	var payload events.UserFollowedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	tweets, err := i.repo.GetTweetsForUsers(ctx, []string{payload.Following}, 0, i.cfg.HomeTimelineLength)
+	if err != nil {
+		return err
+	}
+	if err := i.homeTimelines.AddTweets(ctx, payload.Username, tweets); err != nil {
+		return err
+	}
+
+	return i.invalidate(ctx, payload.Username)
+}
+
+// HandleUserUnfollowed quita los tweets del usuario dejado de seguir del
+// timeline precalculado del seguidor e invalida su caché
+func (i *TimelineInvalidator) HandleUserUnfollowed(ctx context.Context, evt events.Event) error {
+	var payload events.UserUnfollowedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+
+	tweets, err := i.repo.GetTweetsForUsers(ctx, []string{payload.Following}, 0, i.cfg.HomeTimelineLength)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(tweets))
+	for j, tweet := range tweets {
+		ids[j] = tweet.ID
+	}
+	if err := i.homeTimelines.RemoveTweets(ctx, payload.Username, ids); err != nil {
+		return err
+	}
+
+	return i.invalidate(ctx, payload.Username)
+}
+
+// invalidate elimina todas las páginas cacheadas del timeline de cada usuario
+func (i *TimelineInvalidator) invalidate(ctx context.Context, usernames ...string) error {
+	var errs []error
+	for _, username := range usernames {
+		if err := i.cache.InvalidateTag(ctx, timelineCacheTag(username)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	timelinedomain "github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTimelineInvalidator(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	followeeTweets := []timelinedomain.Tweet{
+		{ID: "2", Username: "followee", Content: "Tweet 2", CreatedAt: now},
+		{ID: "1", Username: "followee", Content: "Tweet 1", CreatedAt: now.Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		name         string
+		eventType    string
+		payload      any
+		handle       func(*TimelineInvalidator) events.Handler
+		checkerSetup func(*mockUserChecker)
+		repoSetup    func(*mockTimelineRepository)
+		cacheSetup   func(*mockCacheRepository)
+		homeSetup    func(*mockHomeTimelineRepository)
+		celebSetup   func(*mockCelebrityRepository)
+	}{
+		{
+			name:      "tweet created invalidates followers timelines",
+			eventType: events.TweetCreated,
+			payload:   events.TweetCreatedPayload{TweetID: "3", Username: "author", CreatedAt: now},
+			handle:    func(i *TimelineInvalidator) events.Handler { return i.HandleTweetCreated },
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{}, nil)
+			},
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{"user1", "user2"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("InvalidateTag", mock.Anything, "timeline:user1").Return(nil)
+				m.On("InvalidateTag", mock.Anything, "timeline:user2").Return(nil)
+			},
+		},
+		{
+			name:      "celebrity tweet invalidates the celebrity tag",
+			eventType: events.TweetCreated,
+			payload:   events.TweetCreatedPayload{TweetID: "3", Username: "celeb", CreatedAt: now},
+			handle:    func(i *TimelineInvalidator) events.Handler { return i.HandleTweetCreated },
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"celeb"}).Return([]string{"celeb"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("InvalidateTag", mock.Anything, "celebrity:celeb").Return(nil)
+			},
+		},
+		{
+			name:      "celebrity tweet deleted invalidates the celebrity tag",
+			eventType: events.TweetDeleted,
+			payload:   events.TweetDeletedPayload{TweetID: "3", Username: "celeb", DeletedAt: now},
+			handle:    func(i *TimelineInvalidator) events.Handler { return i.HandleTweetDeleted },
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"celeb"}).Return([]string{"celeb"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("InvalidateTag", mock.Anything, "celebrity:celeb").Return(nil)
+			},
+		},
+		{
+			name:      "tweet deleted is removed from followers timelines",
+			eventType: events.TweetDeleted,
+			payload:   events.TweetDeletedPayload{TweetID: "3", Username: "author", DeletedAt: now},
+			handle:    func(i *TimelineInvalidator) events.Handler { return i.HandleTweetDeleted },
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{}, nil)
+			},
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{"user1", "user2"}, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("RemoveTweet", mock.Anything, []string{"user1", "user2"}, "3").Return(nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("InvalidateTag", mock.Anything, "timeline:user1").Return(nil)
+				m.On("InvalidateTag", mock.Anything, "timeline:user2").Return(nil)
+			},
+		},
+		{
+			name:      "follow backfills and invalidates follower timeline",
+			eventType: events.UserFollowed,
+			payload:   events.UserFollowedPayload{Username: "follower", Following: "followee", CreatedAt: now},
+			handle:    func(i *TimelineInvalidator) events.Handler { return i.HandleUserFollowed },
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsForUsers", mock.Anything, []string{"followee"}, 0, 100).Return(followeeTweets, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("AddTweets", mock.Anything, "follower", followeeTweets).Return(nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("InvalidateTag", mock.Anything, "timeline:follower").Return(nil)
+			},
+		},
+		{
+			name:      "unfollow removes tweets and invalidates follower timeline",
+			eventType: events.UserUnfollowed,
+			payload:   events.UserUnfollowedPayload{Username: "follower", Following: "followee", UnfollowedAt: now},
+			handle:    func(i *TimelineInvalidator) events.Handler { return i.HandleUserUnfollowed },
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsForUsers", mock.Anything, []string{"followee"}, 0, 100).Return(followeeTweets, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("RemoveTweets", mock.Anything, "follower", []string{"2", "1"}).Return(nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("InvalidateTag", mock.Anything, "timeline:follower").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := new(mockUserChecker)
+			repo := new(mockTimelineRepository)
+			cache := new(mockCacheRepository)
+			home := new(mockHomeTimelineRepository)
+			celebrities := new(mockCelebrityRepository)
+			if tt.checkerSetup != nil {
+				tt.checkerSetup(checker)
+			}
+			if tt.repoSetup != nil {
+				tt.repoSetup(repo)
+			}
+			if tt.cacheSetup != nil {
+				tt.cacheSetup(cache)
+			}
+			if tt.homeSetup != nil {
+				tt.homeSetup(home)
+			}
+			if tt.celebSetup != nil {
+				tt.celebSetup(celebrities)
+			}
+
+			evt, err := events.New(events.TopicUsers, tt.eventType, 1, "key", tt.payload)
+			assert.NoError(t, err)
+
+			invalidator := NewTimelineInvalidator(repo, checker, cache, home, celebrities, TimelineConfig{HomeTimelineLength: 100})
+			assert.NoError(t, tt.handle(invalidator)(context.Background(), evt))
+
+			checker.AssertExpectations(t)
+			repo.AssertExpectations(t)
+			cache.AssertExpectations(t)
+			home.AssertExpectations(t)
+			celebrities.AssertExpectations(t)
+		})
+	}
+}
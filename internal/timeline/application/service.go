@@ -3,14 +3,18 @@ package application
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/nicodelara/microblogging-uala/internal/common"
 	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
 	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
 )
 
+// newTweetsCountLimit es la cantidad máxima de tweets nuevos que se cuentan;
+// los clientes muestran a partir de ahí que hay "más de" esa cantidad
+const newTweetsCountLimit = 100
+
 type TweetView struct {
 	TweetID   string `json:"tweetId"`
 	Username  string `json:"username"`
@@ -18,29 +22,51 @@ type TweetView struct {
 	CreatedAt string `json:"createdAt"`
 }
 
+// TimelineConfig agrupa los parámetros del servicio de timeline
+type TimelineConfig struct {
+	// HomeTimelineLength es la cantidad de tweets que se conservan en cada
+	// timeline precalculado. Las páginas fuera de esa ventana se leen de MongoDB.
+	HomeTimelineLength int
+
+	// CelebrityFollowerThreshold es la cantidad de seguidores a partir de la
+	// cual los tweets de un autor no se distribuyen por fan-out
+	CelebrityFollowerThreshold int64
+}
+
 type timelineService struct {
-	repo        ports.TimelineRepository
-	userChecker common.UserChecker
-	cache       ports.CacheRepository
+	repo          ports.TimelineRepository
+	userChecker   common.UserChecker
+	cache         ports.CacheLoader
+	homeTimelines ports.HomeTimelineRepository
+	celebrities   ports.CelebrityRepository
+	authors       ports.AuthorRepository
+	cfg           TimelineConfig
 }
 
 func NewTimelineService(
 	repo ports.TimelineRepository,
 	userChecker common.UserChecker,
-	cache ports.CacheRepository,
+	cache ports.CacheLoader,
+	homeTimelines ports.HomeTimelineRepository,
+	celebrities ports.CelebrityRepository,
+	authors ports.AuthorRepository,
+	cfg TimelineConfig,
 ) ports.TimelineService {
 	return &timelineService{
-		repo:        repo,
-		userChecker: userChecker,
-		cache:       cache,
+		repo:          repo,
+		userChecker:   userChecker,
+		cache:         cache,
+		homeTimelines: homeTimelines,
+		celebrities:   celebrities,
+		authors:       authors,
+		cfg:           cfg,
 	}
 }
 
-func (s *timelineService) GetTimeline(ctx context.Context, username string, offset, limit int) (*domain.Timeline, error) {
+func (s *timelineService) GetTimeline(ctx context.Context, username string, page domain.Page) (*domain.Timeline, error) {
 	// Verificar que el usuario existe
-	_, err := s.userChecker.GetUser(username)
-	if err != nil {
-		return nil, errors.New("user does not exist")
+	if _, err := s.userChecker.GetUser(username); err != nil {
+		return nil, ErrUserNotFound
 	}
 
 	// Obtener la lista de followings
@@ -52,45 +78,444 @@ func (s *timelineService) GetTimeline(ctx context.Context, username string, offs
 		return domain.NewTimeline(username), nil
 	}
 
-	// Generar clave de cache con offset y limit
-	cacheKey := fmt.Sprintf("timeline:%s:offset=%d:limit=%d", username, offset, limit)
-	cached, err := s.cache.Get(ctx, cacheKey)
-	if err == nil && cached != "" {
-		var cachedTweets []domain.Tweet
-		if err := json.Unmarshal([]byte(cached), &cachedTweets); err == nil {
-			timeline := domain.NewTimeline(username)
-			for _, tweet := range cachedTweets {
-				if err := timeline.AddTweet(tweet); err != nil {
-					return nil, err
-				}
-			}
-			return timeline, nil
+	// Un since por id se resuelve a su cursor para compartir la clave de cache
+	if page.Since != nil {
+		since, err := s.resolveSince(ctx, *page.Since)
+		if err != nil {
+			return nil, err
 		}
+		page.Since = &domain.Since{Cursor: &since}
 	}
 
-	// Obtener tweets de los usuarios seguidos
-	tweets, err := s.repo.GetTweetsForUsers(ctx, followings, offset, limit)
+	// Generar clave de cache según el modo de paginación. Las lecturas
+	// concurrentes de una página que no está en cache comparten la consulta.
+	cacheKey := timelineCacheKey(username, page)
+	cached, err := s.cache.Load(ctx, cacheKey, func(ctx context.Context) (string, []string, error) {
+		tweets, err := s.fetchTweets(ctx, username, followings, page)
+		if err != nil {
+			return "", nil, err
+		}
+		tweetsJSON, err := json.Marshal(tweets)
+		return string(tweetsJSON), s.cacheTags(ctx, username, followings), err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(tweets) == 0 {
-		return domain.NewTimeline(username), nil
+	var tweets []domain.Tweet
+	if err := json.Unmarshal([]byte(cached), &tweets); err != nil {
+		return nil, err
 	}
 
 	// Crear timeline
 	timeline := domain.NewTimeline(username)
 	for _, tweet := range tweets {
+		if tweet.IsOrphanRetweet() {
+			continue
+		}
 		if err := timeline.AddTweet(tweet); err != nil {
 			return nil, err
 		}
 	}
+	timeline.SetNextCursor(tweets, page.Limit)
 
-	// Guardar en cache
-	tweetsJSON, err := json.Marshal(tweets)
-	if err == nil {
-		_ = s.cache.Set(ctx, cacheKey, string(tweetsJSON))
+	decorateAuthors(ctx, s.authors, timeline.Tweets)
+	return timeline, nil
+}
+
+// fetchTweets obtiene los tweets de una página del timeline con sus retweets
+// resueltos, listos para guardar en cache
+func (s *timelineService) fetchTweets(ctx context.Context, username string, followings []string, page domain.Page) ([]domain.Tweet, error) {
+	var tweets []domain.Tweet
+	var err error
+	switch {
+	case page.Since != nil:
+		tweets, err = s.loadTweetsAfter(ctx, username, followings, *page.Since.Cursor, page.Limit)
+	case page.Cursor != nil:
+		tweets, err = s.loadTweetsBefore(ctx, username, followings, *page.Cursor, page.Limit)
+	default:
+		tweets, err = s.loadTweets(ctx, username, followings, page.Offset, page.Limit)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return timeline, nil
+	return resolveRetweets(ctx, s.repo, tweets)
+}
+
+func (s *timelineService) CountNewTweets(ctx context.Context, username string, since domain.Since) (*domain.NewTweetsCount, error) {
+	if _, err := s.userChecker.GetUser(username); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	followings, err := s.userChecker.GetFollowings(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if len(followings) == 0 {
+		return &domain.NewTweetsCount{}, nil
+	}
+
+	cursor, err := s.resolveSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	// La cuenta se cachea con el tag del timeline, por lo que los clientes que
+	// consultan periódicamente con el mismo since la leen del cache hasta que
+	// llega un tweet nuevo
+	cacheKey := newTweetsCountCacheKey(username, cursor)
+	cached, err := s.cache.Load(ctx, cacheKey, func(ctx context.Context) (string, []string, error) {
+		total, err := s.countTweetsAfter(ctx, username, followings, cursor, newTweetsCountLimit+1)
+		if err != nil {
+			return "", nil, err
+		}
+		countJSON, err := json.Marshal(domain.NewTweetsCount{
+			Count:   min(total, newTweetsCountLimit),
+			HasMore: total > newTweetsCountLimit,
+		})
+		return string(countJSON), s.cacheTags(ctx, username, followings), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var count domain.NewTweetsCount
+	if err := json.Unmarshal([]byte(cached), &count); err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// GetTweetsSince no usa el caché porque una página cacheada puede no tener
+// los tweets publicados después de guardarla, y quien la usa, como la
+// reanudación de un stream, no volvería a pedirlos
+func (s *timelineService) GetTweetsSince(ctx context.Context, username string, since domain.Cursor, limit int) ([]domain.Tweet, error) {
+	followings, err := s.userChecker.GetFollowings(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if len(followings) == 0 {
+		return nil, nil
+	}
+
+	page := domain.Page{Limit: limit, Since: &domain.Since{Cursor: &since}}
+	fetched, err := s.fetchTweets(ctx, username, followings, page)
+	if err != nil {
+		return nil, err
+	}
+
+	tweets := make([]domain.Tweet, 0, len(fetched))
+	for _, tweet := range fetched {
+		if !tweet.IsOrphanRetweet() {
+			tweets = append(tweets, tweet)
+		}
+	}
+	decorateAuthors(ctx, s.authors, tweets)
+	return tweets, nil
+}
+
+// resolveSince obtiene el cursor del tweet indicado por since. Un tweet
+// eliminado sigue resolviéndose, porque el cliente pudo haberlo visto antes.
+func (s *timelineService) resolveSince(ctx context.Context, since domain.Since) (domain.Cursor, error) {
+	if since.Cursor != nil {
+		return *since.Cursor, nil
+	}
+
+	cursor, err := s.repo.GetTweetCursor(ctx, since.TweetID)
+	if err != nil {
+		return domain.Cursor{}, err
+	}
+	if cursor == nil {
+		return domain.Cursor{}, ErrTweetNotFound
+	}
+	return *cursor, nil
+}
+
+// resolveRetweets completa cada retweet con su tweet original. Se aplica antes
+// de guardar en caché; los retweets cuyo original fue eliminado quedan sin
+// Original para que la página conserve su tamaño y se descartan al mostrarla.
+func resolveRetweets(ctx context.Context, repo ports.TimelineRepository, tweets []domain.Tweet) ([]domain.Tweet, error) {
+	var ids []string
+	for _, tweet := range tweets {
+		if tweet.IsRetweet() {
+			ids = append(ids, tweet.RetweetOf)
+		}
+	}
+	if len(ids) == 0 {
+		return tweets, nil
+	}
+
+	originals, err := repo.GetTweetsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.Tweet, len(originals))
+	for _, original := range originals {
+		byID[original.ID] = original
+	}
+
+	resolved := make([]domain.Tweet, 0, len(tweets))
+	for _, tweet := range tweets {
+		if tweet.IsRetweet() {
+			if original, ok := byID[tweet.RetweetOf]; ok {
+				tweet.Original = &original
+			}
+		}
+		resolved = append(resolved, tweet)
+	}
+	return resolved, nil
+}
+
+// decorateAuthors completa los datos de perfil de los autores de los tweets.
+// Se aplica después del caché para que un cambio de perfil se refleje sin
+// invalidar las páginas cacheadas; si falla, los tweets se devuelven sin ellos.
+func decorateAuthors(ctx context.Context, repo ports.AuthorRepository, page []domain.Tweet) {
+	// Los retweets muestran al autor del tweet original
+	tweets := make([]*domain.Tweet, 0, len(page))
+	for i := range page {
+		tweets = append(tweets, &page[i])
+		if page[i].Original != nil {
+			tweets = append(tweets, page[i].Original)
+		}
+	}
+
+	usernames := make([]string, 0, len(tweets))
+	seen := make(map[string]bool, len(tweets))
+	for _, tweet := range tweets {
+		if !seen[tweet.Username] {
+			seen[tweet.Username] = true
+			usernames = append(usernames, tweet.Username)
+		}
+	}
+
+	authors, err := repo.GetAuthors(ctx, usernames)
+	if err != nil {
+		return
+	}
+	for _, tweet := range tweets {
+		if author, ok := authors[tweet.Username]; ok {
+			tweet.DisplayName = author.DisplayName
+			tweet.AvatarURL = author.AvatarURL
+		}
+	}
+}
+
+// timelineCacheKey genera la clave de cache de una página del timeline
+func timelineCacheKey(username string, page domain.Page) string {
+	if page.Since != nil {
+		return fmt.Sprintf("timeline:%s:since=%s:limit=%d", username, page.Since.Cursor.Encode(), page.Limit)
+	}
+	if page.Cursor != nil {
+		return fmt.Sprintf("timeline:%s:cursor=%s:limit=%d", username, page.Cursor.Encode(), page.Limit)
+	}
+	return fmt.Sprintf("timeline:%s:offset=%d:limit=%d", username, page.Offset, page.Limit)
+}
+
+// newTweetsCountCacheKey genera la clave de cache de la cantidad de tweets
+// nuevos del timeline de un usuario desde un cursor
+func newTweetsCountCacheKey(username string, since domain.Cursor) string {
+	return fmt.Sprintf("timeline:%s:newcount:since=%s", username, since.Encode())
+}
+
+// timelineCacheTag es el tag que agrupa todas las páginas cacheadas del
+// timeline de un usuario, para poder invalidarlas juntas
+func timelineCacheTag(username string) string {
+	return "timeline:" + username
+}
+
+// celebrityCacheTag es el tag que agrupa las páginas cacheadas de los
+// timelines que siguen a una celebridad. Sus tweets no se distribuyen por
+// fan-out, por lo que se invalidan con una única operación en lugar de
+// recorrer a todos sus seguidores.
+func celebrityCacheTag(username string) string {
+	return "celebrity:" + username
+}
+
+// cacheTags devuelve los tags con los que se cachean las páginas del timeline
+// de un usuario. Si no se pueden obtener las celebridades seguidas, sus
+// tweets nuevos o eliminados se reflejan al vencer el TTL.
+func (s *timelineService) cacheTags(ctx context.Context, username string, followings []string) []string {
+	tags := []string{timelineCacheTag(username)}
+	celebrities, err := s.celebrities.FilterCelebrities(ctx, followings)
+	if err != nil {
+		return tags
+	}
+	for _, celebrity := range celebrities {
+		tags = append(tags, celebrityCacheTag(celebrity))
+	}
+	return tags
+}
+
+// loadTweets obtiene los tweets del timeline precalculado cuando la página cae
+// dentro de la ventana almacenada, y de MongoDB en caso contrario o si el
+// usuario todavía no tiene timeline precalculado. Los tweets de las
+// celebridades seguidas se mezclan en el momento de la lectura.
+func (s *timelineService) loadTweets(ctx context.Context, username string, followings []string, offset, limit int) ([]domain.Tweet, error) {
+	if offset+limit > s.cfg.HomeTimelineLength {
+		return s.repo.GetTweetsForUsers(ctx, followings, offset, limit)
+	}
+
+	celebrities, err := s.celebrities.FilterCelebrities(ctx, followings)
+	if err != nil {
+		return s.repo.GetTweetsForUsers(ctx, followings, offset, limit)
+	}
+
+	if len(celebrities) == 0 {
+		ids, found, err := s.homeTimelines.GetTweetIDs(ctx, username, offset, limit)
+		if err != nil || !found {
+			if err == nil && !found {
+				s.seedHomeTimeline(ctx, username, followings, celebrities)
+			}
+			return s.repo.GetTweetsForUsers(ctx, followings, offset, limit)
+		}
+		return s.repo.GetTweetsByIDs(ctx, ids)
+	}
+
+	// Timeline híbrido: los primeros offset+limit tweets de la mezcla están
+	// contenidos en los primeros offset+limit de cada fuente
+	ids, found, err := s.homeTimelines.GetTweetIDs(ctx, username, 0, offset+limit)
+	if err != nil || !found {
+		if err == nil && !found {
+			s.seedHomeTimeline(ctx, username, followings, celebrities)
+		}
+		return s.repo.GetTweetsForUsers(ctx, followings, offset, limit)
+	}
+
+	precomputed, err := s.repo.GetTweetsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	celebrityTweets, err := s.repo.GetTweetsForUsers(ctx, celebrities, 0, offset+limit)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := domain.MergeTweets(precomputed, celebrityTweets)
+	if offset >= len(merged) {
+		return nil, nil
+	}
+	return merged[offset:min(offset+limit, len(merged))], nil
+}
+
+// loadTweetsBefore obtiene los limit tweets siguientes al cursor. Usa el
+// timeline precalculado mientras tenga tweets suficientes después del cursor;
+// cuando se agota la ventana almacenada se continúa desde MongoDB.
+func (s *timelineService) loadTweetsBefore(ctx context.Context, username string, followings []string, cursor domain.Cursor, limit int) ([]domain.Tweet, error) {
+	celebrities, err := s.celebrities.FilterCelebrities(ctx, followings)
+	if err != nil {
+		return s.repo.GetTweetsForUsersBefore(ctx, followings, cursor, limit)
+	}
+
+	ids, found, err := s.homeTimelines.GetTweetIDsBefore(ctx, username, cursor, limit)
+	if err != nil || !found || len(ids) < limit {
+		if err == nil && !found {
+			s.seedHomeTimeline(ctx, username, followings, celebrities)
+		}
+		return s.repo.GetTweetsForUsersBefore(ctx, followings, cursor, limit)
+	}
+
+	precomputed, err := s.repo.GetTweetsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(celebrities) == 0 {
+		return precomputed, nil
+	}
+
+	celebrityTweets, err := s.repo.GetTweetsForUsersBefore(ctx, celebrities, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := domain.MergeTweets(precomputed, celebrityTweets)
+	return merged[:min(limit, len(merged))], nil
+}
+
+// loadTweetsAfter obtiene los limit tweets más recientes que since, del
+// timeline precalculado si el usuario lo tiene y de MongoDB si no. Los tweets
+// de las celebridades seguidas se mezclan en el momento de la lectura.
+func (s *timelineService) loadTweetsAfter(ctx context.Context, username string, followings []string, since domain.Cursor, limit int) ([]domain.Tweet, error) {
+	celebrities, err := s.celebrities.FilterCelebrities(ctx, followings)
+	if err != nil {
+		return s.repo.GetTweetsForUsersAfter(ctx, followings, since, limit)
+	}
+
+	ids, found, err := s.homeTimelines.GetTweetIDsAfter(ctx, username, since, limit)
+	if err != nil || !found {
+		if err == nil && !found {
+			s.seedHomeTimeline(ctx, username, followings, celebrities)
+		}
+		return s.repo.GetTweetsForUsersAfter(ctx, followings, since, limit)
+	}
+
+	precomputed, err := s.repo.GetTweetsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(celebrities) == 0 {
+		return precomputed, nil
+	}
+
+	celebrityTweets, err := s.repo.GetTweetsForUsersAfter(ctx, celebrities, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := domain.MergeTweets(precomputed, celebrityTweets)
+	return merged[:min(limit, len(merged))], nil
+}
+
+// countTweetsAfter cuenta, hasta un máximo de limit, los tweets más recientes
+// que since. El timeline precalculado se cuenta en Redis y sólo los tweets de
+// las celebridades seguidas requieren una consulta a MongoDB.
+func (s *timelineService) countTweetsAfter(ctx context.Context, username string, followings []string, since domain.Cursor, limit int) (int, error) {
+	celebrities, err := s.celebrities.FilterCelebrities(ctx, followings)
+	if err != nil {
+		return s.repo.CountTweetsForUsersAfter(ctx, followings, since, limit)
+	}
+
+	// Si el timeline precalculado está completo y since es anterior a su
+	// ventana la cuenta de Redis es un mínimo, que sólo sirve si ya alcanza limit
+	count, found, err := s.homeTimelines.CountTweetIDsAfter(ctx, username, since)
+	if err != nil || !found || (count >= s.cfg.HomeTimelineLength && count < limit) {
+		if err == nil && !found {
+			s.seedHomeTimeline(ctx, username, followings, celebrities)
+		}
+		return s.repo.CountTweetsForUsersAfter(ctx, followings, since, limit)
+	}
+	if len(celebrities) == 0 || count >= limit {
+		return min(count, limit), nil
+	}
+
+	celebrityCount, err := s.repo.CountTweetsForUsersAfter(ctx, celebrities, since, limit-count)
+	if err != nil {
+		return 0, err
+	}
+	return count + celebrityCount, nil
+}
+
+// seedHomeTimeline inicializa el timeline precalculado de un usuario que no lo
+// tiene, ya sea porque nunca se leyó o porque Redis lo perdió, con los tweets
+// más recientes de los autores seguidos que no son celebridades. La lectura
+// actual sigue desde MongoDB; si la inicialización falla se vuelve a intentar
+// en la próxima lectura.
+func (s *timelineService) seedHomeTimeline(ctx context.Context, username string, followings, celebrities []string) {
+	authors := make([]string, 0, len(followings))
+	for _, following := range followings {
+		if !slices.Contains(celebrities, following) {
+			authors = append(authors, following)
+		}
+	}
+
+	var tweets []domain.Tweet
+	if len(authors) > 0 {
+		var err error
+		tweets, err = s.repo.GetTweetsForUsers(ctx, authors, 0, s.cfg.HomeTimelineLength)
+		if err != nil {
+			return
+		}
+	}
+	_ = s.homeTimelines.SeedTweets(ctx, username, tweets)
 }
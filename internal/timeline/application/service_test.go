@@ -8,6 +8,7 @@ import (
 	"time"
 
 	timelinedomain "github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
 	usersdomain "github.com/nicodelara/microblogging-uala/internal/users/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,6 +23,39 @@ func (m *mockTimelineRepository) GetTweetsForUsers(ctx context.Context, username
 	return args.Get(0).([]timelinedomain.Tweet), args.Error(1)
 }
 
+func (m *mockTimelineRepository) GetTweetsForUsersBefore(ctx context.Context, usernames []string, cursor timelinedomain.Cursor, limit int) ([]timelinedomain.Tweet, error) {
+	args := m.Called(ctx, usernames, cursor, limit)
+	return args.Get(0).([]timelinedomain.Tweet), args.Error(1)
+}
+
+func (m *mockTimelineRepository) GetTweetsForUsersAfter(ctx context.Context, usernames []string, since timelinedomain.Cursor, limit int) ([]timelinedomain.Tweet, error) {
+	args := m.Called(ctx, usernames, since, limit)
+	return args.Get(0).([]timelinedomain.Tweet), args.Error(1)
+}
+
+func (m *mockTimelineRepository) CountTweetsForUsersAfter(ctx context.Context, usernames []string, since timelinedomain.Cursor, limit int) (int, error) {
+	args := m.Called(ctx, usernames, since, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockTimelineRepository) GetTweetsByIDs(ctx context.Context, ids []string) ([]timelinedomain.Tweet, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]timelinedomain.Tweet), args.Error(1)
+}
+
+func (m *mockTimelineRepository) GetTweetCursor(ctx context.Context, id string) (*timelinedomain.Cursor, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*timelinedomain.Cursor), args.Error(1)
+}
+
+func (m *mockTimelineRepository) GetTweetsByHashtag(ctx context.Context, tag string, cursor *timelinedomain.Cursor, limit int) ([]timelinedomain.Tweet, error) {
+	args := m.Called(ctx, tag, cursor, limit)
+	return args.Get(0).([]timelinedomain.Tweet), args.Error(1)
+}
+
 func (m *mockTimelineRepository) SaveTweet(ctx context.Context, tweet timelinedomain.Tweet) error {
 	args := m.Called(ctx, tweet)
 	return args.Error(0)
@@ -44,6 +78,16 @@ func (m *mockUserChecker) GetFollowings(ctx context.Context, username string) ([
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *mockUserChecker) GetFollowers(ctx context.Context, username string) ([]string, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserChecker) CountFollowers(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type mockCacheRepository struct {
 	mock.Mock
 }
@@ -53,21 +97,125 @@ func (m *mockCacheRepository) Get(ctx context.Context, key string) (string, erro
 	return args.String(0), args.Error(1)
 }
 
-func (m *mockCacheRepository) Set(ctx context.Context, key string, value string) error {
-	args := m.Called(ctx, key, value)
+func (m *mockCacheRepository) Lookup(ctx context.Context, key string) (string, bool, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Bool(1), args.Error(2)
+}
+
+func (m *mockCacheRepository) Set(ctx context.Context, key string, value string, tags ...string) error {
+	args := m.Called(ctx, key, value, tags)
+	return args.Error(0)
+}
+
+func (m *mockCacheRepository) Delete(ctx context.Context, keys ...string) error {
+	args := m.Called(ctx, keys)
+	return args.Error(0)
+}
+
+func (m *mockCacheRepository) InvalidateTag(ctx context.Context, tag string) error {
+	args := m.Called(ctx, tag)
+	return args.Error(0)
+}
+
+func (m *mockCacheRepository) TagKeys(ctx context.Context, tag string) ([]string, error) {
+	args := m.Called(ctx, tag)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type mockHomeTimelineRepository struct {
+	mock.Mock
+}
+
+func (m *mockHomeTimelineRepository) AddTweet(ctx context.Context, usernames []string, tweetID string, createdAt time.Time) error {
+	args := m.Called(ctx, usernames, tweetID, createdAt)
+	return args.Error(0)
+}
+
+func (m *mockHomeTimelineRepository) AddTweets(ctx context.Context, username string, tweets []timelinedomain.Tweet) error {
+	args := m.Called(ctx, username, tweets)
+	return args.Error(0)
+}
+
+func (m *mockHomeTimelineRepository) SeedTweets(ctx context.Context, username string, tweets []timelinedomain.Tweet) error {
+	args := m.Called(ctx, username, tweets)
+	return args.Error(0)
+}
+
+func (m *mockHomeTimelineRepository) RemoveTweets(ctx context.Context, username string, tweetIDs []string) error {
+	args := m.Called(ctx, username, tweetIDs)
+	return args.Error(0)
+}
+
+func (m *mockHomeTimelineRepository) GetTweetIDs(ctx context.Context, username string, offset, limit int) ([]string, bool, error) {
+	args := m.Called(ctx, username, offset, limit)
+	return args.Get(0).([]string), args.Bool(1), args.Error(2)
+}
+
+func (m *mockHomeTimelineRepository) GetTweetIDsBefore(ctx context.Context, username string, cursor timelinedomain.Cursor, limit int) ([]string, bool, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	return args.Get(0).([]string), args.Bool(1), args.Error(2)
+}
+
+func (m *mockHomeTimelineRepository) GetTweetIDsAfter(ctx context.Context, username string, since timelinedomain.Cursor, limit int) ([]string, bool, error) {
+	args := m.Called(ctx, username, since, limit)
+	return args.Get(0).([]string), args.Bool(1), args.Error(2)
+}
+
+func (m *mockHomeTimelineRepository) CountTweetIDsAfter(ctx context.Context, username string, since timelinedomain.Cursor) (int, bool, error) {
+	args := m.Called(ctx, username, since)
+	return args.Int(0), args.Bool(1), args.Error(2)
+}
+
+func (m *mockHomeTimelineRepository) RemoveTweet(ctx context.Context, usernames []string, tweetID string) error {
+	args := m.Called(ctx, usernames, tweetID)
+	return args.Error(0)
+}
+
+type mockCelebrityRepository struct {
+	mock.Mock
+}
+
+func (m *mockCelebrityRepository) SetCelebrity(ctx context.Context, username string, celebrity bool) error {
+	args := m.Called(ctx, username, celebrity)
+	return args.Error(0)
+}
+
+func (m *mockCelebrityRepository) FilterCelebrities(ctx context.Context, usernames []string) ([]string, error) {
+	args := m.Called(ctx, usernames)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type mockAuthorRepository struct {
+	mock.Mock
+}
+
+func (m *mockAuthorRepository) SaveAuthor(ctx context.Context, author timelinedomain.Author) error {
+	args := m.Called(ctx, author)
 	return args.Error(0)
 }
 
+func (m *mockAuthorRepository) GetAuthors(ctx context.Context, usernames []string) (map[string]timelinedomain.Author, error) {
+	args := m.Called(ctx, usernames)
+	return args.Get(0).(map[string]timelinedomain.Author), args.Error(1)
+}
+
 func TestTimelineService_GetTimeline(t *testing.T) {
 	now := time.Now()
+	cursor := timelinedomain.Cursor{CreatedAt: now.Add(-1 * time.Minute), ID: "p3"}
 	tests := []struct {
 		name          string
 		username      string
 		offset        int
 		limit         int
+		cursor        *timelinedomain.Cursor
+		since         *timelinedomain.Since
 		checkerSetup  func(*mockUserChecker)
 		repoSetup     func(*mockTimelineRepository)
 		cacheSetup    func(*mockCacheRepository)
+		homeSetup     func(*mockHomeTimelineRepository)
+		celebSetup    func(*mockCelebrityRepository)
+		expectedIDs   []string
+		expectedNext  string
 		expectedError error
 	}{
 		{
@@ -85,7 +233,7 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 					{ID: "2", Username: "user2", Content: "Tweet 2", CreatedAt: now},
 				}
 				tweetsJSON, _ := json.Marshal(tweets)
-				m.On("Get", mock.Anything, "timeline:testuser:offset=0:limit=10").Return(string(tweetsJSON), nil)
+				m.On("Lookup", mock.Anything, "timeline:testuser:offset=0:limit=10").Return(string(tweetsJSON), true, nil)
 			},
 			expectedError: nil,
 		},
@@ -99,13 +247,13 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1", "user2"}, nil)
 			},
 			cacheSetup: func(m *mockCacheRepository) {
-				m.On("Get", mock.Anything, "timeline:testuser:offset=0:limit=10").Return("", nil)
+				m.On("Lookup", mock.Anything, "timeline:testuser:offset=0:limit=10").Return("", false, nil)
 				tweets := []timelinedomain.Tweet{
 					{ID: "1", Username: "user1", Content: "Tweet 1", CreatedAt: now},
 					{ID: "2", Username: "user2", Content: "Tweet 2", CreatedAt: now},
 				}
 				tweetsJSON, _ := json.Marshal(tweets)
-				m.On("Set", mock.Anything, "timeline:testuser:offset=0:limit=10", string(tweetsJSON)).Return(nil)
+				m.On("Set", mock.Anything, "timeline:testuser:offset=0:limit=10", string(tweetsJSON), []string{"timeline:testuser"}).Return(nil)
 			},
 			repoSetup: func(m *mockTimelineRepository) {
 				tweets := []timelinedomain.Tweet{
@@ -113,9 +261,164 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 					{ID: "2", Username: "user2", Content: "Tweet 2", CreatedAt: now},
 				}
 				m.On("GetTweetsForUsers", mock.Anything, []string{"user1", "user2"}, 0, 10).Return(tweets, nil)
+				m.On("GetTweetsForUsers", mock.Anything, []string{"user1", "user2"}, 0, 100).Return(tweets, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("GetTweetIDs", mock.Anything, "testuser", 0, 10).Return([]string{}, false, nil)
+				// El timeline que no está inicializado se carga desde MongoDB
+				m.On("SeedTweets", mock.Anything, "testuser", mock.Anything).Return(nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "user2"}).Return([]string{}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "successful retrieval from precomputed timeline",
+			username: "testuser",
+			offset:   0,
+			limit:    10,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1", "user2"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("Lookup", mock.Anything, "timeline:testuser:offset=0:limit=10").Return("", false, nil)
+				m.On("Set", mock.Anything, "timeline:testuser:offset=0:limit=10", mock.Anything, []string{"timeline:testuser"}).Return(nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("GetTweetIDs", mock.Anything, "testuser", 0, 10).Return([]string{"2", "1"}, true, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "user2"}).Return([]string{}, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				tweets := []timelinedomain.Tweet{
+					{ID: "2", Username: "user2", Content: "Tweet 2", CreatedAt: now},
+					{ID: "1", Username: "user1", Content: "Tweet 1", CreatedAt: now},
+				}
+				m.On("GetTweetsByIDs", mock.Anything, []string{"2", "1"}).Return(tweets, nil)
+			},
+			expectedIDs:   []string{"2", "1"},
+			expectedError: nil,
+		},
+		{
+			name:     "merges celebrity tweets with precomputed timeline",
+			username: "testuser",
+			offset:   1,
+			limit:    2,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1", "celeb"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("Lookup", mock.Anything, "timeline:testuser:offset=1:limit=2").Return("", false, nil)
+				m.On("Set", mock.Anything, "timeline:testuser:offset=1:limit=2", mock.Anything, []string{"timeline:testuser", "celebrity:celeb"}).Return(nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "celeb"}).Return([]string{"celeb"}, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("GetTweetIDs", mock.Anything, "testuser", 0, 3).Return([]string{"p3", "p1"}, true, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				precomputed := []timelinedomain.Tweet{
+					{ID: "p3", Username: "user1", Content: "Tweet p3", CreatedAt: now.Add(-1 * time.Minute)},
+					{ID: "p1", Username: "user1", Content: "Tweet p1", CreatedAt: now.Add(-3 * time.Minute)},
+				}
+				celebrity := []timelinedomain.Tweet{
+					{ID: "c4", Username: "celeb", Content: "Tweet c4", CreatedAt: now},
+					{ID: "c2", Username: "celeb", Content: "Tweet c2", CreatedAt: now.Add(-2 * time.Minute)},
+					{ID: "c0", Username: "celeb", Content: "Tweet c0", CreatedAt: now.Add(-4 * time.Minute)},
+				}
+				m.On("GetTweetsByIDs", mock.Anything, []string{"p3", "p1"}).Return(precomputed, nil)
+				m.On("GetTweetsForUsers", mock.Anything, []string{"celeb"}, 0, 3).Return(celebrity, nil)
 			},
+			expectedIDs:   []string{"p3", "c2"},
+			expectedNext:  timelinedomain.CursorFor(timelinedomain.Tweet{ID: "c2", CreatedAt: now.Add(-2 * time.Minute)}).Encode(),
 			expectedError: nil,
 		},
+		{
+			name:     "page outside precomputed window reads from repository",
+			username: "testuser",
+			offset:   95,
+			limit:    10,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("Lookup", mock.Anything, "timeline:testuser:offset=95:limit=10").Return("", false, nil)
+				// Las páginas vacías también se cachean
+				m.On("Set", mock.Anything, "timeline:testuser:offset=95:limit=10", "[]", []string{"timeline:testuser"}).Return(nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsForUsers", mock.Anything, []string{"user1"}, 95, 10).Return([]timelinedomain.Tweet{}, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1"}).Return([]string{}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "cursor page merges celebrity tweets after the cursor",
+			username: "testuser",
+			limit:    2,
+			cursor:   &cursor,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1", "celeb"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				key := "timeline:testuser:cursor=" + cursor.Encode() + ":limit=2"
+				m.On("Lookup", mock.Anything, key).Return("", false, nil)
+				m.On("Set", mock.Anything, key, mock.Anything, []string{"timeline:testuser", "celebrity:celeb"}).Return(nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "celeb"}).Return([]string{"celeb"}, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("GetTweetIDsBefore", mock.Anything, "testuser", cursor, 2).Return([]string{"p2", "p1"}, true, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsByIDs", mock.Anything, []string{"p2", "p1"}).Return([]timelinedomain.Tweet{
+					{ID: "p2", Username: "user1", Content: "Tweet p2", CreatedAt: now.Add(-2 * time.Minute)},
+					{ID: "p1", Username: "user1", Content: "Tweet p1", CreatedAt: now.Add(-4 * time.Minute)},
+				}, nil)
+				m.On("GetTweetsForUsersBefore", mock.Anything, []string{"celeb"}, cursor, 2).Return([]timelinedomain.Tweet{
+					{ID: "c3", Username: "celeb", Content: "Tweet c3", CreatedAt: now.Add(-3 * time.Minute)},
+				}, nil)
+			},
+			expectedIDs:  []string{"p2", "c3"},
+			expectedNext: timelinedomain.CursorFor(timelinedomain.Tweet{ID: "c3", CreatedAt: now.Add(-3 * time.Minute)}).Encode(),
+		},
+		{
+			name:     "cursor past precomputed window reads from repository",
+			username: "testuser",
+			limit:    2,
+			cursor:   &cursor,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				key := "timeline:testuser:cursor=" + cursor.Encode() + ":limit=2"
+				m.On("Lookup", mock.Anything, key).Return("", false, nil)
+				m.On("Set", mock.Anything, key, mock.Anything, []string{"timeline:testuser"}).Return(nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1"}).Return([]string{}, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("GetTweetIDsBefore", mock.Anything, "testuser", cursor, 2).Return([]string{"p2"}, true, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsForUsersBefore", mock.Anything, []string{"user1"}, cursor, 2).Return([]timelinedomain.Tweet{
+					{ID: "p2", Username: "user1", Content: "Tweet p2", CreatedAt: now.Add(-2 * time.Minute)},
+				}, nil)
+			},
+			expectedIDs: []string{"p2"},
+		},
 		{
 			name:     "user does not exist",
 			username: "nonexistent",
@@ -124,7 +427,7 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 			checkerSetup: func(m *mockUserChecker) {
 				m.On("GetUser", "nonexistent").Return(nil, errors.New("user does not exist"))
 			},
-			expectedError: errors.New("user does not exist"),
+			expectedError: ErrUserNotFound,
 		},
 		{
 			name:     "no followings",
@@ -137,6 +440,52 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:     "since id of a deleted tweet returns newer tweets merged with celebrities",
+			username: "testuser",
+			limit:    10,
+			since:    &timelinedomain.Since{TweetID: "p3"},
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1", "celeb"}, nil)
+			},
+			cacheSetup: func(m *mockCacheRepository) {
+				key := "timeline:testuser:since=" + cursor.Encode() + ":limit=10"
+				m.On("Lookup", mock.Anything, key).Return("", false, nil)
+				m.On("Set", mock.Anything, key, mock.Anything, []string{"timeline:testuser", "celebrity:celeb"}).Return(nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("GetTweetIDsAfter", mock.Anything, "testuser", cursor, 10).Return([]string{"n1"}, true, nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "celeb"}).Return([]string{"celeb"}, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				// p3 fue eliminado, pero su cursor sigue marcando la posición
+				m.On("GetTweetCursor", mock.Anything, "p3").Return(&cursor, nil)
+				m.On("GetTweetsByIDs", mock.Anything, []string{"n1"}).Return([]timelinedomain.Tweet{
+					{ID: "n1", Username: "user1", Content: "New", CreatedAt: now.Add(-30 * time.Second)},
+				}, nil)
+				m.On("GetTweetsForUsersAfter", mock.Anything, []string{"celeb"}, cursor, 10).Return([]timelinedomain.Tweet{
+					{ID: "c1", Username: "celeb", Content: "Celeb", CreatedAt: now},
+				}, nil)
+			},
+			expectedIDs: []string{"c1", "n1"},
+		},
+		{
+			name:     "since id of an unknown tweet",
+			username: "testuser",
+			limit:    10,
+			since:    &timelinedomain.Since{TweetID: "missing"},
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+				m.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1"}, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetCursor", mock.Anything, "missing").Return(nil, nil)
+			},
+			expectedError: ErrTweetNotFound,
+		},
 	}
 
 	for _, tt := range tests {
@@ -144,6 +493,8 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 			checker := new(mockUserChecker)
 			repo := new(mockTimelineRepository)
 			cache := new(mockCacheRepository)
+			home := new(mockHomeTimelineRepository)
+			celebrities := new(mockCelebrityRepository)
 			tt.checkerSetup(checker)
 			if tt.repoSetup != nil {
 				tt.repoSetup(repo)
@@ -151,9 +502,23 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 			if tt.cacheSetup != nil {
 				tt.cacheSetup(cache)
 			}
+			if tt.homeSetup != nil {
+				tt.homeSetup(home)
+			}
+			if tt.celebSetup != nil {
+				tt.celebSetup(celebrities)
+			}
 
-			service := NewTimelineService(repo, checker, cache)
-			timeline, err := service.GetTimeline(context.Background(), tt.username, tt.offset, tt.limit)
+			authors := new(mockAuthorRepository)
+			authors.On("GetAuthors", mock.Anything, mock.Anything).Return(map[string]timelinedomain.Author{}, nil).Maybe()
+
+			service := NewTimelineService(repo, checker, newTestCacheLoader(cache), home, celebrities, authors, TimelineConfig{HomeTimelineLength: 100})
+			timeline, err := service.GetTimeline(context.Background(), tt.username, timelinedomain.Page{
+				Offset: tt.offset,
+				Limit:  tt.limit,
+				Cursor: tt.cursor,
+				Since:  tt.since,
+			})
 
 			if tt.expectedError != nil {
 				assert.Nil(t, timeline)
@@ -163,9 +528,340 @@ func TestTimelineService_GetTimeline(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.username, timeline.Username)
 			}
+			if tt.expectedIDs != nil {
+				ids := make([]string, len(timeline.Tweets))
+				for i, tweet := range timeline.Tweets {
+					ids[i] = tweet.ID
+				}
+				assert.Equal(t, tt.expectedIDs, ids)
+				assert.Equal(t, tt.expectedNext, timeline.NextCursor)
+			}
 			checker.AssertExpectations(t)
 			repo.AssertExpectations(t)
 			cache.AssertExpectations(t)
+			home.AssertExpectations(t)
+			celebrities.AssertExpectations(t)
 		})
 	}
 }
+
+func TestTimelineService_CountNewTweets(t *testing.T) {
+	since := timelinedomain.Cursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: "t1"}
+	cacheKey := "timeline:testuser:newcount:since=" + since.Encode()
+
+	tests := []struct {
+		name       string
+		followings []string
+		cacheSetup func(*mockCacheRepository)
+		homeSetup  func(*mockHomeTimelineRepository)
+		celebSetup func(*mockCelebrityRepository)
+		repoSetup  func(*mockTimelineRepository)
+		expected   *timelinedomain.NewTweetsCount
+	}{
+		{
+			name:       "answers from cache",
+			followings: []string{"user1"},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("Lookup", mock.Anything, cacheKey).Return(`{"count":3,"hasMore":false}`, true, nil)
+			},
+			expected: &timelinedomain.NewTweetsCount{Count: 3},
+		},
+		{
+			name:       "counts precomputed timeline and celebrity tweets",
+			followings: []string{"user1", "celeb"},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("Lookup", mock.Anything, cacheKey).Return("", false, nil)
+				m.On("Set", mock.Anything, cacheKey, `{"count":12,"hasMore":false}`, []string{"timeline:testuser", "celebrity:celeb"}).Return(nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "celeb"}).Return([]string{"celeb"}, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("CountTweetIDsAfter", mock.Anything, "testuser", since).Return(10, true, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("CountTweetsForUsersAfter", mock.Anything, []string{"celeb"}, since, newTweetsCountLimit+1-10).Return(2, nil)
+			},
+			expected: &timelinedomain.NewTweetsCount{Count: 12},
+		},
+		{
+			name:       "caps the count without querying celebrities",
+			followings: []string{"user1", "celeb"},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("Lookup", mock.Anything, cacheKey).Return("", false, nil)
+				m.On("Set", mock.Anything, cacheKey, mock.Anything, []string{"timeline:testuser", "celebrity:celeb"}).Return(nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "celeb"}).Return([]string{"celeb"}, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("CountTweetIDsAfter", mock.Anything, "testuser", since).Return(250, true, nil)
+			},
+			expected: &timelinedomain.NewTweetsCount{Count: newTweetsCountLimit, HasMore: true},
+		},
+		{
+			name:       "counts in mongo without precomputed timeline",
+			followings: []string{"user1", "user2"},
+			cacheSetup: func(m *mockCacheRepository) {
+				m.On("Lookup", mock.Anything, cacheKey).Return("", false, nil)
+				m.On("Set", mock.Anything, cacheKey, mock.Anything, []string{"timeline:testuser"}).Return(nil)
+			},
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"user1", "user2"}).Return([]string{}, nil)
+			},
+			homeSetup: func(m *mockHomeTimelineRepository) {
+				m.On("CountTweetIDsAfter", mock.Anything, "testuser", since).Return(0, false, nil)
+				m.On("SeedTweets", mock.Anything, "testuser", []timelinedomain.Tweet(nil)).Return(nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("CountTweetsForUsersAfter", mock.Anything, []string{"user1", "user2"}, since, newTweetsCountLimit+1).Return(4, nil)
+				m.On("GetTweetsForUsers", mock.Anything, []string{"user1", "user2"}, 0, 800).Return([]timelinedomain.Tweet(nil), nil)
+			},
+			expected: &timelinedomain.NewTweetsCount{Count: 4},
+		},
+		{
+			name:     "no followings",
+			expected: &timelinedomain.NewTweetsCount{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := new(mockUserChecker)
+			repo := new(mockTimelineRepository)
+			cache := new(mockCacheRepository)
+			home := new(mockHomeTimelineRepository)
+			celebrities := new(mockCelebrityRepository)
+			checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+			checker.On("GetFollowings", mock.Anything, "testuser").Return(tt.followings, nil)
+			if tt.cacheSetup != nil {
+				tt.cacheSetup(cache)
+			}
+			if tt.homeSetup != nil {
+				tt.homeSetup(home)
+			}
+			if tt.celebSetup != nil {
+				tt.celebSetup(celebrities)
+			}
+			if tt.repoSetup != nil {
+				tt.repoSetup(repo)
+			}
+
+			service := NewTimelineService(repo, checker, newTestCacheLoader(cache), home, celebrities, nil, TimelineConfig{HomeTimelineLength: 800})
+			count, err := service.CountNewTweets(context.Background(), "testuser", timelinedomain.Since{Cursor: &since})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, count)
+			repo.AssertExpectations(t)
+			cache.AssertExpectations(t)
+			home.AssertExpectations(t)
+			celebrities.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimelineService_GetTimeline_SeedsHomeTimeline(t *testing.T) {
+	now := time.Now()
+	fanTweets := []timelinedomain.Tweet{{ID: "t1", Username: "fan", Content: "Tweet 1", CreatedAt: now}}
+	celebTweets := []timelinedomain.Tweet{{ID: "c1", Username: "celeb", Content: "Celeb", CreatedAt: now.Add(-time.Minute)}}
+
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+	checker.On("GetFollowings", mock.Anything, "testuser").Return([]string{"fan", "celeb"}, nil)
+
+	cache := new(mockCacheRepository)
+	cache.On("Lookup", mock.Anything, "timeline:testuser:offset=0:limit=10").Return("", false, nil)
+	cache.On("Set", mock.Anything, "timeline:testuser:offset=0:limit=10", mock.Anything, []string{"timeline:testuser", "celebrity:celeb"}).Return(nil)
+
+	celebrities := new(mockCelebrityRepository)
+	celebrities.On("FilterCelebrities", mock.Anything, []string{"fan", "celeb"}).Return([]string{"celeb"}, nil)
+
+	// El timeline sin inicializar se carga sólo con los autores que se
+	// distribuyen por fan-out, y la lectura sigue desde MongoDB
+	home := new(mockHomeTimelineRepository)
+	home.On("GetTweetIDs", mock.Anything, "testuser", 0, 10).Return([]string(nil), false, nil)
+	home.On("SeedTweets", mock.Anything, "testuser", fanTweets).Return(nil)
+
+	repo := new(mockTimelineRepository)
+	repo.On("GetTweetsForUsers", mock.Anything, []string{"fan"}, 0, 100).Return(fanTweets, nil)
+	repo.On("GetTweetsForUsers", mock.Anything, []string{"fan", "celeb"}, 0, 10).Return(append(fanTweets, celebTweets...), nil)
+
+	authors := new(mockAuthorRepository)
+	authors.On("GetAuthors", mock.Anything, []string{"fan", "celeb"}).Return(map[string]timelinedomain.Author{}, nil)
+
+	service := NewTimelineService(repo, checker, newTestCacheLoader(cache), home, celebrities, authors, TimelineConfig{HomeTimelineLength: 100})
+	timeline, err := service.GetTimeline(context.Background(), "testuser", timelinedomain.Page{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, timeline.Tweets, 2)
+	repo.AssertExpectations(t)
+	home.AssertExpectations(t)
+}
+
+func TestTimelineService_GetTimeline_ResolvesRetweets(t *testing.T) {
+	now := time.Now()
+
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+	checker.On("GetFollowings", mock.Anything, "testuser").Return([]string{"fan"}, nil)
+
+	cache := new(mockCacheRepository)
+	cache.On("Lookup", mock.Anything, "timeline:testuser:offset=0:limit=10").Return("", false, nil)
+	cache.On("Set", mock.Anything, "timeline:testuser:offset=0:limit=10", mock.Anything, []string{"timeline:testuser"}).Return(nil)
+
+	home := new(mockHomeTimelineRepository)
+	home.On("GetTweetIDs", mock.Anything, "testuser", 0, 10).Return([]string{"rt1", "rt2", "t1"}, true, nil)
+
+	celebrities := new(mockCelebrityRepository)
+	celebrities.On("FilterCelebrities", mock.Anything, []string{"fan"}).Return([]string{}, nil)
+
+	repo := new(mockTimelineRepository)
+	repo.On("GetTweetsByIDs", mock.Anything, []string{"rt1", "rt2", "t1"}).Return([]timelinedomain.Tweet{
+		{ID: "rt1", Username: "fan", CreatedAt: now, RetweetOf: "o1"},
+		{ID: "rt2", Username: "fan", CreatedAt: now.Add(-time.Minute), RetweetOf: "deleted"},
+		{ID: "t1", Username: "fan", Content: "Tweet 1", CreatedAt: now.Add(-2 * time.Minute)},
+	}, nil)
+	// El original eliminado no se devuelve y su retweet se descarta
+	repo.On("GetTweetsByIDs", mock.Anything, []string{"o1", "deleted"}).Return([]timelinedomain.Tweet{
+		{ID: "o1", Username: "author", Content: "Original", CreatedAt: now.Add(-time.Hour)},
+	}, nil)
+
+	authors := new(mockAuthorRepository)
+	authors.On("GetAuthors", mock.Anything, []string{"fan", "author"}).Return(map[string]timelinedomain.Author{
+		"author": {Username: "author", DisplayName: "The Author"},
+	}, nil)
+
+	service := NewTimelineService(repo, checker, newTestCacheLoader(cache), home, celebrities, authors, TimelineConfig{HomeTimelineLength: 100})
+	timeline, err := service.GetTimeline(context.Background(), "testuser", timelinedomain.Page{Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, timeline.Tweets, 2)
+	assert.Equal(t, "rt1", timeline.Tweets[0].ID)
+	assert.Equal(t, "o1", timeline.Tweets[0].Original.ID)
+	assert.Equal(t, "Original", timeline.Tweets[0].Original.Content)
+	assert.Equal(t, "The Author", timeline.Tweets[0].Original.DisplayName)
+	assert.Equal(t, "t1", timeline.Tweets[1].ID)
+	repo.AssertExpectations(t)
+	authors.AssertExpectations(t)
+}
+
+func TestTimelineService_GetTimeline_OrphanRetweetKeepsNextCursor(t *testing.T) {
+	now := time.Now()
+
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+	checker.On("GetFollowings", mock.Anything, "testuser").Return([]string{"fan"}, nil)
+
+	cache := new(mockCacheRepository)
+	cache.On("Lookup", mock.Anything, "timeline:testuser:offset=0:limit=3").Return("", false, nil)
+	cache.On("Set", mock.Anything, "timeline:testuser:offset=0:limit=3", mock.Anything, []string{"timeline:testuser"}).Return(nil)
+
+	home := new(mockHomeTimelineRepository)
+	home.On("GetTweetIDs", mock.Anything, "testuser", 0, 3).Return([]string{"t1", "rt1", "t2"}, true, nil)
+
+	celebrities := new(mockCelebrityRepository)
+	celebrities.On("FilterCelebrities", mock.Anything, []string{"fan"}).Return([]string{}, nil)
+
+	last := timelinedomain.Tweet{ID: "t2", Username: "fan", Content: "Tweet 2", CreatedAt: now.Add(-2 * time.Minute)}
+	repo := new(mockTimelineRepository)
+	repo.On("GetTweetsByIDs", mock.Anything, []string{"t1", "rt1", "t2"}).Return([]timelinedomain.Tweet{
+		{ID: "t1", Username: "fan", Content: "Tweet 1", CreatedAt: now},
+		{ID: "rt1", Username: "fan", CreatedAt: now.Add(-time.Minute), RetweetOf: "deleted"},
+		last,
+	}, nil)
+	repo.On("GetTweetsByIDs", mock.Anything, []string{"deleted"}).Return([]timelinedomain.Tweet{}, nil)
+
+	authors := new(mockAuthorRepository)
+	authors.On("GetAuthors", mock.Anything, []string{"fan"}).Return(map[string]timelinedomain.Author{}, nil)
+
+	service := NewTimelineService(repo, checker, newTestCacheLoader(cache), home, celebrities, authors, TimelineConfig{HomeTimelineLength: 100})
+	timeline, err := service.GetTimeline(context.Background(), "testuser", timelinedomain.Page{Limit: 3})
+
+	// La página leída estaba completa, por lo que hay página siguiente aunque
+	// se muestren menos tweets
+	assert.NoError(t, err)
+	assert.Len(t, timeline.Tweets, 2)
+	assert.Equal(t, "t1", timeline.Tweets[0].ID)
+	assert.Equal(t, "t2", timeline.Tweets[1].ID)
+	assert.Equal(t, timelinedomain.CursorFor(last).Encode(), timeline.NextCursor)
+}
+
+// fakeCacheLoader ejecuta siempre la carga y registra la clave y los tags
+// con los que se guardaría cada valor
+type fakeCacheLoader struct {
+	tags map[string][]string
+}
+
+func (l *fakeCacheLoader) Load(ctx context.Context, key string, load ports.LoadFunc) (string, error) {
+	value, tags, err := load(ctx)
+	if err != nil {
+		return "", err
+	}
+	if l.tags == nil {
+		l.tags = make(map[string][]string)
+	}
+	l.tags[key] = tags
+	return value, nil
+}
+
+func TestTimelineService_CountNewTweets_LoadsThroughCacheLoader(t *testing.T) {
+	since := timelinedomain.Cursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: "t1"}
+
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+	checker.On("GetFollowings", mock.Anything, "testuser").Return([]string{"user1", "celeb"}, nil)
+
+	home := new(mockHomeTimelineRepository)
+	home.On("CountTweetIDsAfter", mock.Anything, "testuser", since).Return(3, true, nil)
+
+	celebrities := new(mockCelebrityRepository)
+	celebrities.On("FilterCelebrities", mock.Anything, []string{"user1", "celeb"}).Return([]string{"celeb"}, nil)
+
+	repo := new(mockTimelineRepository)
+	repo.On("CountTweetsForUsersAfter", mock.Anything, []string{"celeb"}, since, newTweetsCountLimit+1-3).Return(1, nil)
+
+	loader := &fakeCacheLoader{}
+	service := NewTimelineService(repo, checker, loader, home, celebrities, nil, TimelineConfig{HomeTimelineLength: 100})
+	count, err := service.CountNewTweets(context.Background(), "testuser", timelinedomain.Since{Cursor: &since})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &timelinedomain.NewTweetsCount{Count: 4}, count)
+	assert.Equal(t, map[string][]string{
+		"timeline:testuser:newcount:since=" + since.Encode(): {"timeline:testuser", "celebrity:celeb"},
+	}, loader.tags)
+	repo.AssertExpectations(t)
+}
+
+func TestTimelineService_GetTweetsSince_BypassesCache(t *testing.T) {
+	now := time.Now()
+	since := timelinedomain.Cursor{CreatedAt: now.Add(-time.Hour), ID: "t0"}
+
+	checker := new(mockUserChecker)
+	checker.On("GetFollowings", mock.Anything, "testuser").Return([]string{"fan"}, nil)
+
+	// Sin expectativas: cualquier lectura o escritura del caché falla el test
+	cache := new(mockCacheRepository)
+
+	home := new(mockHomeTimelineRepository)
+	home.On("GetTweetIDsAfter", mock.Anything, "testuser", since, 10).Return([]string{"t2", "t1"}, true, nil)
+
+	celebrities := new(mockCelebrityRepository)
+	celebrities.On("FilterCelebrities", mock.Anything, []string{"fan"}).Return([]string{}, nil)
+
+	repo := new(mockTimelineRepository)
+	repo.On("GetTweetsByIDs", mock.Anything, []string{"t2", "t1"}).Return([]timelinedomain.Tweet{
+		{ID: "t2", Username: "fan", Content: "Tweet 2", CreatedAt: now},
+		{ID: "t1", Username: "fan", Content: "Tweet 1", CreatedAt: now.Add(-time.Minute)},
+	}, nil)
+
+	authors := new(mockAuthorRepository)
+	authors.On("GetAuthors", mock.Anything, []string{"fan"}).Return(map[string]timelinedomain.Author{}, nil)
+
+	service := NewTimelineService(repo, checker, newTestCacheLoader(cache), home, celebrities, authors, TimelineConfig{HomeTimelineLength: 100})
+	tweets, err := service.GetTweetsSince(context.Background(), "testuser", since, 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, tweets, 2)
+	assert.Equal(t, "t2", tweets[0].ID)
+	cache.AssertExpectations(t)
+}
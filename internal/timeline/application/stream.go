@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"slices"
+
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+)
+
+// StreamConfig agrupa los parámetros de los streams de timeline
+type StreamConfig struct {
+	// BufferSize es la cantidad de tweets pendientes de enviar que admite un
+	// stream; si el cliente no los consume a tiempo el stream se cierra
+	BufferSize int
+
+	// ResumeLimit es la cantidad máxima de tweets que se reenvían al
+	// reanudar un stream con Last-Event-ID
+	ResumeLimit int
+}
+
+// StreamPublisher avisa los tweets nuevos a los streams de timeline abiertos.
+// Sigue el mismo criterio que el fan-out: los tweets de celebridades se
+// publican una sola vez para quienes las siguen y los demás se publican en el
+// stream de cada seguidor.
+type StreamPublisher struct {
+	userChecker common.UserChecker
+	celebrities ports.CelebrityRepository
+	broker      ports.StreamBroker
+}
+
+// NewStreamPublisher crea una nueva instancia de StreamPublisher
+func NewStreamPublisher(
+	userChecker common.UserChecker,
+	celebrities ports.CelebrityRepository,
+	broker ports.StreamBroker,
+) *StreamPublisher {
+	return &StreamPublisher{
+		userChecker: userChecker,
+		celebrities: celebrities,
+		broker:      broker,
+	}
+}
+
+// HandleTweetCreated procesa un evento TweetCreated. Se registra después del
+// fan-out para que la clasificación del autor como celebridad esté al día.
+// Una reentrega vuelve a publicar el aviso, que los streams descartan.
+func (p *StreamPublisher) HandleTweetCreated(ctx context.Context, evt events.Event) error {
+	var payload events.TweetCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	entry := domain.StreamEntry{TweetID: payload.TweetID, CreatedAt: payload.CreatedAt}
+
+	celebrities, err := p.celebrities.FilterCelebrities(ctx, []string{payload.Username})
+	if err != nil {
+		return err
+	}
+	if len(celebrities) > 0 {
+		return p.broker.PublishFromAuthor(ctx, payload.Username, entry)
+	}
+
+	followers, err := p.userChecker.GetFollowers(ctx, payload.Username)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(followers); start += fanoutBatchSize {
+		end := min(start+fanoutBatchSize, len(followers))
+		if err := p.broker.PublishToTimelines(ctx, followers[start:end], entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type timelineStreamService struct {
+	timelines   ports.TimelineService
+	repo        ports.TimelineRepository
+	userChecker common.UserChecker
+	celebrities ports.CelebrityRepository
+	authors     ports.AuthorRepository
+	broker      ports.StreamBroker
+	cfg         StreamConfig
+}
+
+func NewTimelineStreamService(
+	timelines ports.TimelineService,
+	repo ports.TimelineRepository,
+	userChecker common.UserChecker,
+	celebrities ports.CelebrityRepository,
+	authors ports.AuthorRepository,
+	broker ports.StreamBroker,
+	cfg StreamConfig,
+) ports.TimelineStreamService {
+	return &timelineStreamService{
+		timelines:   timelines,
+		repo:        repo,
+		userChecker: userChecker,
+		celebrities: celebrities,
+		authors:     authors,
+		broker:      broker,
+		cfg:         cfg,
+	}
+}
+
+// OpenStream se suscribe antes de leer los tweets a reenviar para no perder
+// los que lleguen mientras tanto; los repetidos se descartan. Las
+// celebridades seguidas se fijan al abrir el stream, por lo que un follow a
+// una celebridad se refleja al reconectar.
+func (s *timelineStreamService) OpenStream(ctx context.Context, username string, lastEventID *domain.Cursor) (ports.TimelineStream, error) {
+	if _, err := s.userChecker.GetUser(username); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	followings, err := s.userChecker.GetFollowings(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	celebrities, err := s.celebrities.FilterCelebrities(ctx, followings)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.broker.Subscribe(ctx, username, celebrities, s.cfg.BufferSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var backlog []domain.Tweet
+	if lastEventID != nil {
+		backlog, err = s.loadBacklog(ctx, username, *lastEventID)
+		if err != nil {
+			sub.Close()
+			return nil, err
+		}
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream := &timelineStream{
+		tweets: make(chan domain.Tweet),
+		sub:    sub,
+		cancel: cancel,
+	}
+	go stream.run(streamCtx, s, backlog)
+	return stream, nil
+}
+
+// loadBacklog obtiene los tweets más recientes que el cursor, del más antiguo
+// al más reciente, hasta ResumeLimit. Se leen sin caché para no perder los
+// publicados después de cachear la primera página del timeline.
+func (s *timelineStreamService) loadBacklog(ctx context.Context, username string, cursor domain.Cursor) ([]domain.Tweet, error) {
+	backlog, err := s.timelines.GetTweetsSince(ctx, username, cursor, s.cfg.ResumeLimit)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(backlog)
+	return backlog, nil
+}
+
+// loadTweet obtiene un tweet avisado con su original y los perfiles de los
+// autores, igual que en el timeline. Devuelve false si el tweet ya no existe.
+func (s *timelineStreamService) loadTweet(ctx context.Context, id string) (domain.Tweet, bool, error) {
+	tweets, err := s.repo.GetTweetsByIDs(ctx, []string{id})
+	if err != nil {
+		return domain.Tweet{}, false, err
+	}
+	tweets, err = resolveRetweets(ctx, s.repo, tweets)
+	if err != nil || len(tweets) == 0 || tweets[0].IsOrphanRetweet() {
+		return domain.Tweet{}, false, err
+	}
+
+	decorateAuthors(ctx, s.authors, tweets)
+	return tweets[0], true, nil
+}
+
+type timelineStream struct {
+	tweets chan domain.Tweet
+	sub    ports.StreamSubscription
+	cancel context.CancelFunc
+	// err se escribe antes de cerrar tweets
+	err error
+}
+
+func (st *timelineStream) Tweets() <-chan domain.Tweet {
+	return st.tweets
+}
+
+func (st *timelineStream) Err() error {
+	return st.err
+}
+
+func (st *timelineStream) Close() {
+	st.cancel()
+	st.sub.Close()
+}
+
+// run envía primero los tweets a reenviar y después los avisados, descartando
+// los repetidos. Mientras el cliente no consume, los avisos se acumulan en el
+// buffer de la suscripción.
+func (st *timelineStream) run(ctx context.Context, s *timelineStreamService, backlog []domain.Tweet) {
+	defer close(st.tweets)
+
+	seen := make(map[string]bool)
+	for _, tweet := range backlog {
+		seen[tweet.ID] = true
+		if !st.send(ctx, tweet) {
+			return
+		}
+	}
+
+	for entry := range st.sub.Entries() {
+		if seen[entry.TweetID] {
+			continue
+		}
+		seen[entry.TweetID] = true
+
+		tweet, found, err := s.loadTweet(ctx, entry.TweetID)
+		if err != nil {
+			if ctx.Err() == nil {
+				st.err = err
+			}
+			st.sub.Close()
+			return
+		}
+		if found && !st.send(ctx, tweet) {
+			return
+		}
+	}
+	st.err = st.sub.Err()
+}
+
+func (st *timelineStream) send(ctx context.Context, tweet domain.Tweet) bool {
+	select {
+	case st.tweets <- tweet:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
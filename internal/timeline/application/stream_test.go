@@ -0,0 +1,272 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	timelinedomain "github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+	usersdomain "github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStreamBroker struct {
+	mock.Mock
+}
+
+func (m *mockStreamBroker) PublishToTimelines(ctx context.Context, usernames []string, entry timelinedomain.StreamEntry) error {
+	args := m.Called(ctx, usernames, entry)
+	return args.Error(0)
+}
+
+func (m *mockStreamBroker) PublishFromAuthor(ctx context.Context, author string, entry timelinedomain.StreamEntry) error {
+	args := m.Called(ctx, author, entry)
+	return args.Error(0)
+}
+
+func (m *mockStreamBroker) Subscribe(ctx context.Context, username string, celebrities []string, buffer int) (ports.StreamSubscription, error) {
+	args := m.Called(ctx, username, celebrities, buffer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(ports.StreamSubscription), args.Error(1)
+}
+
+// fakeSubscription es una suscripción cuyos avisos se cargan desde el test
+type fakeSubscription struct {
+	entries chan timelinedomain.StreamEntry
+	err     error
+	closed  bool
+}
+
+func newFakeSubscription(entries ...timelinedomain.StreamEntry) *fakeSubscription {
+	sub := &fakeSubscription{entries: make(chan timelinedomain.StreamEntry, len(entries)+1)}
+	for _, entry := range entries {
+		sub.entries <- entry
+	}
+	return sub
+}
+
+func (s *fakeSubscription) Entries() <-chan timelinedomain.StreamEntry { return s.entries }
+func (s *fakeSubscription) Err() error                                 { return s.err }
+func (s *fakeSubscription) Close() {
+	if !s.closed {
+		s.closed = true
+		close(s.entries)
+	}
+}
+
+type mockTimelineService struct {
+	mock.Mock
+}
+
+func (m *mockTimelineService) GetTimeline(ctx context.Context, username string, page timelinedomain.Page) (*timelinedomain.Timeline, error) {
+	args := m.Called(ctx, username, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*timelinedomain.Timeline), args.Error(1)
+}
+
+func (m *mockTimelineService) GetTweetsSince(ctx context.Context, username string, since timelinedomain.Cursor, limit int) ([]timelinedomain.Tweet, error) {
+	args := m.Called(ctx, username, since, limit)
+	return args.Get(0).([]timelinedomain.Tweet), args.Error(1)
+}
+
+func (m *mockTimelineService) CountNewTweets(ctx context.Context, username string, since timelinedomain.Since) (*timelinedomain.NewTweetsCount, error) {
+	args := m.Called(ctx, username, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*timelinedomain.NewTweetsCount), args.Error(1)
+}
+
+func TestStreamPublisher_HandleTweetCreated(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := timelinedomain.StreamEntry{TweetID: "3", CreatedAt: now}
+
+	tests := []struct {
+		name         string
+		username     string
+		checkerSetup func(*mockUserChecker)
+		celebSetup   func(*mockCelebrityRepository)
+		brokerSetup  func(*mockStreamBroker)
+	}{
+		{
+			name:     "publishes to every follower timeline",
+			username: "author",
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"author"}).Return([]string{}, nil)
+			},
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetFollowers", mock.Anything, "author").Return([]string{"user1", "user2"}, nil)
+			},
+			brokerSetup: func(m *mockStreamBroker) {
+				m.On("PublishToTimelines", mock.Anything, []string{"user1", "user2"}, entry).Return(nil)
+			},
+		},
+		{
+			name:     "celebrity tweets are published once",
+			username: "celeb",
+			celebSetup: func(m *mockCelebrityRepository) {
+				m.On("FilterCelebrities", mock.Anything, []string{"celeb"}).Return([]string{"celeb"}, nil)
+			},
+			brokerSetup: func(m *mockStreamBroker) {
+				m.On("PublishFromAuthor", mock.Anything, "celeb", entry).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := new(mockUserChecker)
+			celebs := new(mockCelebrityRepository)
+			broker := new(mockStreamBroker)
+			if tt.checkerSetup != nil {
+				tt.checkerSetup(checker)
+			}
+			tt.celebSetup(celebs)
+			tt.brokerSetup(broker)
+
+			evt, err := events.New(events.TopicTweets, events.TweetCreated, 1, tt.username,
+				events.TweetCreatedPayload{TweetID: "3", Username: tt.username, CreatedAt: now})
+			assert.NoError(t, err)
+
+			publisher := NewStreamPublisher(checker, celebs, broker)
+			assert.NoError(t, publisher.HandleTweetCreated(context.Background(), evt))
+
+			checker.AssertExpectations(t)
+			celebs.AssertExpectations(t)
+			broker.AssertExpectations(t)
+		})
+	}
+}
+
+// collectTweets lee los tweets del stream hasta que se cierra
+func collectTweets(t *testing.T, stream ports.TimelineStream) []string {
+	var ids []string
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case tweet, ok := <-stream.Tweets():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, tweet.ID)
+		case <-timeout:
+			t.Fatal("stream did not finish")
+			return nil
+		}
+	}
+}
+
+func TestTimelineStreamService_OpenStream(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tweet := func(id string, at time.Time) timelinedomain.Tweet {
+		return timelinedomain.Tweet{ID: id, Username: "followee", Content: "Tweet " + id, CreatedAt: at}
+	}
+	cfg := StreamConfig{BufferSize: 8, ResumeLimit: 50}
+
+	tests := []struct {
+		name          string
+		lastEventID   *timelinedomain.Cursor
+		subscription  *fakeSubscription
+		timelineSetup func(*mockTimelineService)
+		repoSetup     func(*mockTimelineRepository)
+		expectedIDs   []string
+		expectedError error
+	}{
+		{
+			name:         "streams announced tweets",
+			subscription: newFakeSubscription(timelinedomain.StreamEntry{TweetID: "3", CreatedAt: now}),
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsByIDs", mock.Anything, []string{"3"}).Return([]timelinedomain.Tweet{tweet("3", now)}, nil)
+			},
+			expectedIDs: []string{"3"},
+		},
+		{
+			name:        "resumes after last event id and skips repeated tweets",
+			lastEventID: &timelinedomain.Cursor{CreatedAt: now.Add(-2 * time.Minute), ID: "1"},
+			subscription: newFakeSubscription(
+				timelinedomain.StreamEntry{TweetID: "3", CreatedAt: now},
+				timelinedomain.StreamEntry{TweetID: "4", CreatedAt: now.Add(time.Minute)},
+			),
+			timelineSetup: func(m *mockTimelineService) {
+				since := timelinedomain.Cursor{CreatedAt: now.Add(-2 * time.Minute), ID: "1"}
+				m.On("GetTweetsSince", mock.Anything, "alice", since, 50).Return([]timelinedomain.Tweet{
+					tweet("3", now),
+					tweet("2", now.Add(-time.Minute)),
+				}, nil)
+			},
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsByIDs", mock.Anything, []string{"4"}).Return([]timelinedomain.Tweet{tweet("4", now.Add(time.Minute))}, nil)
+			},
+			expectedIDs: []string{"2", "3", "4"},
+		},
+		{
+			name:         "skips deleted tweets",
+			subscription: newFakeSubscription(timelinedomain.StreamEntry{TweetID: "3", CreatedAt: now}),
+			repoSetup: func(m *mockTimelineRepository) {
+				m.On("GetTweetsByIDs", mock.Anything, []string{"3"}).Return([]timelinedomain.Tweet{}, nil)
+			},
+		},
+		{
+			name:          "reports overflow",
+			subscription:  &fakeSubscription{entries: make(chan timelinedomain.StreamEntry), err: timelinedomain.ErrStreamOverflow},
+			expectedError: timelinedomain.ErrStreamOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timelines := new(mockTimelineService)
+			repo := new(mockTimelineRepository)
+			checker := new(mockUserChecker)
+			celebs := new(mockCelebrityRepository)
+			authors := new(mockAuthorRepository)
+			broker := new(mockStreamBroker)
+
+			checker.On("GetUser", "alice").Return(&usersdomain.User{Username: "alice"}, nil)
+			checker.On("GetFollowings", mock.Anything, "alice").Return([]string{"followee", "celeb"}, nil)
+			celebs.On("FilterCelebrities", mock.Anything, []string{"followee", "celeb"}).Return([]string{"celeb"}, nil)
+			broker.On("Subscribe", mock.Anything, "alice", []string{"celeb"}, cfg.BufferSize).Return(tt.subscription, nil)
+			authors.On("GetAuthors", mock.Anything, mock.Anything).Return(map[string]timelinedomain.Author{}, nil)
+			if tt.timelineSetup != nil {
+				tt.timelineSetup(timelines)
+			}
+			if tt.repoSetup != nil {
+				tt.repoSetup(repo)
+			}
+
+			service := NewTimelineStreamService(timelines, repo, checker, celebs, authors, broker, cfg)
+			stream, err := service.OpenStream(context.Background(), "alice", tt.lastEventID)
+			assert.NoError(t, err)
+
+			// La suscripción termina cuando ya entregó los avisos cargados
+			tt.subscription.Close()
+
+			assert.Equal(t, tt.expectedIDs, collectTweets(t, stream))
+			assert.Equal(t, tt.expectedError, stream.Err())
+			stream.Close()
+
+			timelines.AssertExpectations(t)
+			repo.AssertExpectations(t)
+			broker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimelineStreamService_OpenStream_UserNotFound(t *testing.T) {
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "ghost").Return(nil, errors.New("not found"))
+
+	service := NewTimelineStreamService(nil, nil, checker, nil, nil, nil, StreamConfig{})
+	stream, err := service.OpenStream(context.Background(), "ghost", nil)
+
+	assert.Equal(t, ErrUserNotFound, err)
+	assert.Nil(t, stream)
+}
@@ -0,0 +1,12 @@
+package domain
+
+// Author es la proyección del perfil de un usuario que se muestra junto a
+// sus tweets en los timelines
+type Author struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	AvatarURL   string `json:"avatarUrl"`
+
+	// Version es la versión del perfil del usuario de la que sale la proyección
+	Version int64 `json:"version,omitempty"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+)
+
+// Cursor identifica una posición en un timeline ordenado por fecha de
+// creación descendente y, ante fechas iguales, por id descendente
+type Cursor = pagination.Cursor
+
+// ErrInvalidCursor se devuelve cuando un cursor no puede decodificarse
+var ErrInvalidCursor = pagination.ErrInvalidCursor
+
+// ErrInvalidSince se devuelve cuando since no es un cursor ni un id de tweet
+var ErrInvalidSince = errors.New("since must be a cursor or a tweet id")
+
+// CursorFor devuelve el cursor que apunta a un tweet
+func CursorFor(tweet Tweet) Cursor {
+	return Cursor{CreatedAt: tweet.CreatedAt, ID: tweet.ID}
+}
+
+// DecodeCursor decodifica un cursor generado por Cursor.Encode
+func DecodeCursor(encoded string) (Cursor, error) {
+	return pagination.DecodeCursor(encoded)
+}
+
+// Since identifica el tweet más reciente que ya vio un cliente, por su cursor
+// o por su id. El id requiere leer el tweet, por lo que se prefiere el cursor.
+type Since struct {
+	Cursor  *Cursor
+	TweetID string
+}
+
+// ParseSince interpreta el valor recibido como parámetro de una request: si
+// no es un cursor válido se toma como id de tweet, que debe ser un UUID
+func ParseSince(value string) (Since, error) {
+	if cursor, err := DecodeCursor(value); err == nil {
+		return Since{Cursor: &cursor}, nil
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		return Since{}, ErrInvalidSince
+	}
+	return Since{TweetID: value}, nil
+}
+
+// Page describe la página de un timeline a obtener. Si Cursor no es nil se
+// devuelven los Limit tweets siguientes al cursor y Offset se ignora. Si
+// Since no es nil se devuelven los Limit tweets más recientes que él y
+// Cursor y Offset se ignoran.
+type Page struct {
+	Offset int
+	Limit  int
+	Cursor *Cursor
+	Since  *Since
+}
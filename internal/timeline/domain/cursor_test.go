@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSince(t *testing.T) {
+	cursor := Cursor{CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: "t1"}
+
+	tests := []struct {
+		name          string
+		value         string
+		expected      Since
+		expectedError error
+	}{
+		{
+			name:     "cursor",
+			value:    cursor.Encode(),
+			expected: Since{Cursor: &cursor},
+		},
+		{
+			name:     "tweet id",
+			value:    "6f1c2a9e-3b1d-4c5e-9f0a-1b2c3d4e5f60",
+			expected: Since{TweetID: "6f1c2a9e-3b1d-4c5e-9f0a-1b2c3d4e5f60"},
+		},
+		{
+			name:          "malformed",
+			value:         "not-a-cursor",
+			expectedError: ErrInvalidSince,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since, err := ParseSince(tt.value)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, since)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+// Entities son los hashtags, menciones, cashtags y URLs reconocidos en el
+// contenido al crear el tweet. Start y End son posiciones en caracteres
+// (runas) del contenido, con End exclusivo.
+type Entities struct {
+	Hashtags []TagEntity     `bson:"hashtags,omitempty" json:"hashtags,omitempty"`
+	Mentions []MentionEntity `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	Cashtags []TagEntity     `bson:"cashtags,omitempty" json:"cashtags,omitempty"`
+	URLs     []URLEntity     `bson:"urls,omitempty" json:"urls,omitempty"`
+}
+
+// TagEntity es un hashtag o un cashtag; Text no incluye el prefijo # o $
+type TagEntity struct {
+	Text  string `bson:"text" json:"text"`
+	Start int    `bson:"start" json:"start"`
+	End   int    `bson:"end" json:"end"`
+}
+
+// MentionEntity es la mención a un usuario; Username no incluye el prefijo @
+type MentionEntity struct {
+	Username string `bson:"username" json:"username"`
+	Start    int    `bson:"start" json:"start"`
+	End      int    `bson:"end" json:"end"`
+}
+
+// URLEntity es una URL http o https
+type URLEntity struct {
+	URL   string `bson:"url" json:"url"`
+	Start int    `bson:"start" json:"start"`
+	End   int    `bson:"end" json:"end"`
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
 )
@@ -10,6 +11,134 @@ import (
 type TimelineRepository interface {
 	// GetTweetsForUsers obtiene los tweets de una lista de usuarios
 	GetTweetsForUsers(ctx context.Context, usernames []string, offset, limit int) ([]domain.Tweet, error)
+
+	// GetTweetsForUsersBefore obtiene los tweets de una lista de usuarios
+	// posteriores al cursor en el orden del timeline
+	GetTweetsForUsersBefore(ctx context.Context, usernames []string, cursor domain.Cursor, limit int) ([]domain.Tweet, error)
+
+	// GetTweetsForUsersAfter obtiene los limit tweets más recientes de una
+	// lista de usuarios que van antes del cursor en el orden del timeline
+	GetTweetsForUsersAfter(ctx context.Context, usernames []string, since domain.Cursor, limit int) ([]domain.Tweet, error)
+
+	// CountTweetsForUsersAfter cuenta, hasta un máximo de limit, los tweets de
+	// una lista de usuarios más recientes que el cursor
+	CountTweetsForUsersAfter(ctx context.Context, usernames []string, since domain.Cursor, limit int) (int, error)
+
+	// GetTweetsByIDs obtiene los tweets con los ids indicados, respetando su orden
+	GetTweetsByIDs(ctx context.Context, ids []string) ([]domain.Tweet, error)
+
+	// GetTweetCursor obtiene el cursor de un tweet aunque haya sido eliminado,
+	// ya que sigue marcando una posición del timeline. Devuelve nil si no existe.
+	GetTweetCursor(ctx context.Context, id string) (*domain.Cursor, error)
+
+	// GetTweetsByHashtag obtiene los tweets que contienen un hashtag
+	// normalizado, empezando después del cursor si no es nil
+	GetTweetsByHashtag(ctx context.Context, tag string, cursor *domain.Cursor, limit int) ([]domain.Tweet, error)
+}
+
+// HomeTimelineRepository define la interfaz para los timelines precalculados
+// (fan-out on write) de cada usuario
+type HomeTimelineRepository interface {
+	// AddTweet agrega un tweet al timeline de cada uno de los usuarios
+	// indicados que lo tenga inicializado
+	AddTweet(ctx context.Context, usernames []string, tweetID string, createdAt time.Time) error
+
+	// GetTweetIDs obtiene los ids del timeline de un usuario, del más reciente al
+	// más antiguo. found es false si el usuario no tiene timeline precalculado.
+	GetTweetIDs(ctx context.Context, username string, offset, limit int) (ids []string, found bool, err error)
+
+	// GetTweetIDsBefore obtiene los ids del timeline de un usuario posteriores al cursor
+	GetTweetIDsBefore(ctx context.Context, username string, cursor domain.Cursor, limit int) (ids []string, found bool, err error)
+
+	// GetTweetIDsAfter obtiene los limit ids más recientes del timeline de un
+	// usuario que van antes del cursor
+	GetTweetIDsAfter(ctx context.Context, username string, since domain.Cursor, limit int) (ids []string, found bool, err error)
+
+	// CountTweetIDsAfter cuenta los tweets del timeline de un usuario más
+	// recientes que el cursor
+	CountTweetIDsAfter(ctx context.Context, username string, since domain.Cursor) (count int, found bool, err error)
+
+	// AddTweets agrega tweets al timeline de un usuario sólo si está inicializado
+	AddTweets(ctx context.Context, username string, tweets []domain.Tweet) error
+
+	// SeedTweets inicializa el timeline de un usuario con sus tweets más
+	// recientes. Desde entonces se considera completo y recibe los tweets
+	// nuevos; hasta entonces las lecturas devuelven found en false.
+	SeedTweets(ctx context.Context, username string, tweets []domain.Tweet) error
+
+	// RemoveTweets quita tweets del timeline de un usuario
+	RemoveTweets(ctx context.Context, username string, tweetIDs []string) error
+
+	// RemoveTweet quita un tweet de los timelines de varios usuarios
+	RemoveTweet(ctx context.Context, usernames []string, tweetID string) error
+}
+
+// CelebrityRepository define la interfaz para registrar a los autores cuyos
+// tweets no se distribuyen por fan-out y se mezclan en el momento de la lectura
+type CelebrityRepository interface {
+	// SetCelebrity marca o desmarca a un usuario como celebridad
+	SetCelebrity(ctx context.Context, username string, celebrity bool) error
+
+	// FilterCelebrities devuelve los usuarios de la lista que son celebridades
+	FilterCelebrities(ctx context.Context, usernames []string) ([]string, error)
+}
+
+// AuthorRepository define la interfaz para la proyección de perfiles de los
+// autores, actualizada a partir de los eventos UserUpdated
+type AuthorRepository interface {
+	// SaveAuthor guarda o reemplaza el perfil de un autor, salvo que el
+	// guardado tenga una versión mayor
+	SaveAuthor(ctx context.Context, author domain.Author) error
+
+	// GetAuthors obtiene los perfiles conocidos de los usuarios indicados,
+	// indexados por username. Los usuarios sin perfil no se incluyen.
+	GetAuthors(ctx context.Context, usernames []string) (map[string]domain.Author, error)
+}
+
+// TrendRepository define la interfaz para los contadores de hashtags que
+// alimentan las tendencias
+type TrendRepository interface {
+	// RecordHashtags suma una aparición de cada hashtag en el intervalo de at
+	// de cada ventana. Cada tweet se cuenta una sola vez aunque se reprocese.
+	RecordHashtags(ctx context.Context, tweetID string, tags []string, at time.Time) error
+
+	// RemoveHashtags descuenta los hashtags de un tweet eliminado del intervalo
+	// de at de cada ventana. Solo descuenta los tweets que se habían contado,
+	// una única vez aunque se reprocese.
+	RemoveHashtags(ctx context.Context, tweetID string, tags []string, at time.Time) error
+
+	// TopHashtags devuelve los limit hashtags con mayor puntaje en la ventana
+	// que termina en now, de mayor a menor
+	TopHashtags(ctx context.Context, window domain.TrendWindow, now time.Time, limit int) ([]domain.Trend, error)
+}
+
+// StreamBroker define la interfaz para distribuir en tiempo real los tweets
+// nuevos a los streams de timeline abiertos
+type StreamBroker interface {
+	// PublishToTimelines avisa un tweet nuevo a los streams de los usuarios indicados
+	PublishToTimelines(ctx context.Context, usernames []string, entry domain.StreamEntry) error
+
+	// PublishFromAuthor avisa un tweet de una celebridad a los streams de
+	// quienes la siguen, que se suscriben a ella al abrirse
+	PublishFromAuthor(ctx context.Context, author string, entry domain.StreamEntry) error
+
+	// Subscribe abre una suscripción a los avisos del timeline de un usuario y
+	// de las celebridades indicadas. La suscripción guarda hasta buffer avisos
+	// sin consumir; si se llena se cierra con domain.ErrStreamOverflow.
+	Subscribe(ctx context.Context, username string, celebrities []string, buffer int) (StreamSubscription, error)
+}
+
+// StreamSubscription es una suscripción abierta a los avisos de un timeline
+type StreamSubscription interface {
+	// Entries devuelve los avisos recibidos. El canal se cierra al llamar a
+	// Close o cuando se llena el buffer.
+	Entries() <-chan domain.StreamEntry
+
+	// Err devuelve el motivo por el que se cerró Entries, o nil si fue por Close
+	Err() error
+
+	// Close cancela la suscripción
+	Close()
 }
 
 // UserRepository define la interfaz para el repositorio de usuarios
@@ -20,9 +149,45 @@ type UserRepository interface {
 
 // CacheRepository define la interfaz para el caché
 type CacheRepository interface {
-	// Get obtiene un valor del caché
+	// Get obtiene un valor vigente del caché
 	Get(ctx context.Context, key string) (string, error)
 
-	// Set guarda un valor en el caché
-	Set(ctx context.Context, key string, value string) error
+	// Lookup obtiene un valor del caché aunque esté vencido, para servirlo
+	// mientras se regenera. fresh indica si sigue vigente y value es vacío si
+	// la clave no existe.
+	Lookup(ctx context.Context, key string) (value string, fresh bool, err error)
+
+	// Set guarda un valor en el caché, asociándolo a los tags indicados
+	Set(ctx context.Context, key string, value string, tags ...string) error
+
+	// Delete elimina claves del caché
+	Delete(ctx context.Context, keys ...string) error
+
+	// InvalidateTag elimina todas las claves asociadas a un tag
+	InvalidateTag(ctx context.Context, tag string) error
+
+	// TagKeys obtiene las claves asociadas a un tag
+	TagKeys(ctx context.Context, tag string) ([]string, error)
+}
+
+// CacheInvalidationBus define la interfaz para avisar a las demás réplicas
+// qué claves del caché cambiaron, para que las descarten de su caché local
+type CacheInvalidationBus interface {
+	// PublishInvalidation avisa a las demás réplicas que las claves cambiaron
+	PublishInvalidation(ctx context.Context, keys []string) error
+
+	// SubscribeInvalidations llama a handler con las claves de cada aviso de
+	// otra réplica hasta que se cancele ctx
+	SubscribeInvalidations(ctx context.Context, handler func(keys []string)) error
+}
+
+// CacheLeaseRepository define la interfaz para los leases que coordinan entre
+// réplicas la regeneración de una clave del caché
+type CacheLeaseRepository interface {
+	// AcquireLease intenta tomar el lease de una clave por ttl. acquired es
+	// false si otra réplica ya lo tiene.
+	AcquireLease(ctx context.Context, key string, ttl time.Duration) (token string, acquired bool, err error)
+
+	// ReleaseLease libera el lease de una clave si todavía pertenece al token
+	ReleaseLease(ctx context.Context, key, token string) error
 }
@@ -8,8 +8,57 @@ import (
 
 // TimelineService define la interfaz para el servicio de timeline
 type TimelineService interface {
-	// GetTimeline obtiene el timeline de un usuario
-	GetTimeline(ctx context.Context, username string, offset, limit int) (*domain.Timeline, error)
+	// GetTimeline obtiene una página del timeline de un usuario
+	GetTimeline(ctx context.Context, username string, page domain.Page) (*domain.Timeline, error)
+
+	// CountNewTweets cuenta los tweets del timeline de un usuario más
+	// recientes que since, sin leer los tweets
+	CountNewTweets(ctx context.Context, username string, since domain.Since) (*domain.NewTweetsCount, error)
+
+	// GetTweetsSince obtiene los limit tweets más recientes que since del
+	// timeline de un usuario, leyéndolos sin pasar por el caché
+	GetTweetsSince(ctx context.Context, username string, since domain.Cursor, limit int) ([]domain.Tweet, error)
+}
+
+// HashtagService define la interfaz para la búsqueda por hashtag y las tendencias
+type HashtagService interface {
+	// GetHashtagTweets obtiene una página de los tweets que contienen un hashtag
+	GetHashtagTweets(ctx context.Context, tag string, cursor *domain.Cursor, limit int) (*domain.HashtagPage, error)
+	// GetTrends obtiene los hashtags con mayor tendencia en la ventana indicada
+	GetTrends(ctx context.Context, window string, limit int) ([]domain.Trend, error)
+}
+
+// TimelineStreamService define la interfaz para los streams en tiempo real
+// del timeline
+type TimelineStreamService interface {
+	// OpenStream abre el stream del timeline de un usuario. Si lastEventID no
+	// es nil, el stream empieza con los tweets más recientes que él.
+	OpenStream(ctx context.Context, username string, lastEventID *domain.Cursor) (TimelineStream, error)
+}
+
+// TimelineStream es un stream abierto con los tweets nuevos de un timeline
+type TimelineStream interface {
+	// Tweets devuelve los tweets del stream en el orden en que llegan. El
+	// canal se cierra cuando termina el stream.
+	Tweets() <-chan domain.Tweet
+
+	// Err devuelve el motivo por el que se cerró Tweets, o nil si fue por Close
+	Err() error
+
+	// Close termina el stream y cancela su suscripción
+	Close()
+}
+
+// LoadFunc obtiene el valor de una clave faltante del caché y los tags con
+// los que se guarda
+type LoadFunc func(ctx context.Context) (value string, tags []string, err error)
+
+// CacheLoader define la interfaz para leer claves del caché regenerándolas
+// cuando faltan
+type CacheLoader interface {
+	// Load devuelve el valor de key. Si no está en el caché lo obtiene con
+	// load y lo guarda asociado a los tags que load devuelve.
+	Load(ctx context.Context, key string, load LoadFunc) (string, error)
 }
 
 // TimelineUseCase define la interfaz para los casos de uso del timeline
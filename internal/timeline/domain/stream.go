@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrStreamOverflow se devuelve cuando un stream se cierra porque el cliente
+// no consume los tweets al ritmo en que llegan
+var ErrStreamOverflow = errors.New("stream consumer is too slow")
+
+// StreamEntry es el aviso de un tweet nuevo que se envía a los streams de
+// timeline abiertos. Sólo lleva la referencia al tweet, que cada stream lee
+// al momento de enviarlo.
+type StreamEntry struct {
+	TweetID   string    `json:"tweetId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
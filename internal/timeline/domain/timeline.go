@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"sort"
 	"time"
 )
 
@@ -11,12 +12,38 @@ type Tweet struct {
 	Username  string    `json:"username"`
 	Content   string    `json:"content"`
 	CreatedAt time.Time `json:"createdAt"`
+	LikeCount int64     `json:"likeCount"`
+	Entities  *Entities `json:"entities,omitempty"`
+
+	// Datos del perfil del autor, completados al momento de la lectura
+	DisplayName string `json:"displayName,omitempty"`
+	AvatarURL   string `json:"avatarUrl,omitempty"`
+
+	// RetweetOf es el id del tweet original de un retweet y Original el tweet
+	// completo, que es el que se muestra. El retweet conserva su propio id y
+	// fecha para ordenar y paginar el timeline.
+	RetweetOf     string `json:"retweetOf,omitempty"`
+	Original      *Tweet `json:"original,omitempty"`
+	QuotedTweetID string `json:"quotedTweetId,omitempty"`
+}
+
+// IsRetweet indica si el tweet es un retweet
+func (t Tweet) IsRetweet() bool {
+	return t.RetweetOf != ""
 }
 
 // Timeline es el agregado raíz que representa el timeline de un usuario
 type Timeline struct {
-	Username string  `json:"username"`
-	Tweets   []Tweet `json:"tweets"`
+	Username   string  `json:"username"`
+	Tweets     []Tweet `json:"tweets"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// NewTweetsCount es la cantidad de tweets del timeline más recientes que un
+// tweet ya visto. La cuenta se corta en un máximo; HasMore indica que hay más.
+type NewTweetsCount struct {
+	Count   int  `json:"count"`
+	HasMore bool `json:"hasMore"`
 }
 
 // NewTimeline crea un nuevo timeline para un usuario
@@ -32,10 +59,54 @@ func (t *Timeline) AddTweet(tweet Tweet) error {
 	if tweet.Username == "" {
 		return errors.New("tweet debe tener un usuario")
 	}
-	if len(tweet.Content) == 0 {
+	if tweet.IsRetweet() && tweet.Original == nil {
+		return errors.New("retweet debe tener el tweet original")
+	}
+	if len(tweet.Content) == 0 && !tweet.IsRetweet() {
 		return errors.New("tweet no puede estar vacío")
 	}
 
 	t.Tweets = append(t.Tweets, tweet)
 	return nil
 }
+
+// MergeTweets combina varias listas de tweets en orden cronológico inverso,
+// descartando duplicados. Ante fechas iguales ordena por id descendente para
+// que la paginación sea estable.
+func MergeTweets(lists ...[]Tweet) []Tweet {
+	seen := make(map[string]bool)
+	var merged []Tweet
+	for _, list := range lists {
+		for _, tweet := range list {
+			if seen[tweet.ID] {
+				continue
+			}
+			seen[tweet.ID] = true
+			merged = append(merged, tweet)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if !merged[i].CreatedAt.Equal(merged[j].CreatedAt) {
+			return merged[i].CreatedAt.After(merged[j].CreatedAt)
+		}
+		return merged[i].ID > merged[j].ID
+	})
+
+	return merged
+}
+
+// IsOrphanRetweet indica si el tweet es un retweet cuyo original fue eliminado
+func (t Tweet) IsOrphanRetweet() bool {
+	return t.IsRetweet() && t.Original == nil
+}
+
+// SetNextCursor establece el cursor de la página siguiente cuando la página
+// leída está completa; una página incompleta indica que no hay más tweets.
+// Recibe la página antes de descartar los retweets huérfanos, que no se
+// muestran pero ocupan su lugar en la paginación.
+func (t *Timeline) SetNextCursor(page []Tweet, limit int) {
+	if limit > 0 && len(page) == limit {
+		t.NextCursor = CursorFor(page[len(page)-1]).Encode()
+	}
+}
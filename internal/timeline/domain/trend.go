@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Trend es un hashtag con su puntaje de tendencia dentro de una ventana
+type Trend struct {
+	Hashtag string  `json:"hashtag"`
+	Score   float64 `json:"score"`
+}
+
+// TrendWindow describe una ventana deslizante de tendencias. Las apariciones
+// de cada hashtag se cuentan en Buckets intervalos de duración Bucket, y el
+// puntaje de cada intervalo decae a la mitad cada HalfLife.
+type TrendWindow struct {
+	Name     string
+	Bucket   time.Duration
+	Buckets  int
+	HalfLife time.Duration
+}
+
+// Duration devuelve la duración total de la ventana
+func (w TrendWindow) Duration() time.Duration {
+	return w.Bucket * time.Duration(w.Buckets)
+}
+
+// BucketStart devuelve el inicio del intervalo que contiene a t
+func (w TrendWindow) BucketStart(t time.Time) time.Time {
+	return t.Truncate(w.Bucket)
+}
+
+// Weight devuelve el peso del intervalo que está age intervalos antes del actual
+func (w TrendWindow) Weight(age int) float64 {
+	return math.Pow(0.5, float64(w.Bucket*time.Duration(age))/float64(w.HalfLife))
+}
+
+// NormalizeHashtag lleva un hashtag a la forma en que se indexa: sin el
+// prefijo # y en minúsculas
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// HashtagPage es una página de los tweets que contienen un hashtag, del más
+// reciente al más antiguo
+type HashtagPage struct {
+	Hashtag    string  `json:"hashtag"`
+	Tweets     []Tweet `json:"tweets"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
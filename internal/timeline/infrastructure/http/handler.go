@@ -7,10 +7,17 @@ import (
 	"errors"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/timeline/application"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
 	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
 )
 
+const (
+	defaultTimelineLimit = 10
+	maxTimelineLimit     = 100
+)
+
 type TimelineHandler struct {
 	service ports.TimelineService
 }
@@ -23,6 +30,8 @@ type getTimelineRequest struct {
 	Username string
 	Offset   int
 	Limit    int
+	Cursor   *domain.Cursor
+	Since    *domain.Since
 }
 
 type TweetView struct {
@@ -30,11 +39,49 @@ type TweetView struct {
 	Username  string `json:"username"`
 	Content   string `json:"content"`
 	CreatedAt string `json:"created_at"`
+	LikeCount int64  `json:"like_count"`
+
+	Entities *domain.Entities `json:"entities,omitempty"`
+
+	DisplayName string `json:"display_name,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+
+	// En los retweets el tweet mostrado es el original; RetweetedBy y
+	// RetweetedAt indican quién lo retweeteó y cuándo
+	RetweetedBy   string `json:"retweeted_by,omitempty"`
+	RetweetedAt   string `json:"retweeted_at,omitempty"`
+	QuotedTweetID string `json:"quoted_tweet_id,omitempty"`
+}
+
+const timeLayout = "2006-01-02T15:04:05Z"
+
+func newTweetView(tweet domain.Tweet) TweetView {
+	if tweet.Original != nil {
+		view := newTweetView(*tweet.Original)
+		view.RetweetedBy = tweet.Username
+		view.RetweetedAt = tweet.CreatedAt.Format(timeLayout)
+		return view
+	}
+
+	return TweetView{
+		TweetID:   tweet.ID,
+		Username:  tweet.Username,
+		Content:   tweet.Content,
+		CreatedAt: tweet.CreatedAt.Format(timeLayout),
+		LikeCount: tweet.LikeCount,
+		Entities:  tweet.Entities,
+
+		DisplayName: tweet.DisplayName,
+		AvatarURL:   tweet.AvatarURL,
+
+		QuotedTweetID: tweet.QuotedTweetID,
+	}
 }
 
 type TimelineResponse struct {
-	Username string      `json:"username"`
-	Tweets   []TweetView `json:"tweets"`
+	Username   string      `json:"username"`
+	Tweets     []TweetView `json:"tweets"`
+	NextCursor string      `json:"next_cursor,omitempty"`
 }
 
 func validateGetTimelineRequest(c *gin.Context) (*getTimelineRequest, error) {
@@ -43,27 +90,46 @@ func validateGetTimelineRequest(c *gin.Context) (*getTimelineRequest, error) {
 		return nil, errors.New("username is required")
 	}
 
-	offsetStr := c.Query("offset")
-	limitStr := c.Query("limit")
 	offset := 0
-	limit := 10
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		v, err := strconv.Atoi(offsetStr)
+		if err != nil || v < 0 {
+			return nil, errors.New("offset must be a non-negative integer")
+		}
+		offset = v
+	}
 
-	if offsetStr != "" {
-		if v, err := strconv.Atoi(offsetStr); err == nil {
-			offset = v
+	limit, err := pagination.ParseLimit(c.Query("limit"), defaultTimelineLimit, maxTimelineLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// El cursor tiene prioridad sobre offset, que se mantiene por compatibilidad
+	var cursor *domain.Cursor
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		decoded, err := domain.DecodeCursor(cursorStr)
+		if err != nil {
+			return nil, err
 		}
+		cursor = &decoded
 	}
 
-	if limitStr != "" {
-		if v, err := strconv.Atoi(limitStr); err == nil {
-			limit = v
+	// since_id pide sólo los tweets más recientes que uno ya visto
+	var since *domain.Since
+	if sinceStr := c.Query("since_id"); sinceStr != "" {
+		parsed, err := domain.ParseSince(sinceStr)
+		if err != nil {
+			return nil, err
 		}
+		since = &parsed
 	}
 
 	return &getTimelineRequest{
 		Username: username,
 		Offset:   offset,
 		Limit:    limit,
+		Cursor:   cursor,
+		Since:    since,
 	}, nil
 }
 
@@ -74,10 +140,15 @@ func (h *TimelineHandler) GetTimeline(c *gin.Context) {
 		return
 	}
 
-	tweets, err := h.service.GetTimeline(c.Request.Context(), req.Username, req.Offset, req.Limit)
+	tweets, err := h.service.GetTimeline(c.Request.Context(), req.Username, domain.Page{
+		Offset: req.Offset,
+		Limit:  req.Limit,
+		Cursor: req.Cursor,
+		Since:  req.Since,
+	})
 	if err != nil {
 		switch err {
-		case application.ErrUserNotFound:
+		case application.ErrUserNotFound, application.ErrTweetNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -87,16 +158,52 @@ func (h *TimelineHandler) GetTimeline(c *gin.Context) {
 
 	var tweetsView []TweetView
 	for _, tweet := range tweets.Tweets {
-		tweetsView = append(tweetsView, TweetView{
-			TweetID:   tweet.ID,
-			Username:  tweet.Username,
-			Content:   tweet.Content,
-			CreatedAt: tweet.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		})
+		tweetsView = append(tweetsView, newTweetView(tweet))
 	}
 
 	c.JSON(http.StatusOK, TimelineResponse{
-		Username: req.Username,
-		Tweets:   tweetsView,
+		Username:   req.Username,
+		Tweets:     tweetsView,
+		NextCursor: tweets.NextCursor,
+	})
+}
+
+type NewTweetsCountResponse struct {
+	Username string `json:"username"`
+	Count    int    `json:"count"`
+	HasMore  bool   `json:"has_more"`
+}
+
+// CountNewTweets devuelve la cantidad de tweets del timeline más recientes
+// que el indicado en since, que puede ser un cursor o el id de un tweet
+func (h *TimelineHandler) CountNewTweets(c *gin.Context) {
+	username := c.Param("username")
+	sinceStr := c.Query("since")
+	if sinceStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since is required"})
+		return
+	}
+
+	since, err := domain.ParseSince(sinceStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	count, err := h.service.CountNewTweets(c.Request.Context(), username, since)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound, application.ErrTweetNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewTweetsCountResponse{
+		Username: username,
+		Count:    count.Count,
+		HasMore:  count.HasMore,
 	})
 }
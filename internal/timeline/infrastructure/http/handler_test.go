@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/application"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTimelineService struct {
+	mock.Mock
+}
+
+func (m *mockTimelineService) GetTimeline(ctx context.Context, username string, page domain.Page) (*domain.Timeline, error) {
+	args := m.Called(ctx, username, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Timeline), args.Error(1)
+}
+
+func (m *mockTimelineService) CountNewTweets(ctx context.Context, username string, since domain.Since) (*domain.NewTweetsCount, error) {
+	args := m.Called(ctx, username, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NewTweetsCount), args.Error(1)
+}
+
+func (m *mockTimelineService) GetTweetsSince(ctx context.Context, username string, since domain.Cursor, limit int) ([]domain.Tweet, error) {
+	args := m.Called(ctx, username, since, limit)
+	return args.Get(0).([]domain.Tweet), args.Error(1)
+}
+
+func TestTimelineHandler_GetTimeline(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		serviceSetup func(*mockTimelineService)
+		expectedCode int
+	}{
+		{
+			name:  "default page",
+			query: "",
+			serviceSetup: func(m *mockTimelineService) {
+				m.On("GetTimeline", mock.Anything, "alice", domain.Page{Limit: 10}).Return(domain.NewTimeline("alice"), nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "offset and limit",
+			query: "?offset=20&limit=5",
+			serviceSetup: func(m *mockTimelineService) {
+				m.On("GetTimeline", mock.Anything, "alice", domain.Page{Offset: 20, Limit: 5}).Return(domain.NewTimeline("alice"), nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "unknown user",
+			query: "",
+			serviceSetup: func(m *mockTimelineService) {
+				m.On("GetTimeline", mock.Anything, "alice", domain.Page{Limit: 10}).Return(nil, application.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "negative offset",
+			query:        "?offset=-1",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "malformed offset",
+			query:        "?offset=abc",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "zero limit",
+			query:        "?limit=0",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "limit above maximum",
+			query:        "?limit=101",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockTimelineService)
+			if tt.serviceSetup != nil {
+				tt.serviceSetup(service)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/timeline/:username", NewTimelineHandler(service).GetTimeline)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/timeline/alice"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/application"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+)
+
+const (
+	defaultHashtagTweetsLimit = 10
+	maxHashtagTweetsLimit     = 100
+	defaultTrendsLimit        = 10
+	maxTrendsLimit            = 50
+	defaultTrendWindow        = "1h"
+)
+
+type HashtagHandler struct {
+	service ports.HashtagService
+}
+
+func NewHashtagHandler(service ports.HashtagService) *HashtagHandler {
+	return &HashtagHandler{service: service}
+}
+
+type HashtagTweetsResponse struct {
+	Hashtag    string      `json:"hashtag"`
+	Tweets     []TweetView `json:"tweets"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+type TrendsResponse struct {
+	Window string         `json:"window"`
+	Trends []domain.Trend `json:"trends"`
+}
+
+func (h *HashtagHandler) GetHashtagTweets(c *gin.Context) {
+	limit, err := pagination.ParseLimit(c.Query("limit"), defaultHashtagTweetsLimit, maxHashtagTweetsLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var cursor *domain.Cursor
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		decoded, err := domain.DecodeCursor(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		cursor = &decoded
+	}
+
+	page, err := h.service.GetHashtagTweets(c.Request.Context(), c.Param("tag"), cursor, limit)
+	if err != nil {
+		switch err {
+		case application.ErrInvalidHashtag:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	tweetsView := make([]TweetView, 0, len(page.Tweets))
+	for _, tweet := range page.Tweets {
+		tweetsView = append(tweetsView, newTweetView(tweet))
+	}
+
+	c.JSON(http.StatusOK, HashtagTweetsResponse{
+		Hashtag:    page.Hashtag,
+		Tweets:     tweetsView,
+		NextCursor: page.NextCursor,
+	})
+}
+
+func (h *HashtagHandler) GetTrends(c *gin.Context) {
+	limit, err := pagination.ParseLimit(c.Query("limit"), defaultTrendsLimit, maxTrendsLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window := c.DefaultQuery("window", defaultTrendWindow)
+	trends, err := h.service.GetTrends(c.Request.Context(), window, limit)
+	if err != nil {
+		switch err {
+		case application.ErrUnknownTrendWindow:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, TrendsResponse{Window: window, Trends: trends})
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/application"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+)
+
+const (
+	// streamWriteTimeout es el tiempo máximo de cada escritura en un stream.
+	// Reemplaza al WriteTimeout del servidor, que limita la respuesta completa.
+	streamWriteTimeout = 10 * time.Second
+
+	// streamRetry es el tiempo en milisegundos que el cliente espera antes de
+	// reconectarse cuando se corta el stream
+	streamRetry = 3000
+)
+
+type StreamHandler struct {
+	service   ports.TimelineStreamService
+	heartbeat time.Duration
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewStreamHandler crea el handler de streams. Cada heartbeat se envía un
+// comentario para que los proxies no corten las conexiones inactivas.
+func NewStreamHandler(service ports.TimelineStreamService, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{
+		service:   service,
+		heartbeat: heartbeat,
+		done:      make(chan struct{}),
+	}
+}
+
+// Close termina los streams abiertos para que el servidor pueda apagarse; los
+// clientes se reconectan a otra instancia con Last-Event-ID
+func (h *StreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+func (h *StreamHandler) StreamTimeline(c *gin.Context) {
+	// EventSource envía Last-Event-ID al reconectarse; el parámetro permite
+	// reanudar una conexión nueva
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var cursor *domain.Cursor
+	if lastEventID != "" {
+		decoded, err := domain.DecodeCursor(lastEventID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		cursor = &decoded
+	}
+
+	stream, err := h.service.OpenStream(c.Request.Context(), c.Param("username"), cursor)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	defer stream.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// Un cliente que no lee bloquea la escritura hasta el deadline, el stream
+	// deja de consumir avisos y se cierra al llenarse su buffer
+	rc := http.NewResponseController(c.Writer)
+	write := func(fn func(io.Writer) error) bool {
+		if err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+			return false
+		}
+		if err := fn(c.Writer); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	if !write(func(w io.Writer) error {
+		return sse.Encode(w, sse.Event{Retry: streamRetry})
+	}) {
+		return
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-ticker.C:
+			if !write(func(w io.Writer) error {
+				_, err := io.WriteString(w, ": heartbeat\n\n")
+				return err
+			}) {
+				return
+			}
+		case tweet, ok := <-stream.Tweets():
+			if !ok {
+				// Al cerrarse por ErrStreamOverflow el cliente se reconecta y
+				// recupera los tweets perdidos con Last-Event-ID
+				return
+			}
+			if !write(func(w io.Writer) error {
+				return sse.Encode(w, sse.Event{
+					Id:    domain.CursorFor(tweet).Encode(),
+					Event: "tweet",
+					Data:  newTweetView(tweet),
+				})
+			}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+)
+
+const (
+	// Espera inicial y máxima entre intentos de volver a suscribirse al bus
+	listenMinBackoff = 100 * time.Millisecond
+	listenMaxBackoff = 30 * time.Second
+)
+
+// LRUConfig agrupa los parámetros del caché local
+type LRUConfig struct {
+	// TTL es cuánto se conserva cada valor en memoria. Acota cuánto puede
+	// servir una réplica un valor que otra modificó si se pierde el aviso.
+	TTL time.Duration
+
+	// MaxBytes es el tamaño máximo de las claves y valores en memoria; al
+	// superarlo se descartan los menos usados recientemente
+	MaxBytes int
+}
+
+// LRUMetrics son los contadores del caché local
+type LRUMetrics struct {
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Evictions     int64 `json:"evictions"`
+	Invalidations int64 `json:"invalidations"`
+	Bytes         int   `json:"bytes"`
+	Entries       int   `json:"entries"`
+}
+
+// pendingRead registra las lecturas de abajo en curso de una clave
+type pendingRead struct {
+	count int
+	// invalidated indica que la clave se invalidó durante alguna de las
+	// lecturas, por lo que el valor leído puede estar desactualizado
+	invalidated bool
+}
+
+type lruEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+func (e *lruEntry) size() int {
+	return len(e.key) + len(e.value)
+}
+
+// LRUCacheRepository es un caché en memoria, acotado y con un TTL corto,
+// delante de otro CacheRepository. Las lecturas que no encuentra las
+// completa desde el caché de abajo y sólo guarda los valores vigentes. Las
+// escrituras e invalidaciones se aplican abajo y se avisan por el bus para
+// que las demás réplicas descarten sus copias.
+type LRUCacheRepository struct {
+	next ports.CacheRepository
+	bus  ports.CacheInvalidationBus
+	cfg  LRUConfig
+	now  func() time.Time
+
+	// minBackoff y maxBackoff acotan la espera entre suscripciones fallidas
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // del más usado recientemente al menos usado
+	bytes   int
+	reads   map[string]*pendingRead
+	metrics LRUMetrics
+}
+
+// NewLRUCacheRepository crea un caché local delante de next
+func NewLRUCacheRepository(next ports.CacheRepository, bus ports.CacheInvalidationBus, cfg LRUConfig) *LRUCacheRepository {
+	return &LRUCacheRepository{
+		next:       next,
+		bus:        bus,
+		cfg:        cfg,
+		now:        time.Now,
+		minBackoff: listenMinBackoff,
+		maxBackoff: listenMaxBackoff,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		reads:      make(map[string]*pendingRead),
+	}
+}
+
+func (c *LRUCacheRepository) Get(ctx context.Context, key string) (string, error) {
+	if value, ok := c.get(key); ok {
+		return value, nil
+	}
+
+	c.beginRead(key)
+	value, err := c.next.Get(ctx, key)
+	c.endRead(key, value, err == nil && value != "")
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func (c *LRUCacheRepository) Lookup(ctx context.Context, key string) (string, bool, error) {
+	if value, ok := c.get(key); ok {
+		return value, true, nil
+	}
+
+	// Los valores vencidos no se guardan para que la lectura siguiente vuelva
+	// a verlos vencidos y se regeneren
+	c.beginRead(key)
+	value, fresh, err := c.next.Lookup(ctx, key)
+	c.endRead(key, value, err == nil && fresh && value != "")
+	if err != nil {
+		return "", false, err
+	}
+	return value, fresh, nil
+}
+
+func (c *LRUCacheRepository) Set(ctx context.Context, key string, value string, tags ...string) error {
+	if err := c.next.Set(ctx, key, value, tags...); err != nil {
+		return err
+	}
+	c.Invalidate([]string{key})
+	c.publishInvalidation(ctx, []string{key})
+	return nil
+}
+
+func (c *LRUCacheRepository) Delete(ctx context.Context, keys ...string) error {
+	if err := c.next.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	c.Invalidate(keys)
+	c.publishInvalidation(ctx, keys)
+	return nil
+}
+
+// InvalidateTag avisa las claves del tag, ya que las demás réplicas no saben
+// a qué tags pertenecen los valores que leyeron. Una clave que se agregue al
+// tag mientras se invalida queda en los cachés locales como mucho por su TTL.
+func (c *LRUCacheRepository) InvalidateTag(ctx context.Context, tag string) error {
+	keys, err := c.next.TagKeys(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if err := c.next.InvalidateTag(ctx, tag); err != nil {
+		return err
+	}
+	c.Invalidate(keys)
+	c.publishInvalidation(ctx, keys)
+	return nil
+}
+
+func (c *LRUCacheRepository) TagKeys(ctx context.Context, tag string) ([]string, error) {
+	return c.next.TagKeys(ctx, tag)
+}
+
+// publishInvalidation avisa las claves a las demás réplicas. La escritura de
+// abajo ya se aplicó, por lo que un error al avisar no se devuelve: las
+// copias de las demás réplicas se descartan como mucho al vencer su TTL.
+func (c *LRUCacheRepository) publishInvalidation(ctx context.Context, keys []string) {
+	if err := c.bus.PublishInvalidation(ctx, keys); err != nil {
+		logger.Error("error publishing cache invalidation: " + err.Error())
+	}
+}
+
+// ListenInvalidations descarta las claves que avisan las demás réplicas hasta
+// que se cancele ctx. Si la suscripción falla vuelve a intentarla con una
+// espera creciente, y descarta todo el caché local porque pudo perder avisos.
+func (c *LRUCacheRepository) ListenInvalidations(ctx context.Context) {
+	backoff := c.minBackoff
+	for {
+		err := c.bus.SubscribeInvalidations(ctx, c.Invalidate)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		logger.Error("cache invalidation subscription failed: " + err.Error())
+		c.invalidateAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, c.maxBackoff)
+	}
+}
+
+// Invalidate descarta claves del caché local
+func (c *LRUCacheRepository) Invalidate(keys []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if read, ok := c.reads[key]; ok {
+			read.invalidated = true
+		}
+		if element, ok := c.entries[key]; ok {
+			c.removeLocked(element)
+			c.metrics.Invalidations++
+		}
+	}
+}
+
+// invalidateAll descarta todo el caché local y los valores que se estén leyendo
+func (c *LRUCacheRepository) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, read := range c.reads {
+		read.invalidated = true
+	}
+	c.metrics.Invalidations += int64(len(c.entries))
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.bytes = 0
+}
+
+// Metrics devuelve los contadores del caché local
+func (c *LRUCacheRepository) Metrics() LRUMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metrics := c.metrics
+	metrics.Bytes = c.bytes
+	metrics.Entries = len(c.entries)
+	return metrics
+}
+
+func (c *LRUCacheRepository) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.metrics.Misses++
+		return "", false
+	}
+	entry := element.Value.(*lruEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.removeLocked(element)
+		c.metrics.Misses++
+		return "", false
+	}
+
+	c.order.MoveToFront(element)
+	c.metrics.Hits++
+	return entry.value, true
+}
+
+func (c *LRUCacheRepository) beginRead(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	read, ok := c.reads[key]
+	if !ok {
+		read = &pendingRead{}
+		c.reads[key] = read
+	}
+	read.count++
+}
+
+// endRead termina una lectura de abajo y, si store es true, guarda el valor
+// leído salvo que la clave se haya invalidado mientras se leía
+func (c *LRUCacheRepository) endRead(key, value string, store bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	read := c.reads[key]
+	read.count--
+	if read.count == 0 {
+		delete(c.reads, key)
+	}
+	if store && !read.invalidated {
+		c.addLocked(key, value)
+	}
+}
+
+// addLocked guarda un valor y descarta los menos usados hasta volver a MaxBytes
+func (c *LRUCacheRepository) addLocked(key, value string) {
+	entry := &lruEntry{key: key, value: value, expiresAt: c.now().Add(c.cfg.TTL)}
+	if entry.size() > c.cfg.MaxBytes {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.removeLocked(element)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	c.bytes += entry.size()
+
+	for c.bytes > c.cfg.MaxBytes {
+		c.removeLocked(c.order.Back())
+		c.metrics.Evictions++
+	}
+}
+
+func (c *LRUCacheRepository) removeLocked(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size()
+}
@@ -0,0 +1,378 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type remoteEntry struct {
+	value string
+	fresh bool
+	tags  []string
+}
+
+// remoteCache simula el caché compartido entre réplicas y cuenta sus lecturas
+type remoteCache struct {
+	mu      sync.Mutex
+	entries map[string]remoteEntry
+	reads   int
+}
+
+func newRemoteCache() *remoteCache {
+	return &remoteCache{entries: make(map[string]remoteEntry)}
+}
+
+func (c *remoteCache) Get(ctx context.Context, key string) (string, error) {
+	value, fresh, err := c.Lookup(ctx, key)
+	if !fresh {
+		return "", err
+	}
+	return value, err
+}
+
+func (c *remoteCache) Lookup(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reads++
+	entry := c.entries[key]
+	return entry.value, entry.fresh, nil
+}
+
+func (c *remoteCache) Set(ctx context.Context, key string, value string, tags ...string) error {
+	c.put(key, value, true, tags...)
+	return nil
+}
+
+func (c *remoteCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *remoteCache) InvalidateTag(ctx context.Context, tag string) error {
+	keys, _ := c.TagKeys(ctx, tag)
+	return c.Delete(ctx, keys...)
+}
+
+func (c *remoteCache) TagKeys(ctx context.Context, tag string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	for key, entry := range c.entries {
+		for _, t := range entry.tags {
+			if t == tag {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (c *remoteCache) put(key, value string, fresh bool, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = remoteEntry{value: value, fresh: fresh, tags: tags}
+}
+
+func (c *remoteCache) readCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reads
+}
+
+// fakeBus entrega los avisos de forma sincrónica a los suscriptores de las
+// demás réplicas
+type fakeBus struct {
+	mu       sync.Mutex
+	handlers map[*fakeReplica]func(keys []string)
+}
+
+type fakeReplica struct {
+	bus *fakeBus
+}
+
+func (b *fakeBus) replica() *fakeReplica {
+	return &fakeReplica{bus: b}
+}
+
+func (r *fakeReplica) PublishInvalidation(ctx context.Context, keys []string) error {
+	r.bus.mu.Lock()
+	defer r.bus.mu.Unlock()
+	for replica, handler := range r.bus.handlers {
+		if replica != r {
+			handler(keys)
+		}
+	}
+	return nil
+}
+
+func (r *fakeReplica) SubscribeInvalidations(ctx context.Context, handler func(keys []string)) error {
+	r.bus.mu.Lock()
+	defer r.bus.mu.Unlock()
+	if r.bus.handlers == nil {
+		r.bus.handlers = make(map[*fakeReplica]func(keys []string))
+	}
+	r.bus.handlers[r] = handler
+	return nil
+}
+
+func newTestLRU(remote *remoteCache, bus *fakeBus, maxBytes int) *LRUCacheRepository {
+	cache := NewLRUCacheRepository(remote, bus.replica(), LRUConfig{TTL: time.Second, MaxBytes: maxBytes})
+	cache.ListenInvalidations(context.Background())
+	return cache
+}
+
+func TestLRUCacheRepository_ReadThrough(t *testing.T) {
+	remote := newRemoteCache()
+	remote.put("key", "value", true)
+	cache := newTestLRU(remote, &fakeBus{}, 1024)
+
+	for i := 0; i < 3; i++ {
+		value, err := cache.Get(context.Background(), "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	}
+
+	assert.Equal(t, 1, remote.readCount())
+	assert.Equal(t, LRUMetrics{Hits: 2, Misses: 1, Bytes: len("key") + len("value"), Entries: 1}, cache.Metrics())
+}
+
+func TestLRUCacheRepository_EmptyValuesAreNotCached(t *testing.T) {
+	remote := newRemoteCache()
+	cache := newTestLRU(remote, &fakeBus{}, 1024)
+
+	for i := 0; i < 2; i++ {
+		value, err := cache.Get(context.Background(), "key")
+		assert.NoError(t, err)
+		assert.Empty(t, value)
+	}
+
+	assert.Equal(t, 2, remote.readCount())
+}
+
+func TestLRUCacheRepository_ExpiresAfterTTL(t *testing.T) {
+	remote := newRemoteCache()
+	remote.put("key", "value", true)
+	cache := newTestLRU(remote, &fakeBus{}, 1024)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_, _ = cache.Get(context.Background(), "key")
+	now = now.Add(999 * time.Millisecond)
+	_, _ = cache.Get(context.Background(), "key")
+	assert.Equal(t, 1, remote.readCount())
+
+	now = now.Add(time.Millisecond)
+	_, _ = cache.Get(context.Background(), "key")
+	assert.Equal(t, 2, remote.readCount())
+}
+
+func TestLRUCacheRepository_EvictsLeastRecentlyUsed(t *testing.T) {
+	remote := newRemoteCache()
+	remote.put("a", "1", true)
+	remote.put("b", "2", true)
+	remote.put("c", "3", true)
+	// Cada entrada ocupa 2 bytes, por lo que entran dos
+	cache := newTestLRU(remote, &fakeBus{}, 4)
+	ctx := context.Background()
+
+	_, _ = cache.Get(ctx, "a")
+	_, _ = cache.Get(ctx, "b")
+	_, _ = cache.Get(ctx, "a")
+	_, _ = cache.Get(ctx, "c")
+
+	reads := remote.readCount()
+	_, _ = cache.Get(ctx, "a")
+	_, _ = cache.Get(ctx, "c")
+	assert.Equal(t, reads, remote.readCount())
+	_, _ = cache.Get(ctx, "b")
+	assert.Equal(t, reads+1, remote.readCount())
+
+	metrics := cache.Metrics()
+	assert.Equal(t, int64(2), metrics.Evictions)
+	assert.Equal(t, 4, metrics.Bytes)
+	assert.Equal(t, 2, metrics.Entries)
+}
+
+func TestLRUCacheRepository_OversizedValuesAreNotCached(t *testing.T) {
+	remote := newRemoteCache()
+	remote.put("key", "a value larger than the cache", true)
+	cache := newTestLRU(remote, &fakeBus{}, 8)
+
+	_, _ = cache.Get(context.Background(), "key")
+	_, _ = cache.Get(context.Background(), "key")
+
+	assert.Equal(t, 2, remote.readCount())
+	assert.Equal(t, 0, cache.Metrics().Entries)
+}
+
+func TestLRUCacheRepository_LookupDoesNotCacheStaleValues(t *testing.T) {
+	remote := newRemoteCache()
+	remote.put("key", "stale", false)
+	cache := newTestLRU(remote, &fakeBus{}, 1024)
+
+	for i := 0; i < 2; i++ {
+		value, fresh, err := cache.Lookup(context.Background(), "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "stale", value)
+		assert.False(t, fresh)
+	}
+	assert.Equal(t, 2, remote.readCount())
+
+	remote.put("key", "refreshed", true)
+	_, _, _ = cache.Lookup(context.Background(), "key")
+	value, fresh, _ := cache.Lookup(context.Background(), "key")
+	assert.Equal(t, "refreshed", value)
+	assert.True(t, fresh)
+	assert.Equal(t, 3, remote.readCount())
+}
+
+func TestLRUCacheRepository_InvalidatesOtherReplicas(t *testing.T) {
+	tests := []struct {
+		name       string
+		invalidate func(ctx context.Context, cache *LRUCacheRepository) error
+		expected   string
+	}{
+		{
+			name: "set",
+			invalidate: func(ctx context.Context, cache *LRUCacheRepository) error {
+				return cache.Set(ctx, "key", "new", "tag")
+			},
+			expected: "new",
+		},
+		{
+			name: "delete",
+			invalidate: func(ctx context.Context, cache *LRUCacheRepository) error {
+				return cache.Delete(ctx, "key")
+			},
+		},
+		{
+			name: "invalidate tag",
+			invalidate: func(ctx context.Context, cache *LRUCacheRepository) error {
+				return cache.InvalidateTag(ctx, "tag")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			remote := newRemoteCache()
+			remote.put("key", "old", true, "tag")
+			bus := &fakeBus{}
+			replicaA := newTestLRU(remote, bus, 1024)
+			replicaB := newTestLRU(remote, bus, 1024)
+
+			_, _ = replicaA.Get(ctx, "key")
+			_, _ = replicaB.Get(ctx, "key")
+
+			assert.NoError(t, tt.invalidate(ctx, replicaA))
+
+			for _, replica := range []*LRUCacheRepository{replicaA, replicaB} {
+				value, err := replica.Get(ctx, "key")
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, value)
+			}
+			assert.Equal(t, int64(1), replicaB.Metrics().Invalidations)
+		})
+	}
+}
+
+func TestLRUCacheRepository_InvalidationDuringReadIsNotCached(t *testing.T) {
+	remote := newRemoteCache()
+	remote.put("key", "old", true)
+	cache := newTestLRU(remote, &fakeBus{}, 1024)
+
+	// La clave se invalida mientras se lee el valor anterior de abajo
+	cache.beginRead("key")
+	cache.Invalidate([]string{"key"})
+	cache.endRead("key", "old", true)
+
+	remote.put("key", "new", true)
+	value, err := cache.Get(context.Background(), "key")
+	assert.NoError(t, err)
+	assert.Equal(t, "new", value)
+}
+
+// flakyBus falla las primeras suscripciones y todas las publicaciones
+type flakyBus struct {
+	mu                sync.Mutex
+	failSubscriptions int
+	subscriptions     int
+}
+
+func (b *flakyBus) PublishInvalidation(ctx context.Context, keys []string) error {
+	return errors.New("publish failed")
+}
+
+func (b *flakyBus) SubscribeInvalidations(ctx context.Context, handler func(keys []string)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions++
+	if b.subscriptions <= b.failSubscriptions {
+		return errors.New("subscribe failed")
+	}
+	return nil
+}
+
+func TestLRUCacheRepository_PublishErrorsAreNotReturned(t *testing.T) {
+	ctx := context.Background()
+	remote := newRemoteCache()
+	remote.put("key", "old", true, "tag")
+	cache := NewLRUCacheRepository(remote, &flakyBus{}, LRUConfig{TTL: time.Second, MaxBytes: 1024})
+
+	assert.NoError(t, cache.Set(ctx, "key", "new", "tag"))
+	assert.NoError(t, cache.Delete(ctx, "other"))
+	assert.NoError(t, cache.InvalidateTag(ctx, "tag"))
+
+	value, err := cache.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Empty(t, value)
+}
+
+func TestLRUCacheRepository_ListenRetriesFailedSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	remote := newRemoteCache()
+	remote.put("key", "value", true)
+	bus := &flakyBus{failSubscriptions: 2}
+	cache := NewLRUCacheRepository(remote, bus, LRUConfig{TTL: time.Second, MaxBytes: 1024})
+	cache.minBackoff = time.Millisecond
+	cache.maxBackoff = time.Millisecond
+
+	_, _ = cache.Get(ctx, "key")
+	cache.ListenInvalidations(ctx)
+
+	assert.Equal(t, 3, bus.subscriptions)
+	// El caché local se descarta porque pudo perder avisos mientras no
+	// estaba suscrito
+	assert.Equal(t, 0, cache.Metrics().Entries)
+}
+
+func TestLRUCacheRepository_ListenStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := &flakyBus{failSubscriptions: 1}
+	cache := NewLRUCacheRepository(newRemoteCache(), bus, LRUConfig{TTL: time.Second, MaxBytes: 1024})
+	cache.minBackoff = time.Hour
+
+	done := make(chan struct{})
+	go func() {
+		cache.ListenInvalidations(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("listener did not stop after cancellation")
+	}
+	assert.Equal(t, 1, bus.subscriptions)
+}
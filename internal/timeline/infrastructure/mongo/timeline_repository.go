@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
@@ -17,6 +18,33 @@ type mongoTweet struct {
 	Username  string    `bson:"username"`
 	Content   string    `bson:"content"`
 	CreatedAt time.Time `bson:"createdAt"`
+	LikeCount int64     `bson:"likeCount"`
+
+	Entities *domain.Entities `bson:"entities,omitempty"`
+
+	RetweetOf     string `bson:"retweetOf,omitempty"`
+	QuotedTweetID string `bson:"quotedTweetId,omitempty"`
+}
+
+// notDeleted excluye los tweets eliminados, que se conservan con deletedAt
+var notDeleted = bson.M{"$exists": false}
+
+// timelineSort ordena por fecha de creación descendente y desempata por id
+// para que la paginación sea estable
+var timelineSort = bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}
+
+func (t mongoTweet) toDomain() domain.Tweet {
+	return domain.Tweet{
+		ID:        t.ID,
+		Username:  t.Username,
+		Content:   t.Content,
+		CreatedAt: t.CreatedAt,
+		LikeCount: t.LikeCount,
+		Entities:  t.Entities,
+
+		RetweetOf:     t.RetweetOf,
+		QuotedTweetID: t.QuotedTweetID,
+	}
 }
 
 // mongoTimelineRepository es la implementación de TimelineRepository usando MongoDB.
@@ -37,12 +65,102 @@ func (r *mongoTimelineRepository) GetTweetsForUsers(ctx context.Context, usernam
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	filter := bson.M{"username": bson.M{"$in": usernames}}
+	filter := bson.M{"username": bson.M{"$in": usernames}, "deletedAt": notDeleted}
 	opts := options.Find().
-		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSort(timelineSort).
 		SetSkip(int64(offset)).
 		SetLimit(int64(limit))
 
+	return r.find(ctx, filter, opts)
+}
+
+// GetTweetsForUsersBefore obtiene los tweets de una lista de usuarios
+// posteriores al cursor, sin depender de SetSkip
+func (r *mongoTimelineRepository) GetTweetsForUsersBefore(ctx context.Context, usernames []string, cursor domain.Cursor, limit int) ([]domain.Tweet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"username":  bson.M{"$in": usernames},
+		"deletedAt": notDeleted,
+		"$or": bson.A{
+			bson.M{"createdAt": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		},
+	}
+	opts := options.Find().
+		SetSort(timelineSort).
+		SetLimit(int64(limit))
+
+	return r.find(ctx, filter, opts)
+}
+
+// afterCursor filtra los tweets más recientes que el cursor
+func afterCursor(cursor domain.Cursor) bson.A {
+	return bson.A{
+		bson.M{"createdAt": bson.M{"$gt": cursor.CreatedAt}},
+		bson.M{"createdAt": cursor.CreatedAt, "_id": bson.M{"$gt": cursor.ID}},
+	}
+}
+
+// GetTweetsForUsersAfter obtiene los tweets más recientes que el cursor
+// usando el índice username,createdAt,_id
+func (r *mongoTimelineRepository) GetTweetsForUsersAfter(ctx context.Context, usernames []string, since domain.Cursor, limit int) ([]domain.Tweet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"username":  bson.M{"$in": usernames},
+		"deletedAt": notDeleted,
+		"$or":       afterCursor(since),
+	}
+	opts := options.Find().
+		SetSort(timelineSort).
+		SetLimit(int64(limit))
+
+	return r.find(ctx, filter, opts)
+}
+
+// CountTweetsForUsersAfter cuenta los tweets más recientes que el cursor. El
+// límite corta la cuenta para que su costo no dependa de la cantidad de
+// tweets nuevos.
+func (r *mongoTimelineRepository) CountTweetsForUsersAfter(ctx context.Context, usernames []string, since domain.Cursor, limit int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"username":  bson.M{"$in": usernames},
+		"deletedAt": notDeleted,
+		"$or":       afterCursor(since),
+	}
+	count, err := r.collection.CountDocuments(ctx, filter, options.Count().SetLimit(int64(limit)))
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GetTweetsByHashtag obtiene los tweets con un hashtag usando el índice
+// hashtags,createdAt,_id que crea el servicio de tweets
+func (r *mongoTimelineRepository) GetTweetsByHashtag(ctx context.Context, tag string, cursor *domain.Cursor, limit int) ([]domain.Tweet, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"hashtags": tag, "deletedAt": notDeleted}
+	if cursor != nil {
+		filter["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+	opts := options.Find().
+		SetSort(timelineSort).
+		SetLimit(int64(limit))
+
+	return r.find(ctx, filter, opts)
+}
+
+func (r *mongoTimelineRepository) find(ctx context.Context, filter interface{}, opts *options.FindOptions) ([]domain.Tweet, error) {
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
@@ -55,12 +173,64 @@ func (r *mongoTimelineRepository) GetTweetsForUsers(ctx context.Context, usernam
 		if err := cursor.Decode(&mongoTweet); err != nil {
 			return nil, err
 		}
-		tweets = append(tweets, domain.Tweet{
-			ID:        mongoTweet.ID,
-			Username:  mongoTweet.Username,
-			Content:   mongoTweet.Content,
-			CreatedAt: mongoTweet.CreatedAt,
-		})
+		tweets = append(tweets, mongoTweet.toDomain())
+	}
+
+	return tweets, cursor.Err()
+}
+
+// GetTweetCursor obtiene el cursor de un tweet sin excluir los eliminados
+func (r *mongoTimelineRepository) GetTweetCursor(ctx context.Context, id string) (*domain.Cursor, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var tweet mongoTweet
+	opts := options.FindOne().SetProjection(bson.M{"_id": 1, "createdAt": 1})
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&tweet)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := domain.CursorFor(tweet.toDomain())
+	return &cursor, nil
+}
+
+// GetTweetsByIDs obtiene los tweets con los ids indicados en el mismo orden.
+// Los ids que no existen se omiten.
+func (r *mongoTimelineRepository) GetTweetsByIDs(ctx context.Context, ids []string) ([]domain.Tweet, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deletedAt": notDeleted})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	byID := make(map[string]domain.Tweet, len(ids))
+	for cursor.Next(ctx) {
+		var mongoTweet mongoTweet
+		if err := cursor.Decode(&mongoTweet); err != nil {
+			return nil, err
+		}
+		byID[mongoTweet.ID] = mongoTweet.toDomain()
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	tweets := make([]domain.Tweet, 0, len(ids))
+	for _, id := range ids {
+		if tweet, ok := byID[id]; ok {
+			tweets = append(tweets, tweet)
+		}
 	}
 
 	return tweets, nil
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+)
+
+// authorKeyPrefix es el prefijo de la clave con el perfil de cada autor
+const authorKeyPrefix = "author:"
+
+type redisAuthorRepository struct {
+	client *redis.Client
+}
+
+// NewRedisAuthorRepository crea una nueva instancia del repositorio de autores
+func NewRedisAuthorRepository(client *redis.Client) *redisAuthorRepository {
+	return &redisAuthorRepository{client: client}
+}
+
+func authorKey(username string) string {
+	return authorKeyPrefix + username
+}
+
+// saveAuthorScript guarda el perfil salvo que el guardado tenga una versión
+// mayor. Los perfiles sin versión cuentan como versión 0. ARGV: perfil, versión
+var saveAuthorScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current then
+	local version = cjson.decode(current)["version"] or 0
+	if version > tonumber(ARGV[2]) then
+		return 0
+	end
+end
+redis.call("SET", KEYS[1], ARGV[1])
+return 1
+`)
+
+func (r *redisAuthorRepository) SaveAuthor(ctx context.Context, author domain.Author) error {
+	data, err := json.Marshal(author)
+	if err != nil {
+		return err
+	}
+	return saveAuthorScript.Run(ctx, r.client, []string{authorKey(author.Username)}, data, author.Version).Err()
+}
+
+func (r *redisAuthorRepository) GetAuthors(ctx context.Context, usernames []string) (map[string]domain.Author, error) {
+	authors := make(map[string]domain.Author, len(usernames))
+	if len(usernames) == 0 {
+		return authors, nil
+	}
+
+	keys := make([]string, len(usernames))
+	for i, username := range usernames {
+		keys[i] = authorKey(username)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var author domain.Author
+		if err := json.Unmarshal([]byte(raw), &author); err != nil {
+			continue
+		}
+		authors[author.Username] = author
+	}
+	return authors, nil
+}
@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// cacheInvalidationChannel es el canal de pub/sub de los avisos de claves modificadas
+const cacheInvalidationChannel = "cache:invalidations"
+
+// cacheInvalidation es el mensaje publicado en el canal. Origin identifica a
+// la réplica que lo publicó para que no procese sus propios avisos.
+type cacheInvalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+// redisCacheInvalidationBus distribuye los avisos de claves modificadas por
+// pub/sub. Los avisos no se persisten: los publicados mientras una réplica
+// está desconectada se pierden y su caché local los cubre con su TTL.
+type redisCacheInvalidationBus struct {
+	client *redis.Client
+	origin string
+}
+
+// NewRedisCacheInvalidationBus crea una nueva instancia del bus de avisos de
+// invalidación del caché
+func NewRedisCacheInvalidationBus(client *redis.Client) *redisCacheInvalidationBus {
+	return &redisCacheInvalidationBus{
+		client: client,
+		origin: uuid.NewString(),
+	}
+}
+
+func (b *redisCacheInvalidationBus) PublishInvalidation(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(cacheInvalidation{Origin: b.origin, Keys: keys})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, cacheInvalidationChannel, data).Err()
+}
+
+func (b *redisCacheInvalidationBus) SubscribeInvalidations(ctx context.Context, handler func(keys []string)) error {
+	pubsub := b.client.Subscribe(ctx, cacheInvalidationChannel)
+	defer pubsub.Close()
+
+	// Confirmar la suscripción antes de empezar a recibir
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var invalidation cacheInvalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &invalidation); err != nil {
+				continue
+			}
+			if invalidation.Origin != b.origin {
+				handler(invalidation.Keys)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
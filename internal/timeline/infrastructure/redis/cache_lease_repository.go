@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// cacheLeaseKeyPrefix es el prefijo de las claves de los leases de regeneración
+const cacheLeaseKeyPrefix = "cachelease:"
+
+type redisCacheLeaseRepository struct {
+	client *redis.Client
+}
+
+// NewRedisCacheLeaseRepository crea una nueva instancia del repositorio de
+// leases de regeneración del caché
+func NewRedisCacheLeaseRepository(client *redis.Client) *redisCacheLeaseRepository {
+	return &redisCacheLeaseRepository{client: client}
+}
+
+func cacheLeaseKey(key string) string {
+	return cacheLeaseKeyPrefix + key
+}
+
+func (r *redisCacheLeaseRepository) AcquireLease(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+	acquired, err := r.client.SetNX(ctx, cacheLeaseKey(key), token, ttl).Result()
+	if err != nil || !acquired {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// releaseLeaseScript borra el lease sólo si sigue siendo del token, para no
+// liberar el de otra réplica si el propio ya venció. ARGV: token
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+func (r *redisCacheLeaseRepository) ReleaseLease(ctx context.Context, key, token string) error {
+	return releaseLeaseScript.Run(ctx, r.client, []string{cacheLeaseKey(key)}, token).Err()
+}
@@ -7,23 +7,90 @@ import (
 	"github.com/go-redis/redis/v8"
 )
 
+// cacheTagKeyPrefix es el prefijo de los sets que agrupan las claves de cada tag
+const cacheTagKeyPrefix = "cachetag:"
+
+// redisCacheRepository guarda cada clave por ttl+staleTTL. Durante el último
+// tramo de staleTTL la clave está vencida: Get no la devuelve, pero Lookup sí
+// para servirla mientras se regenera.
 type redisCacheRepository struct {
-	client *redis.Client
-	ttl    time.Duration
+	client   *redis.Client
+	ttl      time.Duration
+	staleTTL time.Duration
 }
 
 // NewRedisCacheRepository crea una nueva instancia del repositorio de caché
-func NewRedisCacheRepository(client *redis.Client, ttl time.Duration) *redisCacheRepository {
+func NewRedisCacheRepository(client *redis.Client, ttl, staleTTL time.Duration) *redisCacheRepository {
 	return &redisCacheRepository{
-		client: client,
-		ttl:    ttl,
+		client:   client,
+		ttl:      ttl,
+		staleTTL: staleTTL,
 	}
 }
 
+func cacheTagKey(tag string) string {
+	return cacheTagKeyPrefix + tag
+}
+
 func (r *redisCacheRepository) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+	value, fresh, err := r.Lookup(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !fresh {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (r *redisCacheRepository) Lookup(ctx context.Context, key string) (string, bool, error) {
+	var value *redis.StringCmd
+	var remaining *redis.DurationCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		value = pipe.Get(ctx, key)
+		remaining = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return value.Val(), remaining.Val() > r.staleTTL, nil
+}
+
+func (r *redisCacheRepository) Set(ctx context.Context, key string, value string, tags ...string) error {
+	expiration := r.ttl + r.staleTTL
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, value, expiration)
+		for _, tag := range tags {
+			// El set del tag vive lo mismo que la última clave agregada
+			pipe.SAdd(ctx, cacheTagKey(tag), key)
+			pipe.Expire(ctx, cacheTagKey(tag), expiration)
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *redisCacheRepository) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
+
+func (r *redisCacheRepository) InvalidateTag(ctx context.Context, tag string) error {
+	tagKey := cacheTagKey(tag)
+	keys, err := r.client.SMembers(ctx, tagKey).Result()
+	if err != nil {
+		return err
+	}
+	return r.client.Del(ctx, append(keys, tagKey)...).Err()
 }
 
-func (r *redisCacheRepository) Set(ctx context.Context, key string, value string) error {
-	return r.client.Set(ctx, key, value, r.ttl).Err()
+func (r *redisCacheRepository) TagKeys(ctx context.Context, tag string) ([]string, error) {
+	return r.client.SMembers(ctx, cacheTagKey(tag)).Result()
 }
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// celebritiesKey es el set con los usernames clasificados como celebridades
+const celebritiesKey = "celebrities"
+
+type redisCelebrityRepository struct {
+	client *redis.Client
+}
+
+// NewRedisCelebrityRepository crea una nueva instancia del repositorio de celebridades
+func NewRedisCelebrityRepository(client *redis.Client) *redisCelebrityRepository {
+	return &redisCelebrityRepository{client: client}
+}
+
+func (r *redisCelebrityRepository) SetCelebrity(ctx context.Context, username string, celebrity bool) error {
+	if celebrity {
+		return r.client.SAdd(ctx, celebritiesKey, username).Err()
+	}
+	return r.client.SRem(ctx, celebritiesKey, username).Err()
+}
+
+func (r *redisCelebrityRepository) FilterCelebrities(ctx context.Context, usernames []string) ([]string, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	members := make([]interface{}, len(usernames))
+	for i, username := range usernames {
+		members[i] = username
+	}
+
+	flags, err := r.client.SMIsMember(ctx, celebritiesKey, members...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var celebrities []string
+	for i, isMember := range flags {
+		if isMember {
+			celebrities = append(celebrities, usernames[i])
+		}
+	}
+	return celebrities, nil
+}
@@ -0,0 +1,282 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+)
+
+const (
+	// homeTimelineKeyPrefix es el prefijo de los sorted sets de timelines precalculados
+	homeTimelineKeyPrefix = "hometimeline:"
+
+	// homeTimelineSeededMember marca, con score -inf, los timelines
+	// inicializados desde MongoDB. Al estar dentro del mismo sorted set, si la
+	// clave se pierde por un flush o una expulsión se pierde también la marca.
+	homeTimelineSeededMember = "_seeded"
+)
+
+// redisHomeTimelineRepository guarda el timeline de cada usuario como un sorted
+// set de ids de tweets con score igual a la fecha de creación (ms). Sólo los
+// timelines con la marca de inicializado se consideran completos y reciben
+// tweets; los demás se leen de MongoDB hasta que se inicializan.
+type redisHomeTimelineRepository struct {
+	client    *redis.Client
+	maxLength int
+}
+
+// NewRedisHomeTimelineRepository crea una nueva instancia del repositorio de
+// timelines precalculados. Cada timeline se recorta a maxLength tweets.
+func NewRedisHomeTimelineRepository(client *redis.Client, maxLength int) *redisHomeTimelineRepository {
+	return &redisHomeTimelineRepository{
+		client:    client,
+		maxLength: maxLength,
+	}
+}
+
+func homeTimelineKey(username string) string {
+	return homeTimelineKeyPrefix + username
+}
+
+func (r *redisHomeTimelineRepository) AddTweet(ctx context.Context, usernames []string, tweetID string, createdAt time.Time) error {
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	// Un ZADD directo crearía la clave con un único tweet, que las lecturas
+	// tomarían como el timeline completo
+	args := []interface{}{homeTimelineSeededMember, r.maxLength, createdAt.UnixMilli(), tweetID}
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, username := range usernames {
+			addIfSeededScript.Eval(ctx, pipe, []string{homeTimelineKey(username)}, args...)
+		}
+		return nil
+	})
+	return err
+}
+
+// seeded devuelve el comando que indica si el timeline tiene la marca de inicializado
+func seeded(ctx context.Context, pipe redis.Pipeliner, key string) *redis.IntCmd {
+	return pipe.ZCount(ctx, key, "-inf", "-inf")
+}
+
+func (r *redisHomeTimelineRepository) GetTweetIDs(ctx context.Context, username string, offset, limit int) ([]string, bool, error) {
+	key := homeTimelineKey(username)
+
+	var exists *redis.IntCmd
+	var ids *redis.StringSliceCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		exists = seeded(ctx, pipe, key)
+		ids = pipe.ZRevRange(ctx, key, int64(offset), int64(offset+limit-1))
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if exists.Val() == 0 {
+		return nil, false, nil
+	}
+
+	// La marca tiene el menor score y sólo aparece al final del timeline
+	page := ids.Val()
+	if n := len(page); n > 0 && page[n-1] == homeTimelineSeededMember {
+		page = page[:n-1]
+	}
+	return page, true, nil
+}
+
+func (r *redisHomeTimelineRepository) GetTweetIDsBefore(ctx context.Context, username string, cursor domain.Cursor, limit int) ([]string, bool, error) {
+	key := homeTimelineKey(username)
+	score := strconv.FormatInt(cursor.CreatedAt.UnixMilli(), 10)
+
+	// Los empates de score se ordenan por miembro descendente, el mismo
+	// desempate que usa el cursor. Se piden tantos tweets extra como empates
+	// haya con el cursor para poder descartar los que no van después de él.
+	var exists, ties *redis.IntCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		exists = seeded(ctx, pipe, key)
+		ties = pipe.ZCount(ctx, key, score, score)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if exists.Val() == 0 {
+		return nil, false, nil
+	}
+
+	entries, err := r.client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Max:   score,
+		Min:   "(-inf", // excluye la marca de inicializado
+		Count: int64(limit) + ties.Val(),
+	}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	cursorScore := float64(cursor.CreatedAt.UnixMilli())
+	ids := make([]string, 0, limit)
+	for _, entry := range entries {
+		id, _ := entry.Member.(string)
+		if entry.Score == cursorScore && id >= cursor.ID {
+			continue
+		}
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, true, nil
+}
+
+func (r *redisHomeTimelineRepository) GetTweetIDsAfter(ctx context.Context, username string, since domain.Cursor, limit int) ([]string, bool, error) {
+	key := homeTimelineKey(username)
+	score := strconv.FormatInt(since.CreatedAt.UnixMilli(), 10)
+
+	// Los empates con el cursor quedan al final del rango; se piden tantos
+	// tweets extra como empates haya para poder descartar los ya vistos
+	var exists, ties *redis.IntCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		exists = seeded(ctx, pipe, key)
+		ties = pipe.ZCount(ctx, key, score, score)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if exists.Val() == 0 {
+		return nil, false, nil
+	}
+
+	entries, err := r.client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Max:   "+inf",
+		Min:   score,
+		Count: int64(limit) + ties.Val(),
+	}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	sinceScore := float64(since.CreatedAt.UnixMilli())
+	ids := make([]string, 0, limit)
+	for _, entry := range entries {
+		id, _ := entry.Member.(string)
+		if entry.Score == sinceScore && id <= since.ID {
+			continue
+		}
+		if len(ids) == limit {
+			break
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, true, nil
+}
+
+func (r *redisHomeTimelineRepository) CountTweetIDsAfter(ctx context.Context, username string, since domain.Cursor) (int, bool, error) {
+	key := homeTimelineKey(username)
+	score := strconv.FormatInt(since.CreatedAt.UnixMilli(), 10)
+
+	// ZCOUNT resuelve los tweets de fecha posterior; los empates con el cursor
+	// se desempatan por id igual que en la paginación
+	var exists, newer *redis.IntCmd
+	var ties *redis.StringSliceCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		exists = seeded(ctx, pipe, key)
+		newer = pipe.ZCount(ctx, key, "("+score, "+inf")
+		ties = pipe.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: score, Max: score})
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if exists.Val() == 0 {
+		return 0, false, nil
+	}
+
+	count := int(newer.Val())
+	for _, id := range ties.Val() {
+		if id > since.ID {
+			count++
+		}
+	}
+	return count, true, nil
+}
+
+// addIfSeededScript agrega tweets a un timeline sólo si está inicializado y
+// lo recorta a los maxLength más recientes, conservando la marca en el rango
+// 0. ARGV: marca, maxLength, score1, member1, ...
+var addIfSeededScript = redis.NewScript(`
+if redis.call("ZSCORE", KEYS[1], ARGV[1]) == false then
+	return 0
+end
+for i = 3, #ARGV, 2 do
+	redis.call("ZADD", KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call("ZREMRANGEBYRANK", KEYS[1], 1, -tonumber(ARGV[2]) - 1)
+return 1
+`)
+
+// seedScript inicializa un timeline con sus tweets y la marca. Los tweets que
+// ya tuviera, como los del fan-out que llegaron durante la consulta a
+// MongoDB, se conservan. ARGV: marca, maxLength, score1, member1, ...
+var seedScript = redis.NewScript(`
+redis.call("ZADD", KEYS[1], "-inf", ARGV[1])
+for i = 3, #ARGV, 2 do
+	redis.call("ZADD", KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call("ZREMRANGEBYRANK", KEYS[1], 1, -tonumber(ARGV[2]) - 1)
+return 1
+`)
+
+// timelineArgs arma los argumentos de los scripts para los tweets indicados
+func (r *redisHomeTimelineRepository) timelineArgs(tweets []domain.Tweet) []interface{} {
+	args := make([]interface{}, 0, 2+2*len(tweets))
+	args = append(args, homeTimelineSeededMember, r.maxLength)
+	for _, tweet := range tweets {
+		args = append(args, tweet.CreatedAt.UnixMilli(), tweet.ID)
+	}
+	return args
+}
+
+func (r *redisHomeTimelineRepository) AddTweets(ctx context.Context, username string, tweets []domain.Tweet) error {
+	if len(tweets) == 0 {
+		return nil
+	}
+
+	return addIfSeededScript.Run(ctx, r.client, []string{homeTimelineKey(username)}, r.timelineArgs(tweets)...).Err()
+}
+
+func (r *redisHomeTimelineRepository) SeedTweets(ctx context.Context, username string, tweets []domain.Tweet) error {
+	return seedScript.Run(ctx, r.client, []string{homeTimelineKey(username)}, r.timelineArgs(tweets)...).Err()
+}
+
+func (r *redisHomeTimelineRepository) RemoveTweet(ctx context.Context, usernames []string, tweetID string) error {
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, username := range usernames {
+			pipe.ZRem(ctx, homeTimelineKey(username), tweetID)
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *redisHomeTimelineRepository) RemoveTweets(ctx context.Context, username string, tweetIDs []string) error {
+	if len(tweetIDs) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(tweetIDs))
+	for i, id := range tweetIDs {
+		members[i] = id
+	}
+	return r.client.ZRem(ctx, homeTimelineKey(username), members...).Err()
+}
@@ -0,0 +1,190 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain/ports"
+	"github.com/nicodelara/microblogging-uala/pkg/logger"
+)
+
+const (
+	// timelineStreamChannelPrefix es el prefijo del canal de pub/sub de cada timeline
+	timelineStreamChannelPrefix = "stream:timeline:"
+	// authorStreamChannelPrefix es el prefijo del canal de pub/sub de cada celebridad
+	authorStreamChannelPrefix = "stream:author:"
+)
+
+// RedisStreamBroker distribuye los avisos de tweets nuevos con pub/sub de
+// Redis. Cada instancia usa una única conexión de pub/sub y se suscribe a un
+// canal mientras tenga algún stream abierto que lo necesite, por lo que un
+// aviso llega a los streams de todas las instancias del servicio.
+type RedisStreamBroker struct {
+	client *redis.Client
+	pubsub *redis.PubSub
+
+	mu          sync.Mutex
+	subscribers map[string]map[*redisStreamSubscription]struct{}
+}
+
+// NewRedisStreamBroker crea el broker y empieza a recibir los avisos de los
+// canales suscritos hasta que se llama a Close
+func NewRedisStreamBroker(client *redis.Client) *RedisStreamBroker {
+	b := &RedisStreamBroker{
+		client:      client,
+		pubsub:      client.Subscribe(context.Background()),
+		subscribers: make(map[string]map[*redisStreamSubscription]struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func timelineStreamChannel(username string) string {
+	return timelineStreamChannelPrefix + username
+}
+
+func authorStreamChannel(username string) string {
+	return authorStreamChannelPrefix + username
+}
+
+func (b *RedisStreamBroker) PublishToTimelines(ctx context.Context, usernames []string, entry domain.StreamEntry) error {
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, username := range usernames {
+			pipe.Publish(ctx, timelineStreamChannel(username), data)
+		}
+		return nil
+	})
+	return err
+}
+
+func (b *RedisStreamBroker) PublishFromAuthor(ctx context.Context, author string, entry domain.StreamEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, authorStreamChannel(author), data).Err()
+}
+
+func (b *RedisStreamBroker) Subscribe(ctx context.Context, username string, celebrities []string, buffer int) (ports.StreamSubscription, error) {
+	channels := make([]string, 0, 1+len(celebrities))
+	channels = append(channels, timelineStreamChannel(username))
+	for _, celebrity := range celebrities {
+		channels = append(channels, authorStreamChannel(celebrity))
+	}
+
+	sub := &redisStreamSubscription{
+		broker:   b,
+		channels: channels,
+		entries:  make(chan domain.StreamEntry, buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Sólo se envía SUBSCRIBE para los canales que no tenían streams
+	var added []string
+	for _, channel := range channels {
+		if b.subscribers[channel] == nil {
+			b.subscribers[channel] = make(map[*redisStreamSubscription]struct{})
+			added = append(added, channel)
+		}
+		b.subscribers[channel][sub] = struct{}{}
+	}
+	if len(added) > 0 {
+		if err := b.pubsub.Subscribe(ctx, added...); err != nil {
+			b.removeLocked(sub, nil)
+			return nil, err
+		}
+	}
+
+	return sub, nil
+}
+
+// Close cierra la conexión de pub/sub
+func (b *RedisStreamBroker) Close() error {
+	return b.pubsub.Close()
+}
+
+// run reparte cada aviso recibido entre los streams del canal sin bloquearse:
+// un stream con el buffer lleno se cierra y los demás siguen recibiendo
+func (b *RedisStreamBroker) run() {
+	for msg := range b.pubsub.Channel() {
+		var entry domain.StreamEntry
+		if err := json.Unmarshal([]byte(msg.Payload), &entry); err != nil {
+			logger.Error("invalid stream entry on " + msg.Channel + ": " + err.Error())
+			continue
+		}
+
+		b.mu.Lock()
+		for sub := range b.subscribers[msg.Channel] {
+			select {
+			case sub.entries <- entry:
+			default:
+				b.removeLocked(sub, domain.ErrStreamOverflow)
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// removeLocked da de baja una suscripción y cierra su canal. Los canales que
+// se quedan sin streams se desuscriben. Requiere tener tomado mu.
+func (b *RedisStreamBroker) removeLocked(sub *redisStreamSubscription, err error) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	sub.err = err
+	close(sub.entries)
+
+	var removed []string
+	for _, channel := range sub.channels {
+		delete(b.subscribers[channel], sub)
+		if len(b.subscribers[channel]) == 0 {
+			delete(b.subscribers, channel)
+			removed = append(removed, channel)
+		}
+	}
+	if len(removed) > 0 {
+		if err := b.pubsub.Unsubscribe(context.Background(), removed...); err != nil {
+			logger.Error("error unsubscribing stream channels: " + err.Error())
+		}
+	}
+}
+
+// redisStreamSubscription es un stream suscrito a un conjunto de canales. Los
+// campos closed y err se protegen con el mutex del broker.
+type redisStreamSubscription struct {
+	broker   *RedisStreamBroker
+	channels []string
+	entries  chan domain.StreamEntry
+	closed   bool
+	err      error
+}
+
+func (s *redisStreamSubscription) Entries() <-chan domain.StreamEntry {
+	return s.entries
+}
+
+func (s *redisStreamSubscription) Err() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.err
+}
+
+func (s *redisStreamSubscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.removeLocked(s, nil)
+}
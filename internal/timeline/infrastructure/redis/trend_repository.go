@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/nicodelara/microblogging-uala/internal/timeline/domain"
+)
+
+const (
+	// trendsKeyPrefix es el prefijo de los sorted sets de conteo de hashtags,
+	// uno por ventana e intervalo: trends:<ventana>:<inicio del intervalo>
+	trendsKeyPrefix = "trends:"
+
+	// trendsCountedKeyPrefix marca los tweets cuyos hashtags ya se contaron
+	trendsCountedKeyPrefix = "trends:counted:"
+
+	// trendsTopTTL es cuánto se conserva el resultado combinado de una ventana
+	trendsTopTTL = time.Minute
+)
+
+// uncountScript pasa la marca de un tweet contado a descontado, conservando su
+// expiración. Devuelve 1 solo la primera vez, para que el tweet se descuente
+// una única vez; la marca sigue existiendo y el tweet no vuelve a contarse.
+var uncountScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= "1" then
+	return 0
+end
+redis.call("SET", KEYS[1], "0", "KEEPTTL")
+return 1
+`)
+
+// redisTrendRepository cuenta las apariciones de cada hashtag en sorted sets
+// por intervalo. Las tendencias de una ventana se obtienen combinando sus
+// intervalos con ZUNIONSTORE, ponderando cada uno según su antigüedad.
+type redisTrendRepository struct {
+	client  *redis.Client
+	windows []domain.TrendWindow
+}
+
+// NewRedisTrendRepository crea una nueva instancia del repositorio de
+// tendencias que cuenta los hashtags en cada una de las ventanas indicadas
+func NewRedisTrendRepository(client *redis.Client, windows []domain.TrendWindow) *redisTrendRepository {
+	return &redisTrendRepository{
+		client:  client,
+		windows: windows,
+	}
+}
+
+func trendsBucketKey(window domain.TrendWindow, bucketStart time.Time) string {
+	return trendsKeyPrefix + window.Name + ":" + strconv.FormatInt(bucketStart.Unix(), 10)
+}
+
+func (r *redisTrendRepository) RecordHashtags(ctx context.Context, tweetID string, tags []string, at time.Time) error {
+	if len(tags) == 0 || len(r.windows) == 0 {
+		return nil
+	}
+
+	// La marca dura lo mismo que la ventana más larga: pasado ese tiempo el
+	// tweet ya no suma en ninguna
+	var longest time.Duration
+	for _, window := range r.windows {
+		longest = max(longest, window.Duration())
+	}
+	first, err := r.client.SetNX(ctx, trendsCountedKeyPrefix+tweetID, 1, longest).Result()
+	if err != nil {
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, window := range r.windows {
+			key := trendsBucketKey(window, window.BucketStart(at))
+			for _, tag := range tags {
+				pipe.ZIncrBy(ctx, key, 1, tag)
+			}
+			// El intervalo expira cuando sale de la ventana
+			pipe.ExpireAt(ctx, key, window.BucketStart(at).Add(window.Duration()+window.Bucket))
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *redisTrendRepository) RemoveHashtags(ctx context.Context, tweetID string, tags []string, at time.Time) error {
+	if len(tags) == 0 || len(r.windows) == 0 {
+		return nil
+	}
+
+	counted, err := uncountScript.Run(ctx, r.client, []string{trendsCountedKeyPrefix + tweetID}).Int()
+	if err != nil {
+		return err
+	}
+	if counted == 0 {
+		return nil
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, window := range r.windows {
+			key := trendsBucketKey(window, window.BucketStart(at))
+			for _, tag := range tags {
+				pipe.ZIncrBy(ctx, key, -1, tag)
+			}
+			// Los hashtags que quedan en cero dejan de ser tendencia; si el
+			// intervalo ya había expirado, el sorted set vuelve a quedar vacío
+			pipe.ZRemRangeByScore(ctx, key, "-inf", "0")
+		}
+		return nil
+	})
+	return err
+}
+
+func (r *redisTrendRepository) TopHashtags(ctx context.Context, window domain.TrendWindow, now time.Time, limit int) ([]domain.Trend, error) {
+	current := window.BucketStart(now)
+	keys := make([]string, window.Buckets)
+	weights := make([]float64, window.Buckets)
+	for age := 0; age < window.Buckets; age++ {
+		keys[age] = trendsBucketKey(window, current.Add(-window.Bucket*time.Duration(age)))
+		weights[age] = window.Weight(age)
+	}
+
+	dest := trendsKeyPrefix + window.Name + ":top"
+	var top *redis.ZSliceCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZUnionStore(ctx, dest, &redis.ZStore{Keys: keys, Weights: weights})
+		pipe.Expire(ctx, dest, trendsTopTTL)
+		top = pipe.ZRevRangeWithScores(ctx, dest, 0, int64(limit-1))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trends := make([]domain.Trend, 0, len(top.Val()))
+	for _, z := range top.Val() {
+		tag, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		trends = append(trends, domain.Trend{Hashtag: tag, Score: z.Score})
+	}
+	return trends, nil
+}
@@ -5,4 +5,16 @@ import "errors"
 var (
 	ErrUserNotFound   = errors.New("user does not exist")
 	ErrContentTooLong = errors.New("content exceeds 280 characters")
+	ErrTweetNotFound  = errors.New("tweet not found")
+	ErrParentNotFound = errors.New("tweet being replied to not found")
+	ErrNotTweetAuthor = errors.New("only the author can delete this tweet")
+
+	ErrQuotedNotFound   = errors.New("quoted tweet not found")
+	ErrAlreadyRetweeted = errors.New("tweet already retweeted")
+	ErrNotRetweeted     = errors.New("tweet not retweeted")
+
+	ErrAlreadyLiked = errors.New("tweet already liked")
+	ErrNotLiked     = errors.New("tweet not liked")
+
+	ErrEmptySearchQuery = errors.New("search query is required")
 )
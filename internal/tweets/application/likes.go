@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
+)
+
+type likeService struct {
+	likes     ports.LikeRepository
+	tweets    ports.TweetRepository
+	checker   common.UserChecker
+	tx        outbox.Transactor
+	publisher events.Publisher
+}
+
+// NewLikeService crea el servicio de likes. Al igual que en NewTweetService,
+// publisher debe escribir en el outbox de la misma transacción.
+func NewLikeService(likes ports.LikeRepository, tweets ports.TweetRepository, checker common.UserChecker, tx outbox.Transactor, publisher events.Publisher) ports.LikeService {
+	return &likeService{
+		likes:     likes,
+		tweets:    tweets,
+		checker:   checker,
+		tx:        tx,
+		publisher: publisher,
+	}
+}
+
+func (s *likeService) LikeTweet(ctx context.Context, id, username string) (*domain.Like, error) {
+	// Verificar que el usuario exista
+	_, err := s.checker.GetUser(username)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	tweet, err := findOriginal(ctx, s.tweets, id)
+	if err != nil {
+		return nil, err
+	}
+	if tweet == nil {
+		return nil, ErrTweetNotFound
+	}
+
+	like := domain.NewLike(username, tweet.ID)
+	evt, err := events.New(events.TopicTweets, events.TweetLiked, events.TweetLikedVersion, like.Username, events.TweetLikedPayload{
+		LikeID:    like.ID,
+		TweetID:   like.TweetID,
+		Username:  like.Username,
+		Author:    tweet.Username,
+		CreatedAt: like.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// El índice único de likes evita contar dos veces el mismo like ante
+	// requests concurrentes, y el contador se actualiza con $inc en la misma
+	// transacción
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err := s.likes.SaveLike(ctx, like)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			return ErrAlreadyLiked
+		}
+		if err := s.tweets.IncrementLikeCount(ctx, tweet.ID, 1); err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return like, nil
+}
+
+func (s *likeService) UnlikeTweet(ctx context.Context, id, username string) error {
+	// El tweet no necesita estar vigente para poder quitar el like
+	tweet, err := s.tweets.GetTweet(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tweet != nil && tweet.IsRetweet() {
+		tweet, err = s.tweets.GetTweet(ctx, tweet.RetweetOf)
+		if err != nil {
+			return err
+		}
+	}
+	if tweet == nil {
+		return ErrNotLiked
+	}
+
+	evt, err := events.New(events.TopicTweets, events.TweetUnliked, events.TweetUnlikedVersion, username, events.TweetUnlikedPayload{
+		TweetID:   tweet.ID,
+		Username:  username,
+		Author:    tweet.Username,
+		UnlikedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Eliminar el like, actualizar el contador y publicar su evento de forma atómica
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.likes.DeleteLike(ctx, username, tweet.ID)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrNotLiked
+		}
+		if err := s.tweets.IncrementLikeCount(ctx, tweet.ID, -1); err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+}
+
+func (s *likeService) ListLikers(ctx context.Context, id string, cursor *pagination.Cursor, limit int) (*domain.LikerList, error) {
+	tweet, err := findOriginal(ctx, s.tweets, id)
+	if err != nil {
+		return nil, err
+	}
+	if tweet == nil {
+		return nil, ErrTweetNotFound
+	}
+
+	likes, err := s.likes.ListLikers(ctx, tweet.ID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &domain.LikerList{Users: make([]domain.Liker, 0, len(likes))}
+	for _, like := range likes {
+		list.Users = append(list.Users, domain.Liker{
+			Username: like.Username,
+			LikedAt:  like.CreatedAt,
+		})
+	}
+	list.NextCursor = nextLikeCursor(likes, limit)
+	return list, nil
+}
+
+func (s *likeService) ListUserLikes(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.TweetPage, error) {
+	// Verificar que el usuario exista
+	_, err := s.checker.GetUser(username)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	likes, err := s.likes.ListUserLikes(ctx, username, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(likes))
+	for i, like := range likes {
+		ids[i] = like.TweetID
+	}
+	tweets, err := s.tweets.GetTweetsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los tweets likeados que fueron eliminados se omiten, pero el cursor se
+	// arma con los likes para no cortar la paginación
+	page := domain.NewTweetPage(tweets, 0)
+	page.NextCursor = nextLikeCursor(likes, limit)
+	return page, nil
+}
+
+// nextLikeCursor devuelve el cursor de la página siguiente cuando la página de
+// likes está completa
+func nextLikeCursor(likes []domain.Like, limit int) string {
+	if limit > 0 && len(likes) == limit {
+		return likes[len(likes)-1].Cursor().Encode()
+	}
+	return ""
+}
@@ -0,0 +1,257 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	tweetsdomain "github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	usersdomain "github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLikeRepository struct {
+	mock.Mock
+}
+
+func (m *mockLikeRepository) SaveLike(ctx context.Context, like *tweetsdomain.Like) (bool, error) {
+	args := m.Called(ctx, like)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockLikeRepository) DeleteLike(ctx context.Context, username, tweetID string) (bool, error) {
+	args := m.Called(ctx, username, tweetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockLikeRepository) ListLikers(ctx context.Context, tweetID string, cursor *pagination.Cursor, limit int) ([]tweetsdomain.Like, error) {
+	args := m.Called(ctx, tweetID, cursor, limit)
+	return args.Get(0).([]tweetsdomain.Like), args.Error(1)
+}
+
+func (m *mockLikeRepository) ListUserLikes(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]tweetsdomain.Like, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	return args.Get(0).([]tweetsdomain.Like), args.Error(1)
+}
+
+func TestLikeService_LikeTweet(t *testing.T) {
+	deletedAt := time.Now()
+	tweet := &tweetsdomain.Tweet{ID: "t1", Username: "author", Content: "Hello"}
+
+	tests := []struct {
+		name          string
+		id            string
+		checkerSetup  func(*mockUserChecker)
+		tweetSetup    func(*mockTweetRepository)
+		likeSetup     func(*mockLikeRepository)
+		expectedError error
+	}{
+		{
+			name: "successful like",
+			id:   "t1",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			tweetSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+				m.On("IncrementLikeCount", mock.Anything, "t1", int64(1)).Return(nil)
+			},
+			likeSetup: func(m *mockLikeRepository) {
+				m.On("SaveLike", mock.Anything, mock.Anything).Return(true, nil)
+			},
+		},
+		{
+			name: "liking a retweet likes the original",
+			id:   "rt",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			tweetSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "rt").Return(&tweetsdomain.Tweet{ID: "rt", Username: "other", RetweetOf: "t1"}, nil)
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+				m.On("IncrementLikeCount", mock.Anything, "t1", int64(1)).Return(nil)
+			},
+			likeSetup: func(m *mockLikeRepository) {
+				m.On("SaveLike", mock.Anything, mock.Anything).Return(true, nil)
+			},
+		},
+		{
+			name: "already liked",
+			id:   "t1",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			tweetSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+			},
+			likeSetup: func(m *mockLikeRepository) {
+				m.On("SaveLike", mock.Anything, mock.Anything).Return(false, nil)
+			},
+			expectedError: ErrAlreadyLiked,
+		},
+		{
+			name: "tweet deleted",
+			id:   "t1",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			tweetSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(&tweetsdomain.Tweet{ID: "t1", DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: ErrTweetNotFound,
+		},
+		{
+			name: "user does not exist",
+			id:   "t1",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(nil, errors.New("user does not exist"))
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := new(mockUserChecker)
+			tweets := new(mockTweetRepository)
+			likes := new(mockLikeRepository)
+			tt.checkerSetup(checker)
+			if tt.tweetSetup != nil {
+				tt.tweetSetup(tweets)
+			}
+			if tt.likeSetup != nil {
+				tt.likeSetup(likes)
+			}
+
+			publisher := events.NewMemoryPublisher()
+
+			service := NewLikeService(likes, tweets, checker, outbox.NopTransactor{}, publisher)
+			like, err := service.LikeTweet(context.Background(), tt.id, "fan")
+
+			if tt.expectedError != nil {
+				assert.Nil(t, like)
+				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, publisher.Events())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "fan", like.Username)
+				assert.Equal(t, "t1", like.TweetID)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.TweetLiked, published[0].Type)
+
+				var payload events.TweetLikedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, "t1", payload.TweetID)
+				assert.Equal(t, "author", payload.Author)
+			}
+			checker.AssertExpectations(t)
+			tweets.AssertExpectations(t)
+			likes.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLikeService_UnlikeTweet(t *testing.T) {
+	tweet := &tweetsdomain.Tweet{ID: "t1", Username: "author", Content: "Hello"}
+
+	tests := []struct {
+		name          string
+		tweetSetup    func(*mockTweetRepository)
+		likeSetup     func(*mockLikeRepository)
+		expectedError error
+	}{
+		{
+			name: "successful unlike",
+			tweetSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+				m.On("IncrementLikeCount", mock.Anything, "t1", int64(-1)).Return(nil)
+			},
+			likeSetup: func(m *mockLikeRepository) {
+				m.On("DeleteLike", mock.Anything, "fan", "t1").Return(true, nil)
+			},
+		},
+		{
+			name: "not liked",
+			tweetSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+			},
+			likeSetup: func(m *mockLikeRepository) {
+				m.On("DeleteLike", mock.Anything, "fan", "t1").Return(false, nil)
+			},
+			expectedError: ErrNotLiked,
+		},
+		{
+			name: "tweet does not exist",
+			tweetSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(nil, nil)
+			},
+			expectedError: ErrNotLiked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tweets := new(mockTweetRepository)
+			likes := new(mockLikeRepository)
+			tt.tweetSetup(tweets)
+			if tt.likeSetup != nil {
+				tt.likeSetup(likes)
+			}
+
+			publisher := events.NewMemoryPublisher()
+
+			service := NewLikeService(likes, tweets, new(mockUserChecker), outbox.NopTransactor{}, publisher)
+			err := service.UnlikeTweet(context.Background(), "t1", "fan")
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, publisher.Events())
+			} else {
+				assert.NoError(t, err)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.TweetUnliked, published[0].Type)
+			}
+			tweets.AssertExpectations(t)
+			likes.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLikeService_ListUserLikes(t *testing.T) {
+	now := time.Now()
+	likes := []tweetsdomain.Like{
+		{ID: "l2", Username: "fan", TweetID: "t2", CreatedAt: now},
+		{ID: "l1", Username: "fan", TweetID: "t1", CreatedAt: now.Add(-time.Minute)},
+	}
+
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+
+	likeRepo := new(mockLikeRepository)
+	likeRepo.On("ListUserLikes", mock.Anything, "fan", (*pagination.Cursor)(nil), 2).Return(likes, nil)
+
+	// t2 fue eliminado y no se devuelve
+	tweetRepo := new(mockTweetRepository)
+	tweetRepo.On("GetTweetsByIDs", mock.Anything, []string{"t2", "t1"}).Return([]tweetsdomain.Tweet{
+		{ID: "t1", Username: "author", Content: "Tweet 1"},
+	}, nil)
+
+	service := NewLikeService(likeRepo, tweetRepo, checker, outbox.NopTransactor{}, events.NewMemoryPublisher())
+	page, err := service.ListUserLikes(context.Background(), "fan", nil, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Tweets, 1)
+	assert.Equal(t, "t1", page.Tweets[0].ID)
+	assert.Equal(t, likes[1].Cursor().Encode(), page.NextCursor)
+	likeRepo.AssertExpectations(t)
+	tweetRepo.AssertExpectations(t)
+}
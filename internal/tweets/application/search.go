@@ -0,0 +1,34 @@
+package application
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
+)
+
+type searchService struct {
+	searcher ports.TweetSearcher
+}
+
+// NewSearchService crea el servicio de búsqueda de tweets sobre el buscador
+// indicado
+func NewSearchService(searcher ports.TweetSearcher) ports.SearchService {
+	return &searchService{searcher: searcher}
+}
+
+func (s *searchService) SearchTweets(ctx context.Context, raw string, cursor *domain.SearchCursor, limit int) (*domain.SearchPage, error) {
+	query, err := domain.ParseSearchQuery(raw)
+	if err != nil {
+		return nil, err
+	}
+	if query.IsEmpty() {
+		return nil, ErrEmptySearchQuery
+	}
+
+	hits, err := s.searcher.SearchTweets(ctx, query, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewSearchPage(hits, limit), nil
+}
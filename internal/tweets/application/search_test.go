@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchService_SearchTweets(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	deletedAt := now
+
+	index := memory.NewInvertedIndex()
+	for _, tweet := range []domain.Tweet{
+		{ID: "1", Username: "ana", Content: "Go go go: el cache distribuido en Go", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "2", Username: "beto", Content: "Un cache distribuido con Redis #golang", Hashtags: []string{"golang"}, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "3", Username: "ana", Content: "Distribuido el cache, no es lo mismo #GoLang", Hashtags: []string{"golang"}, CreatedAt: now.Add(-time.Hour)},
+		{ID: "4", Username: "ana", Content: "Viejo tweet sobre go", CreatedAt: now.Add(-5 * 24 * time.Hour)},
+		{ID: "5", Username: "ana", Content: "Eliminado sobre go", CreatedAt: now, DeletedAt: &deletedAt},
+		{ID: "6", Username: "beto", RetweetOf: "1", CreatedAt: now},
+	} {
+		index.Index(tweet)
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		limit         int
+		expectedIDs   []string
+		expectedError error
+	}{
+		{
+			name:        "keywords are ranked by relevance and then by date",
+			query:       "go",
+			limit:       10,
+			expectedIDs: []string{"1", "4"},
+		},
+		{
+			name:        "any keyword matches and ties are sorted by date",
+			query:       "redis viejo",
+			limit:       10,
+			expectedIDs: []string{"2", "4"},
+		},
+		{
+			name:        "phrase must appear in order",
+			query:       `"cache distribuido"`,
+			limit:       10,
+			expectedIDs: []string{"2", "1"},
+		},
+		{
+			name:        "from and hashtag filters",
+			query:       "from:ana #golang",
+			limit:       10,
+			expectedIDs: []string{"3"},
+		},
+		{
+			name:        "date range without keywords is sorted by date",
+			query:       "since:2024-01-10 until:2024-01-11",
+			limit:       10,
+			expectedIDs: []string{"3", "2", "1"},
+		},
+		{
+			name:          "empty query",
+			query:         "  ",
+			limit:         10,
+			expectedError: ErrEmptySearchQuery,
+		},
+		{
+			name:          "invalid date",
+			query:         "go until:mañana",
+			limit:         10,
+			expectedError: domain.ErrInvalidSearchDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewSearchService(index)
+			page, err := service.SearchTweets(context.Background(), tt.query, nil, tt.limit)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, page)
+				return
+			}
+
+			assert.NoError(t, err)
+			ids := make([]string, 0, len(page.Tweets))
+			for _, tweet := range page.Tweets {
+				ids = append(ids, tweet.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Empty(t, page.NextCursor)
+		})
+	}
+}
+
+func TestSearchService_SearchTweets_Pagination(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	index := memory.NewInvertedIndex()
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		index.Index(domain.Tweet{ID: id, Username: "ana", Content: "hola", CreatedAt: now.Add(time.Duration(i%2) * time.Minute)})
+	}
+	service := NewSearchService(index)
+
+	var ids []string
+	var cursor *domain.SearchCursor
+	for pages := 0; pages < 5; pages++ {
+		page, err := service.SearchTweets(context.Background(), "hola", cursor, 2)
+		assert.NoError(t, err)
+		for _, tweet := range page.Tweets {
+			ids = append(ids, tweet.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor, err = domain.ParseSearchCursor(page.NextCursor)
+		assert.NoError(t, err)
+	}
+
+	// Las fechas iguales se desempatan por id descendente
+	assert.Equal(t, []string{"d", "b", "e", "c", "a"}, ids)
+}
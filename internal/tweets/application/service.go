@@ -2,37 +2,331 @@ package application
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
 	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
 	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
 )
 
+// maxThreadSize es la cantidad máxima de tweets de una conversación que se
+// devuelven al armar su árbol
+const maxThreadSize = 1000
+
 type tweetService struct {
-	repo    ports.TweetRepository
-	checker common.UserChecker
+	repo      ports.TweetRepository
+	checker   common.UserChecker
+	tx        outbox.Transactor
+	publisher events.Publisher
 }
 
-func NewTweetService(repo ports.TweetRepository, checker common.UserChecker) ports.TweetService {
+// NewTweetService crea el servicio de tweets. publisher debe escribir en el
+// outbox para que los eventos se persistan en la misma transacción que el tweet.
+func NewTweetService(repo ports.TweetRepository, checker common.UserChecker, tx outbox.Transactor, publisher events.Publisher) ports.TweetService {
 	return &tweetService{
-		repo:    repo,
-		checker: checker,
+		repo:      repo,
+		checker:   checker,
+		tx:        tx,
+		publisher: publisher,
 	}
 }
 
-func (s *tweetService) CreateTweet(ctx context.Context, username, content string) (*domain.Tweet, error) {
+func (s *tweetService) CreateTweet(ctx context.Context, draft domain.TweetDraft) (*domain.Tweet, error) {
+	// Verificar que el usuario exista
+	_, err := s.checker.GetUser(draft.Username)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	tweet := domain.NewTweet(draft.Username, draft.Content)
+	if draft.InReplyToTweetID != "" {
+		// Verificar que el tweet respondido exista y no esté eliminado
+		parent, err := s.findOriginal(ctx, draft.InReplyToTweetID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, ErrParentNotFound
+		}
+		tweet = domain.NewReply(draft.Username, draft.Content, parent)
+	}
+	if draft.QuotedTweetID != "" {
+		quoted, err := s.findOriginal(ctx, draft.QuotedTweetID)
+		if err != nil {
+			return nil, err
+		}
+		if quoted == nil {
+			return nil, ErrQuotedNotFound
+		}
+		tweet.QuotedTweetID = quoted.ID
+	}
+	tweet.Entities, err = s.extractEntities(tweet.Content)
+	if err != nil {
+		return nil, err
+	}
+	if tweet.Entities != nil {
+		tweet.Hashtags = tweet.Entities.HashtagTexts()
+	}
+
+	evt, err := newTweetCreatedEvent(tweet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Guardar el tweet, el contador del tweet respondido y el evento de forma atómica
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.SaveTweet(ctx, tweet); err != nil {
+			return err
+		}
+		if tweet.IsReply() {
+			if err := s.repo.IncrementReplyCount(ctx, tweet.InReplyToTweetID, 1); err != nil {
+				return err
+			}
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tweet, nil
+}
+
+func (s *tweetService) findOriginal(ctx context.Context, id string) (*domain.Tweet, error) {
+	return findOriginal(ctx, s.repo, id)
+}
+
+// findOriginal obtiene un tweet vigente. Si es un retweet devuelve el tweet
+// original, que es al que apuntan respuestas, citas, retweets y likes.
+// Devuelve nil si no existe o fue eliminado.
+func findOriginal(ctx context.Context, repo ports.TweetRepository, id string) (*domain.Tweet, error) {
+	tweet, err := repo.GetTweet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tweet != nil && tweet.IsRetweet() {
+		tweet, err = repo.GetTweet(ctx, tweet.RetweetOf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if tweet == nil || tweet.IsDeleted() {
+		return nil, nil
+	}
+	return tweet, nil
+}
+
+// extractEntities reconoce las entidades del contenido y descarta las
+// menciones a usuarios que no existen. Devuelve nil si no hay ninguna. Un
+// error al consultar un usuario se devuelve en lugar de descartar la mención.
+func (s *tweetService) extractEntities(content string) (*domain.Entities, error) {
+	entities := domain.ExtractEntities(content)
+
+	exists := make(map[string]bool)
+	for _, username := range entities.MentionedUsernames() {
+		_, err := s.checker.GetUser(username)
+		if err != nil && !errors.Is(err, common.ErrUserNotFound) {
+			return nil, err
+		}
+		exists[username] = err == nil
+	}
+	mentions := entities.Mentions[:0]
+	for _, mention := range entities.Mentions {
+		if exists[mention.Username] {
+			mentions = append(mentions, mention)
+		}
+	}
+	entities.Mentions = mentions
+
+	if entities.IsEmpty() {
+		return nil, nil
+	}
+	return &entities, nil
+}
+
+func newTweetCreatedEvent(tweet *domain.Tweet) (events.Event, error) {
+	payload := events.TweetCreatedPayload{
+		TweetID:           tweet.ID,
+		Username:          tweet.Username,
+		Content:           tweet.Content,
+		CreatedAt:         tweet.CreatedAt,
+		InReplyToTweetID:  tweet.InReplyToTweetID,
+		InReplyToUsername: tweet.InReplyToUsername,
+		ConversationID:    tweet.ConversationID,
+		RetweetOf:         tweet.RetweetOf,
+		QuotedTweetID:     tweet.QuotedTweetID,
+	}
+	if tweet.Entities != nil {
+		payload.Hashtags = tweet.Hashtags
+		payload.Mentions = tweet.Entities.MentionedUsernames()
+	}
+	return events.New(events.TopicTweets, events.TweetCreated, events.TweetCreatedVersion, tweet.Username, payload)
+}
+
+func newTweetDeletedEvent(tweet *domain.Tweet, deletedAt time.Time) (events.Event, error) {
+	return events.New(events.TopicTweets, events.TweetDeleted, events.TweetDeletedVersion, tweet.Username, events.TweetDeletedPayload{
+		TweetID:   tweet.ID,
+		Username:  tweet.Username,
+		DeletedAt: deletedAt,
+		CreatedAt: tweet.CreatedAt,
+		Hashtags:  tweet.Hashtags,
+	})
+}
+
+func (s *tweetService) Retweet(ctx context.Context, id, username string) (*domain.Tweet, error) {
 	// Verificar que el usuario exista
 	_, err := s.checker.GetUser(username)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 
-	tweet := domain.NewTweet(username, content)
+	original, err := s.findOriginal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, ErrTweetNotFound
+	}
 
-	err = s.repo.SaveTweet(ctx, tweet)
+	retweet := domain.NewRetweet(username, original)
+	evt, err := newTweetCreatedEvent(retweet)
 	if err != nil {
 		return nil, err
 	}
 
+	// El índice único de retweets garantiza que un usuario retweetee un tweet
+	// una sola vez, incluso ante requests concurrentes
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		saved, err := s.repo.SaveRetweet(ctx, retweet)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			return ErrAlreadyRetweeted
+		}
+		if err := s.repo.IncrementRetweetCount(ctx, original.ID, 1); err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return retweet, nil
+}
+
+func (s *tweetService) UndoRetweet(ctx context.Context, id, username string) error {
+	// id puede ser el del tweet original o el del propio retweet. El original
+	// no necesita estar vigente para poder deshacer el retweet.
+	originalID := id
+	tweet, err := s.repo.GetTweet(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tweet != nil && tweet.IsRetweet() {
+		originalID = tweet.RetweetOf
+	}
+
+	// Eliminar el retweet, actualizar el contador y publicar su evento de forma atómica
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		retweet, err := s.repo.DeleteRetweet(ctx, username, originalID)
+		if err != nil {
+			return err
+		}
+		if retweet == nil {
+			return ErrNotRetweeted
+		}
+		if err := s.repo.IncrementRetweetCount(ctx, originalID, -1); err != nil {
+			return err
+		}
+
+		evt, err := newTweetDeletedEvent(retweet, time.Now())
+		if err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+}
+
+func (s *tweetService) GetTweet(ctx context.Context, id string) (*domain.Tweet, error) {
+	tweet, err := s.repo.GetTweet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tweet == nil || tweet.IsDeleted() {
+		return nil, ErrTweetNotFound
+	}
 	return tweet, nil
 }
+
+func (s *tweetService) GetThread(ctx context.Context, id string) (*domain.Thread, error) {
+	tweet, err := s.GetTweet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, err := s.repo.GetConversation(ctx, tweet.RootID(), maxThreadSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BuildThread(tweet.RootID(), conversation), nil
+}
+
+func (s *tweetService) ListUserTweets(ctx context.Context, username string, opts domain.TweetListOptions) (*domain.TweetPage, error) {
+	// Verificar que el usuario exista
+	_, err := s.checker.GetUser(username)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	tweets, err := s.repo.ListUserTweets(ctx, username, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewTweetPage(tweets, opts.Limit), nil
+}
+
+func (s *tweetService) DeleteTweet(ctx context.Context, id, username string) error {
+	tweet, err := s.GetTweet(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tweet.Username != username {
+		return ErrNotTweetAuthor
+	}
+
+	// Los retweets se eliminan físicamente para que el usuario pueda volver a
+	// retweetear el mismo tweet
+	if tweet.IsRetweet() {
+		return s.UndoRetweet(ctx, tweet.RetweetOf, username)
+	}
+
+	deletedAt := time.Now()
+	evt, err := newTweetDeletedEvent(tweet, deletedAt)
+	if err != nil {
+		return err
+	}
+
+	// Marcar el tweet como eliminado y publicar su evento de forma atómica
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.repo.DeleteTweet(ctx, tweet.ID, deletedAt)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrTweetNotFound
+		}
+		if tweet.IsReply() {
+			if err := s.repo.IncrementReplyCount(ctx, tweet.InReplyToTweetID, -1); err != nil {
+				return err
+			}
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/nicodelara/microblogging-uala/internal/common"
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	tweetsdomain "github.com/nicodelara/microblogging-uala/internal/tweets/domain"
 	usersdomain "github.com/nicodelara/microblogging-uala/internal/users/domain"
 	"github.com/stretchr/testify/assert"
@@ -20,6 +25,62 @@ func (m *mockTweetRepository) SaveTweet(ctx context.Context, tweet *tweetsdomain
 	return args.Error(0)
 }
 
+func (m *mockTweetRepository) GetTweet(ctx context.Context, id string) (*tweetsdomain.Tweet, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tweetsdomain.Tweet), args.Error(1)
+}
+
+func (m *mockTweetRepository) DeleteTweet(ctx context.Context, id string, deletedAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockTweetRepository) ListUserTweets(ctx context.Context, username string, opts tweetsdomain.TweetListOptions) ([]tweetsdomain.Tweet, error) {
+	args := m.Called(ctx, username, opts)
+	return args.Get(0).([]tweetsdomain.Tweet), args.Error(1)
+}
+
+func (m *mockTweetRepository) GetConversation(ctx context.Context, conversationID string, limit int) ([]tweetsdomain.Tweet, error) {
+	args := m.Called(ctx, conversationID, limit)
+	return args.Get(0).([]tweetsdomain.Tweet), args.Error(1)
+}
+
+func (m *mockTweetRepository) IncrementReplyCount(ctx context.Context, id string, delta int64) error {
+	args := m.Called(ctx, id, delta)
+	return args.Error(0)
+}
+
+func (m *mockTweetRepository) SaveRetweet(ctx context.Context, retweet *tweetsdomain.Tweet) (bool, error) {
+	args := m.Called(ctx, retweet)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockTweetRepository) DeleteRetweet(ctx context.Context, username, originalID string) (*tweetsdomain.Tweet, error) {
+	args := m.Called(ctx, username, originalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tweetsdomain.Tweet), args.Error(1)
+}
+
+func (m *mockTweetRepository) IncrementRetweetCount(ctx context.Context, id string, delta int64) error {
+	args := m.Called(ctx, id, delta)
+	return args.Error(0)
+}
+
+func (m *mockTweetRepository) IncrementLikeCount(ctx context.Context, id string, delta int64) error {
+	args := m.Called(ctx, id, delta)
+	return args.Error(0)
+}
+
+func (m *mockTweetRepository) GetTweetsByIDs(ctx context.Context, ids []string) ([]tweetsdomain.Tweet, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]tweetsdomain.Tweet), args.Error(1)
+}
+
 type mockUserChecker struct {
 	mock.Mock
 }
@@ -37,14 +98,32 @@ func (m *mockUserChecker) GetFollowings(ctx context.Context, username string) ([
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *mockUserChecker) GetFollowers(ctx context.Context, username string) ([]string, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockUserChecker) CountFollowers(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestTweetService_CreateTweet(t *testing.T) {
+	deletedAt := time.Now()
+	parent := &tweetsdomain.Tweet{ID: "parent", Username: "other", ConversationID: "root"}
+
 	tests := []struct {
-		name          string
-		username      string
-		content       string
-		checkerSetup  func(*mockUserChecker)
-		repoSetup     func(*mockTweetRepository)
-		expectedError error
+		name             string
+		username         string
+		content          string
+		inReplyTo        string
+		quoted           string
+		expectedQuoted   string
+		expectedConvRoot string
+		checkerSetup     func(*mockUserChecker)
+		repoSetup        func(*mockTweetRepository)
+		publishErr       error
+		expectedError    error
 	}{
 		{
 			name:     "successful creation",
@@ -58,6 +137,77 @@ func TestTweetService_CreateTweet(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:             "reply joins parent conversation",
+			username:         "testuser",
+			content:          "Hello, world!",
+			inReplyTo:        "parent",
+			expectedConvRoot: "root",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "parent").Return(parent, nil)
+				m.On("SaveTweet", mock.Anything, mock.Anything).Return(nil)
+				m.On("IncrementReplyCount", mock.Anything, "parent", int64(1)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "reply to deleted tweet",
+			username:  "testuser",
+			content:   "Hello, world!",
+			inReplyTo: "parent",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "parent").Return(&tweetsdomain.Tweet{ID: "parent", DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: ErrParentNotFound,
+		},
+		{
+			name:      "reply to missing tweet",
+			username:  "testuser",
+			content:   "Hello, world!",
+			inReplyTo: "parent",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "parent").Return(nil, nil)
+			},
+			expectedError: ErrParentNotFound,
+		},
+		{
+			name:           "quote of a retweet cites the original",
+			username:       "testuser",
+			content:        "Hello, world!",
+			quoted:         "rt",
+			expectedQuoted: "original",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "rt").Return(&tweetsdomain.Tweet{ID: "rt", Username: "other", RetweetOf: "original"}, nil)
+				m.On("GetTweet", mock.Anything, "original").Return(&tweetsdomain.Tweet{ID: "original", Username: "author"}, nil)
+				m.On("SaveTweet", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:     "quote of missing tweet",
+			username: "testuser",
+			content:  "Hello, world!",
+			quoted:   "missing",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "missing").Return(nil, nil)
+			},
+			expectedError: ErrQuotedNotFound,
+		},
 		{
 			name:     "user does not exist",
 			username: "nonexistent",
@@ -79,6 +229,19 @@ func TestTweetService_CreateTweet(t *testing.T) {
 			},
 			expectedError: errors.New("database error"),
 		},
+		{
+			name:     "outbox error fails creation",
+			username: "testuser",
+			content:  "Hello, world!",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("SaveTweet", mock.Anything, mock.Anything).Return(nil)
+			},
+			publishErr:    errors.New("outbox write failed"),
+			expectedError: errors.New("outbox write failed"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -90,8 +253,18 @@ func TestTweetService_CreateTweet(t *testing.T) {
 				tt.repoSetup(repo)
 			}
 
-			service := NewTweetService(repo, checker)
-			tweet, err := service.CreateTweet(context.Background(), tt.username, tt.content)
+			publisher := events.NewMemoryPublisher()
+			if tt.publishErr != nil {
+				publisher.FailWith(tt.publishErr)
+			}
+
+			service := NewTweetService(repo, checker, outbox.NopTransactor{}, publisher)
+			tweet, err := service.CreateTweet(context.Background(), tweetsdomain.TweetDraft{
+				Username:         tt.username,
+				Content:          tt.content,
+				InReplyToTweetID: tt.inReplyTo,
+				QuotedTweetID:    tt.quoted,
+			})
 
 			if tt.expectedError != nil {
 				assert.Nil(t, tweet)
@@ -101,9 +274,463 @@ func TestTweetService_CreateTweet(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.username, tweet.Username)
 				assert.Equal(t, tt.content, tweet.Content)
+				assert.Equal(t, tt.inReplyTo, tweet.InReplyToTweetID)
+				assert.Equal(t, tt.expectedQuoted, tweet.QuotedTweetID)
+				if tt.expectedConvRoot != "" {
+					assert.Equal(t, tt.expectedConvRoot, tweet.ConversationID)
+				} else {
+					assert.Equal(t, tweet.ID, tweet.ConversationID)
+				}
+			}
+
+			if tt.expectedError == nil {
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.TweetCreated, published[0].Type)
+				assert.Equal(t, events.TweetCreatedVersion, published[0].Version)
+				assert.Equal(t, tt.username, published[0].Key)
+
+				var payload events.TweetCreatedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, tweet.ID, payload.TweetID)
+				assert.Equal(t, tweet.QuotedTweetID, payload.QuotedTweetID)
+			} else {
+				assert.Empty(t, publisher.Events())
+			}
+			checker.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTweetService_CreateTweet_Entities(t *testing.T) {
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+	checker.On("GetUser", "ana").Return(&usersdomain.User{}, nil)
+	checker.On("GetUser", "ghost").Return(nil, common.ErrUserNotFound)
+
+	repo := new(mockTweetRepository)
+	repo.On("SaveTweet", mock.Anything, mock.Anything).Return(nil)
+
+	publisher := events.NewMemoryPublisher()
+	service := NewTweetService(repo, checker, outbox.NopTransactor{}, publisher)
+	tweet, err := service.CreateTweet(context.Background(), tweetsdomain.TweetDraft{
+		Username: "testuser",
+		Content:  "@ana @ghost #Go",
+	})
+
+	assert.NoError(t, err)
+	// Las menciones a usuarios inexistentes se descartan
+	assert.Equal(t, []tweetsdomain.MentionEntity{{Username: "ana", Start: 0, End: 4}}, tweet.Entities.Mentions)
+	assert.Equal(t, []tweetsdomain.TagEntity{{Text: "Go", Start: 12, End: 15}}, tweet.Entities.Hashtags)
+
+	var payload events.TweetCreatedPayload
+	assert.NoError(t, publisher.Events()[0].Decode(&payload))
+	assert.Equal(t, []string{"go"}, payload.Hashtags)
+	assert.Equal(t, []string{"ana"}, payload.Mentions)
+	checker.AssertExpectations(t)
+}
+
+func TestTweetService_CreateTweet_MentionLookupError(t *testing.T) {
+	lookupErr := errors.New("connection refused")
+	checker := new(mockUserChecker)
+	checker.On("GetUser", "testuser").Return(&usersdomain.User{}, nil)
+	checker.On("GetUser", "ana").Return(nil, lookupErr)
+
+	repo := new(mockTweetRepository)
+	publisher := events.NewMemoryPublisher()
+	service := NewTweetService(repo, checker, outbox.NopTransactor{}, publisher)
+	tweet, err := service.CreateTweet(context.Background(), tweetsdomain.TweetDraft{
+		Username: "testuser",
+		Content:  "hola @ana",
+	})
+
+	// Una falla al consultar el usuario no descarta la mención: el tweet no se crea
+	assert.ErrorIs(t, err, lookupErr)
+	assert.Nil(t, tweet)
+	assert.Empty(t, publisher.Events())
+	repo.AssertNotCalled(t, "SaveTweet", mock.Anything, mock.Anything)
+	checker.AssertExpectations(t)
+}
+
+func TestTweetService_DeleteTweet(t *testing.T) {
+	deletedAt := time.Now()
+	createdAt := deletedAt.Add(-time.Hour)
+	tweet := &tweetsdomain.Tweet{ID: "t1", Username: "author", Content: "Hello #go", CreatedAt: createdAt, Hashtags: []string{"go"}}
+
+	tests := []struct {
+		name          string
+		username      string
+		repoSetup     func(*mockTweetRepository)
+		expectedError error
+	}{
+		{
+			name:     "author deletes tweet",
+			username: "author",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+				m.On("DeleteTweet", mock.Anything, "t1", mock.Anything).Return(true, nil)
+			},
+		},
+		{
+			name:     "tweet does not exist",
+			username: "author",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(nil, nil)
+			},
+			expectedError: ErrTweetNotFound,
+		},
+		{
+			name:     "tweet already deleted",
+			username: "author",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(&tweetsdomain.Tweet{ID: "t1", Username: "author", DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: ErrTweetNotFound,
+		},
+		{
+			name:     "only the author can delete",
+			username: "other",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+			},
+			expectedError: ErrNotTweetAuthor,
+		},
+		{
+			name:     "deleting a retweet undoes it",
+			username: "author",
+			repoSetup: func(m *mockTweetRepository) {
+				retweet := &tweetsdomain.Tweet{ID: "t1", Username: "author", RetweetOf: "original"}
+				m.On("GetTweet", mock.Anything, "t1").Return(retweet, nil)
+				m.On("GetTweet", mock.Anything, "original").Return(&tweetsdomain.Tweet{ID: "original", Username: "other"}, nil)
+				m.On("DeleteRetweet", mock.Anything, "author", "original").Return(retweet, nil)
+				m.On("IncrementRetweetCount", mock.Anything, "original", int64(-1)).Return(nil)
+			},
+		},
+		{
+			name:     "concurrent deletion",
+			username: "author",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "t1").Return(tweet, nil)
+				m.On("DeleteTweet", mock.Anything, "t1", mock.Anything).Return(false, nil)
+			},
+			expectedError: ErrTweetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockTweetRepository)
+			tt.repoSetup(repo)
+
+			publisher := events.NewMemoryPublisher()
+
+			service := NewTweetService(repo, new(mockUserChecker), outbox.NopTransactor{}, publisher)
+			err := service.DeleteTweet(context.Background(), "t1", tt.username)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, publisher.Events())
+			} else {
+				assert.NoError(t, err)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.TweetDeleted, published[0].Type)
+
+				var payload events.TweetDeletedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, "t1", payload.TweetID)
+				assert.Equal(t, "author", payload.Username)
+				if tt.name == "author deletes tweet" {
+					assert.Equal(t, []string{"go"}, payload.Hashtags)
+					assert.True(t, createdAt.Equal(payload.CreatedAt))
+				}
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTweetService_Retweet(t *testing.T) {
+	deletedAt := time.Now()
+	original := &tweetsdomain.Tweet{ID: "original", Username: "author", Content: "Hello"}
+
+	tests := []struct {
+		name          string
+		id            string
+		checkerSetup  func(*mockUserChecker)
+		repoSetup     func(*mockTweetRepository)
+		expectedError error
+	}{
+		{
+			name: "successful retweet",
+			id:   "original",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "original").Return(original, nil)
+				m.On("SaveRetweet", mock.Anything, mock.Anything).Return(true, nil)
+				m.On("IncrementRetweetCount", mock.Anything, "original", int64(1)).Return(nil)
+			},
+		},
+		{
+			name: "retweeting a retweet targets the original",
+			id:   "rt",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "rt").Return(&tweetsdomain.Tweet{ID: "rt", Username: "other", RetweetOf: "original"}, nil)
+				m.On("GetTweet", mock.Anything, "original").Return(original, nil)
+				m.On("SaveRetweet", mock.Anything, mock.Anything).Return(true, nil)
+				m.On("IncrementRetweetCount", mock.Anything, "original", int64(1)).Return(nil)
+			},
+		},
+		{
+			name: "already retweeted",
+			id:   "original",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "original").Return(original, nil)
+				m.On("SaveRetweet", mock.Anything, mock.Anything).Return(false, nil)
+			},
+			expectedError: ErrAlreadyRetweeted,
+		},
+		{
+			name: "original deleted",
+			id:   "original",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "original").Return(&tweetsdomain.Tweet{ID: "original", DeletedAt: &deletedAt}, nil)
+			},
+			expectedError: ErrTweetNotFound,
+		},
+		{
+			name: "user does not exist",
+			id:   "original",
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "fan").Return(nil, errors.New("user does not exist"))
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := new(mockUserChecker)
+			repo := new(mockTweetRepository)
+			tt.checkerSetup(checker)
+			if tt.repoSetup != nil {
+				tt.repoSetup(repo)
+			}
+
+			publisher := events.NewMemoryPublisher()
+
+			service := NewTweetService(repo, checker, outbox.NopTransactor{}, publisher)
+			retweet, err := service.Retweet(context.Background(), tt.id, "fan")
+
+			if tt.expectedError != nil {
+				assert.Nil(t, retweet)
+				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, publisher.Events())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "fan", retweet.Username)
+				assert.Equal(t, "original", retweet.RetweetOf)
+				assert.Empty(t, retweet.Content)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.TweetCreated, published[0].Type)
+
+				var payload events.TweetCreatedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, retweet.ID, payload.TweetID)
+				assert.Equal(t, "original", payload.RetweetOf)
 			}
 			checker.AssertExpectations(t)
 			repo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestTweetService_UndoRetweet(t *testing.T) {
+	retweet := &tweetsdomain.Tweet{ID: "rt", Username: "fan", RetweetOf: "original"}
+
+	tests := []struct {
+		name          string
+		id            string
+		repoSetup     func(*mockTweetRepository)
+		expectedError error
+	}{
+		{
+			name: "undo by original id",
+			id:   "original",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "original").Return(&tweetsdomain.Tweet{ID: "original", Username: "author"}, nil)
+				m.On("DeleteRetweet", mock.Anything, "fan", "original").Return(retweet, nil)
+				m.On("IncrementRetweetCount", mock.Anything, "original", int64(-1)).Return(nil)
+			},
+		},
+		{
+			name: "undo by retweet id",
+			id:   "rt",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "rt").Return(retweet, nil)
+				m.On("DeleteRetweet", mock.Anything, "fan", "original").Return(retweet, nil)
+				m.On("IncrementRetweetCount", mock.Anything, "original", int64(-1)).Return(nil)
+			},
+		},
+		{
+			name: "not retweeted",
+			id:   "original",
+			repoSetup: func(m *mockTweetRepository) {
+				m.On("GetTweet", mock.Anything, "original").Return(&tweetsdomain.Tweet{ID: "original", Username: "author"}, nil)
+				m.On("DeleteRetweet", mock.Anything, "fan", "original").Return(nil, nil)
+			},
+			expectedError: ErrNotRetweeted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockTweetRepository)
+			tt.repoSetup(repo)
+
+			publisher := events.NewMemoryPublisher()
+
+			service := NewTweetService(repo, new(mockUserChecker), outbox.NopTransactor{}, publisher)
+			err := service.UndoRetweet(context.Background(), tt.id, "fan")
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, publisher.Events())
+			} else {
+				assert.NoError(t, err)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.TweetDeleted, published[0].Type)
+
+				var payload events.TweetDeletedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, "rt", payload.TweetID)
+				assert.Equal(t, "fan", payload.Username)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTweetService_ListUserTweets(t *testing.T) {
+	now := time.Now()
+	tweets := []tweetsdomain.Tweet{
+		{ID: "t2", Username: "author", Content: "Tweet 2", CreatedAt: now},
+		{ID: "t1", Username: "author", Content: "Tweet 1", CreatedAt: now.Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		name          string
+		limit         int
+		checkerSetup  func(*mockUserChecker)
+		repoSetup     func(*mockTweetRepository, tweetsdomain.TweetListOptions)
+		expectedNext  string
+		expectedError error
+	}{
+		{
+			name:  "full page returns next cursor",
+			limit: 2,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "author").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository, opts tweetsdomain.TweetListOptions) {
+				m.On("ListUserTweets", mock.Anything, "author", opts).Return(tweets, nil)
+			},
+			expectedNext: pagination.Cursor{CreatedAt: tweets[1].CreatedAt, ID: "t1"}.Encode(),
+		},
+		{
+			name:  "last page has no next cursor",
+			limit: 10,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "author").Return(&usersdomain.User{}, nil)
+			},
+			repoSetup: func(m *mockTweetRepository, opts tweetsdomain.TweetListOptions) {
+				m.On("ListUserTweets", mock.Anything, "author", opts).Return(tweets, nil)
+			},
+		},
+		{
+			name:  "user does not exist",
+			limit: 10,
+			checkerSetup: func(m *mockUserChecker) {
+				m.On("GetUser", "author").Return(nil, errors.New("user does not exist"))
+			},
+			repoSetup:     func(m *mockTweetRepository, opts tweetsdomain.TweetListOptions) {},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tweetsdomain.TweetListOptions{Limit: tt.limit, IncludeRetweets: true}
+			checker := new(mockUserChecker)
+			repo := new(mockTweetRepository)
+			tt.checkerSetup(checker)
+			tt.repoSetup(repo, opts)
+
+			service := NewTweetService(repo, checker, outbox.NopTransactor{}, events.NewMemoryPublisher())
+			page, err := service.ListUserTweets(context.Background(), "author", opts)
+
+			if tt.expectedError != nil {
+				assert.Nil(t, page)
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tweets, page.Tweets)
+				assert.Equal(t, tt.expectedNext, page.NextCursor)
+			}
+			checker.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTweetService_GetThread(t *testing.T) {
+	now := time.Now()
+	deletedAt := now
+	conversation := []tweetsdomain.Tweet{
+		{ID: "root", Username: "author", Content: "root", CreatedAt: now, ConversationID: "root"},
+		{ID: "r1", Username: "other", Content: "first", CreatedAt: now.Add(1 * time.Minute), ConversationID: "root", InReplyToTweetID: "root"},
+		{ID: "r2", Username: "author", Content: "self", CreatedAt: now.Add(2 * time.Minute), ConversationID: "root", InReplyToTweetID: "root"},
+		{ID: "r3", Username: "other", Content: "gone", CreatedAt: now.Add(3 * time.Minute), ConversationID: "root", InReplyToTweetID: "r1", DeletedAt: &deletedAt},
+		{ID: "r4", Username: "author", Content: "nested", CreatedAt: now.Add(4 * time.Minute), ConversationID: "root", InReplyToTweetID: "r3"},
+	}
+
+	repo := new(mockTweetRepository)
+	repo.On("GetTweet", mock.Anything, "r4").Return(&conversation[4], nil)
+	repo.On("GetConversation", mock.Anything, "root", maxThreadSize).Return(conversation, nil)
+
+	service := NewTweetService(repo, new(mockUserChecker), outbox.NopTransactor{}, events.NewMemoryPublisher())
+	thread, err := service.GetThread(context.Background(), "r4")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "root", thread.Root.ID)
+
+	// Las respuestas del autor de la raíz van primero
+	assert.Len(t, thread.Root.Replies, 2)
+	assert.Equal(t, "r2", thread.Root.Replies[0].ID)
+	assert.Equal(t, "r1", thread.Root.Replies[1].ID)
+
+	// Los tweets eliminados se conservan sin contenido
+	deleted := thread.Root.Replies[1].Replies[0]
+	assert.Equal(t, "r3", deleted.ID)
+	assert.True(t, deleted.Deleted)
+	assert.Empty(t, deleted.Content)
+	assert.Equal(t, "r4", deleted.Replies[0].ID)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Entities son los elementos estructurados reconocidos en el contenido de un
+// tweet. Start y End son posiciones en caracteres (runas) del contenido, con
+// End exclusivo, para que los clientes puedan resaltarlos sin volver a parsear.
+type Entities struct {
+	Hashtags []TagEntity     `bson:"hashtags,omitempty" json:"hashtags,omitempty"`
+	Mentions []MentionEntity `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	Cashtags []TagEntity     `bson:"cashtags,omitempty" json:"cashtags,omitempty"`
+	URLs     []URLEntity     `bson:"urls,omitempty" json:"urls,omitempty"`
+}
+
+// TagEntity es un hashtag o un cashtag; Text no incluye el prefijo # o $
+type TagEntity struct {
+	Text  string `bson:"text" json:"text"`
+	Start int    `bson:"start" json:"start"`
+	End   int    `bson:"end" json:"end"`
+}
+
+// MentionEntity es la mención a un usuario; Username no incluye el prefijo @
+type MentionEntity struct {
+	Username string `bson:"username" json:"username"`
+	Start    int    `bson:"start" json:"start"`
+	End      int    `bson:"end" json:"end"`
+}
+
+// URLEntity es una URL http o https
+type URLEntity struct {
+	URL   string `bson:"url" json:"url"`
+	Start int    `bson:"start" json:"start"`
+	End   int    `bson:"end" json:"end"`
+}
+
+// IsEmpty indica si no se reconoció ninguna entidad
+func (e *Entities) IsEmpty() bool {
+	return len(e.Hashtags) == 0 && len(e.Mentions) == 0 && len(e.Cashtags) == 0 && len(e.URLs) == 0
+}
+
+// HashtagTexts devuelve los hashtags sin repetir, en minúsculas y en el orden
+// en que aparecen
+func (e *Entities) HashtagTexts() []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, hashtag := range e.Hashtags {
+		tag := strings.ToLower(hashtag.Text)
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// MentionedUsernames devuelve los usuarios mencionados sin repetir, en el
+// orden en que aparecen
+func (e *Entities) MentionedUsernames() []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, mention := range e.Mentions {
+		if !seen[mention.Username] {
+			seen[mention.Username] = true
+			usernames = append(usernames, mention.Username)
+		}
+	}
+	return usernames
+}
+
+var (
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>"]+`)
+	hashtagPattern = regexp.MustCompile(`#[\p{L}\p{M}\p{N}_]+`)
+	mentionPattern = regexp.MustCompile(`@[A-Za-z0-9_]+`)
+	cashtagPattern = regexp.MustCompile(`\$[A-Za-z]{1,6}(?:[._][A-Za-z]{1,2})?`)
+)
+
+// urlTrailingPunctuation son los caracteres que suelen seguir a una URL en
+// el texto pero que no forman parte de ella
+const urlTrailingPunctuation = ".,;:!?)]}'\""
+
+// ExtractEntities reconoce hashtags, menciones, cashtags y URLs en el
+// contenido de un tweet. Los prefijos sólo cuentan al inicio del texto o
+// después de un carácter que no forme parte de una palabra, y lo que aparece
+// dentro de una URL no se interpreta como otra entidad.
+func ExtractEntities(content string) Entities {
+	var entities Entities
+	var urlRanges [][2]int
+
+	for _, loc := range urlPattern.FindAllStringIndex(content, -1) {
+		end := loc[0] + len(strings.TrimRight(content[loc[0]:loc[1]], urlTrailingPunctuation))
+		if !startsToken(content, loc[0]) {
+			continue
+		}
+		urlRanges = append(urlRanges, [2]int{loc[0], end})
+		entities.URLs = append(entities.URLs, URLEntity{
+			URL:   content[loc[0]:end],
+			Start: runeOffset(content, loc[0]),
+			End:   runeOffset(content, end),
+		})
+	}
+
+	insideURL := func(start int) bool {
+		for _, r := range urlRanges {
+			if start >= r[0] && start < r[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, loc := range hashtagPattern.FindAllStringIndex(content, -1) {
+		text := content[loc[0]+1 : loc[1]]
+		// Un hashtag necesita al menos una letra: "#1" no lo es
+		if !startsToken(content, loc[0]) || insideURL(loc[0]) || !strings.ContainsFunc(text, unicode.IsLetter) {
+			continue
+		}
+		entities.Hashtags = append(entities.Hashtags, TagEntity{
+			Text:  text,
+			Start: runeOffset(content, loc[0]),
+			End:   runeOffset(content, loc[1]),
+		})
+	}
+
+	for _, loc := range mentionPattern.FindAllStringIndex(content, -1) {
+		if !startsToken(content, loc[0]) || insideURL(loc[0]) {
+			continue
+		}
+		entities.Mentions = append(entities.Mentions, MentionEntity{
+			Username: content[loc[0]+1 : loc[1]],
+			Start:    runeOffset(content, loc[0]),
+			End:      runeOffset(content, loc[1]),
+		})
+	}
+
+	for _, loc := range cashtagPattern.FindAllStringIndex(content, -1) {
+		// El cashtag debe terminar en un límite de palabra: "$AAPLX1" no lo es
+		if !startsToken(content, loc[0]) || !endsToken(content, loc[1]) || insideURL(loc[0]) {
+			continue
+		}
+		entities.Cashtags = append(entities.Cashtags, TagEntity{
+			Text:  content[loc[0]+1 : loc[1]],
+			Start: runeOffset(content, loc[0]),
+			End:   runeOffset(content, loc[1]),
+		})
+	}
+
+	return entities
+}
+
+// startsToken indica si la posición está al inicio del texto o después de un
+// carácter que no forma parte de una palabra
+func startsToken(content string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(content[:i])
+	return !isWordRune(r)
+}
+
+// endsToken indica si la posición está al final del texto o antes de un
+// carácter que no forma parte de una palabra
+func endsToken(content string, i int) bool {
+	if i == len(content) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(content[i:])
+	return !isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_' || r == '@' || r == '#' || r == '$' || r == '&'
+}
+
+// runeOffset convierte una posición en bytes en una posición en caracteres
+func runeOffset(content string, i int) int {
+	return utf8.RuneCountInString(content[:i])
+}
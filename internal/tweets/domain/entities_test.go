@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Entities
+	}{
+		{
+			name:    "hashtags, mentions and cashtags",
+			content: "Hola @ana, mirá #GoLang y $AAPL",
+			expected: Entities{
+				Hashtags: []TagEntity{{Text: "GoLang", Start: 16, End: 23}},
+				Mentions: []MentionEntity{{Username: "ana", Start: 5, End: 9}},
+				Cashtags: []TagEntity{{Text: "AAPL", Start: 26, End: 31}},
+			},
+		},
+		{
+			name:    "offsets count characters, not bytes",
+			content: "ñandú #café",
+			expected: Entities{
+				Hashtags: []TagEntity{{Text: "café", Start: 6, End: 11}},
+			},
+		},
+		{
+			name:    "url without trailing punctuation",
+			content: "Leé https://example.com/a#frag.",
+			expected: Entities{
+				URLs: []URLEntity{{URL: "https://example.com/a#frag", Start: 4, End: 30}},
+			},
+		},
+		{
+			name:    "prefixes inside words are ignored",
+			content: "mail@example.com a#b $AAPLX1 #123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExtractEntities(tt.content))
+		})
+	}
+}
+
+func TestEntities_HashtagTexts(t *testing.T) {
+	entities := ExtractEntities("#Go #go #Rust")
+	assert.Equal(t, []string{"go", "rust"}, entities.HashtagTexts())
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+)
+
+// Like es una entidad del dominio que representa el like de un usuario a un tweet
+type Like struct {
+	ID        string    `bson:"_id" json:"id"`
+	Username  string    `bson:"username" json:"username"`
+	TweetID   string    `bson:"tweetId" json:"tweetId"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+// NewLike crea una nueva instancia de Like
+func NewLike(username, tweetID string) *Like {
+	return &Like{
+		ID:        uuid.New().String(),
+		Username:  username,
+		TweetID:   tweetID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Cursor devuelve el cursor que apunta a este like dentro de un listado
+func (l *Like) Cursor() pagination.Cursor {
+	return pagination.Cursor{CreatedAt: l.CreatedAt, ID: l.ID}
+}
+
+// Liker resume un usuario dentro del listado de quienes likearon un tweet
+type Liker struct {
+	Username string    `json:"username"`
+	LikedAt  time.Time `json:"likedAt"`
+}
+
+// LikerList es una página del listado de usuarios que likearon un tweet,
+// ordenada del like más reciente al más antiguo
+type LikerList struct {
+	Users      []Liker `json:"users"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "github.com/nicodelara/microblogging-uala/internal/common/pagination"
+
+// TweetListOptions describe la página a obtener de los tweets de un usuario
+type TweetListOptions struct {
+	// Cursor indica el último tweet de la página anterior; nil pide la primera
+	Cursor *pagination.Cursor
+	Limit  int
+
+	// IncludeReplies e IncludeRetweets indican si se incluyen las respuestas
+	// y los retweets del usuario además de sus tweets originales
+	IncludeReplies  bool
+	IncludeRetweets bool
+}
+
+// TweetPage es una página de tweets ordenada del más reciente al más antiguo
+type TweetPage struct {
+	Tweets     []Tweet `json:"tweets"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// NewTweetPage arma la página a partir de los tweets obtenidos. Una página
+// incompleta indica que no hay más resultados.
+func NewTweetPage(tweets []Tweet, limit int) *TweetPage {
+	page := &TweetPage{Tweets: tweets}
+	if page.Tweets == nil {
+		page.Tweets = make([]Tweet, 0)
+	}
+	if limit > 0 && len(tweets) == limit {
+		last := tweets[len(tweets)-1]
+		page.NextCursor = pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page
+}
@@ -2,7 +2,9 @@ package ports
 
 import (
 	"context"
+	"time"
 
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
 )
 
@@ -10,4 +12,52 @@ import (
 type TweetRepository interface {
 	// SaveTweet guarda un tweet en el repositorio
 	SaveTweet(ctx context.Context, tweet *domain.Tweet) error
+	// GetTweet obtiene un tweet por su id, incluso si fue eliminado.
+	// Devuelve nil si no existe.
+	GetTweet(ctx context.Context, id string) (*domain.Tweet, error)
+	// ListUserTweets obtiene los tweets vigentes de un usuario, del más
+	// reciente al más antiguo
+	ListUserTweets(ctx context.Context, username string, opts domain.TweetListOptions) ([]domain.Tweet, error)
+	// GetConversation obtiene hasta limit tweets de una conversación, incluidos
+	// los eliminados, en orden cronológico
+	GetConversation(ctx context.Context, conversationID string, limit int) ([]domain.Tweet, error)
+	// IncrementReplyCount aplica la variación sobre el contador de respuestas
+	IncrementReplyCount(ctx context.Context, id string, delta int64) error
+	// SaveRetweet guarda un retweet e indica si se guardó; devuelve false si el
+	// usuario ya había retweeteado el mismo tweet
+	SaveRetweet(ctx context.Context, retweet *domain.Tweet) (bool, error)
+	// DeleteRetweet elimina el retweet de un usuario sobre el tweet original y
+	// lo devuelve. Devuelve nil si no existía.
+	DeleteRetweet(ctx context.Context, username, originalID string) (*domain.Tweet, error)
+	// IncrementRetweetCount aplica la variación sobre el contador de retweets
+	IncrementRetweetCount(ctx context.Context, id string, delta int64) error
+	// IncrementLikeCount aplica la variación sobre el contador de likes
+	IncrementLikeCount(ctx context.Context, id string, delta int64) error
+	// GetTweetsByIDs obtiene los tweets vigentes con los ids indicados,
+	// respetando su orden. Los ids que no existen se omiten.
+	GetTweetsByIDs(ctx context.Context, ids []string) ([]domain.Tweet, error)
+	// DeleteTweet marca el tweet como eliminado e indica si estaba vigente
+	DeleteTweet(ctx context.Context, id string, deletedAt time.Time) (bool, error)
+}
+
+// LikeRepository define la interfaz para el repositorio de likes
+type LikeRepository interface {
+	// SaveLike guarda un like e indica si se guardó; devuelve false si el
+	// usuario ya había likeado el tweet
+	SaveLike(ctx context.Context, like *domain.Like) (bool, error)
+	// DeleteLike elimina el like de un usuario a un tweet e indica si existía
+	DeleteLike(ctx context.Context, username, tweetID string) (bool, error)
+	// ListLikers obtiene los likes de un tweet, del más reciente al más antiguo
+	ListLikers(ctx context.Context, tweetID string, cursor *pagination.Cursor, limit int) ([]domain.Like, error)
+	// ListUserLikes obtiene los likes de un usuario, del más reciente al más antiguo
+	ListUserLikes(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Like, error)
+}
+
+// TweetSearcher define la interfaz para la búsqueda de tweets. Sólo devuelve
+// tweets vigentes que no sean retweets, ordenados por relevancia descendente y
+// luego del más reciente al más antiguo.
+type TweetSearcher interface {
+	// SearchTweets obtiene hasta limit resultados posteriores al cursor, o
+	// desde el primero si el cursor es nil
+	SearchTweets(ctx context.Context, query domain.SearchQuery, cursor *domain.SearchCursor, limit int) ([]domain.SearchHit, error)
 }
@@ -3,11 +3,44 @@ package ports
 import (
 	"context"
 
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
 )
 
 // TweetService define la interfaz para el servicio de tweets
 type TweetService interface {
-	// CreateTweet crea un nuevo tweet
-	CreateTweet(ctx context.Context, username, content string) (*domain.Tweet, error)
+	// CreateTweet crea un nuevo tweet. El borrador indica si es una respuesta
+	// o un quote tweet.
+	CreateTweet(ctx context.Context, draft domain.TweetDraft) (*domain.Tweet, error)
+	// GetTweet obtiene un tweet vigente por su id
+	GetTweet(ctx context.Context, id string) (*domain.Tweet, error)
+	// GetThread obtiene la conversación completa a la que pertenece un tweet
+	GetThread(ctx context.Context, id string) (*domain.Thread, error)
+	// ListUserTweets lista los tweets de un usuario para su perfil
+	ListUserTweets(ctx context.Context, username string, opts domain.TweetListOptions) (*domain.TweetPage, error)
+	// Retweet crea el retweet de un tweet por parte de username
+	Retweet(ctx context.Context, id, username string) (*domain.Tweet, error)
+	// UndoRetweet elimina el retweet de username sobre un tweet
+	UndoRetweet(ctx context.Context, id, username string) error
+	// DeleteTweet elimina un tweet; sólo su autor puede hacerlo
+	DeleteTweet(ctx context.Context, id, username string) error
+}
+
+// LikeService define la interfaz para el servicio de likes
+type LikeService interface {
+	// LikeTweet registra el like de username a un tweet
+	LikeTweet(ctx context.Context, id, username string) (*domain.Like, error)
+	// UnlikeTweet elimina el like de username a un tweet
+	UnlikeTweet(ctx context.Context, id, username string) error
+	// ListLikers lista los usuarios que likearon un tweet
+	ListLikers(ctx context.Context, id string, cursor *pagination.Cursor, limit int) (*domain.LikerList, error)
+	// ListUserLikes lista los tweets que likeó un usuario, del like más
+	// reciente al más antiguo
+	ListUserLikes(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.TweetPage, error)
+}
+
+// SearchService define la interfaz para el servicio de búsqueda de tweets
+type SearchService interface {
+	// SearchTweets busca tweets según el texto de búsqueda del usuario
+	SearchTweets(ctx context.Context, query string, cursor *domain.SearchCursor, limit int) (*domain.SearchPage, error)
 }
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// searchDateLayout es el formato de las fechas de since: y until:
+const searchDateLayout = "2006-01-02"
+
+var (
+	// ErrInvalidSearchDate se devuelve cuando since: o until: no son una fecha
+	ErrInvalidSearchDate = errors.New("search dates must have the format YYYY-MM-DD")
+
+	// ErrInvalidSearchCursor se devuelve cuando un cursor de búsqueda no
+	// puede decodificarse
+	ErrInvalidSearchCursor = errors.New("invalid cursor")
+)
+
+// SearchQuery es una búsqueda de tweets ya interpretada. Un tweet coincide si
+// contiene todas las frases y, cuando no hay frases, al menos una palabra;
+// los demás criterios siempre se cumplen todos.
+type SearchQuery struct {
+	// Terms son las palabras sueltas en minúsculas; definen la relevancia
+	Terms []string
+	// Phrases son las frases entre comillas, ya separadas en palabras
+	Phrases [][]string
+	// From limita la búsqueda a los tweets de un usuario
+	From string
+	// Hashtags son los hashtags que el tweet debe contener, en minúsculas
+	Hashtags []string
+	// Since y Until limitan la fecha de creación; Since es inclusiva y Until
+	// exclusiva
+	Since *time.Time
+	Until *time.Time
+}
+
+// IsEmpty indica si la búsqueda no tiene ningún criterio
+func (q SearchQuery) IsEmpty() bool {
+	return !q.HasText() && q.From == "" && len(q.Hashtags) == 0 && q.Since == nil && q.Until == nil
+}
+
+// HasText indica si la búsqueda tiene palabras o frases
+func (q SearchQuery) HasText() bool {
+	return len(q.Terms) > 0 || len(q.Phrases) > 0
+}
+
+// ParseSearchQuery interpreta el texto de una búsqueda. Reconoce frases entre
+// comillas, from:usuario, #hashtag, since:AAAA-MM-DD y until:AAAA-MM-DD; el
+// resto del texto se separa en palabras.
+func ParseSearchQuery(raw string) (SearchQuery, error) {
+	var query SearchQuery
+	rest := raw
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			return query, nil
+		}
+
+		// Una frase sin comilla de cierre llega hasta el final del texto
+		if rest[0] == '"' {
+			phrase, after, _ := strings.Cut(rest[1:], `"`)
+			if tokens := Tokenize(phrase); len(tokens) > 0 {
+				query.Phrases = append(query.Phrases, tokens)
+			}
+			rest = after
+			continue
+		}
+
+		word := rest
+		if i := strings.IndexFunc(rest, unicode.IsSpace); i >= 0 {
+			word = rest[:i]
+		}
+		rest = rest[len(word):]
+
+		switch {
+		case strings.HasPrefix(word, "from:"):
+			query.From = strings.TrimPrefix(strings.TrimPrefix(word, "from:"), "@")
+		case strings.HasPrefix(word, "since:"):
+			since, err := time.Parse(searchDateLayout, strings.TrimPrefix(word, "since:"))
+			if err != nil {
+				return SearchQuery{}, ErrInvalidSearchDate
+			}
+			query.Since = &since
+		case strings.HasPrefix(word, "until:"):
+			until, err := time.Parse(searchDateLayout, strings.TrimPrefix(word, "until:"))
+			if err != nil {
+				return SearchQuery{}, ErrInvalidSearchDate
+			}
+			query.Until = &until
+		case strings.HasPrefix(word, "#"):
+			if tag := strings.ToLower(strings.TrimLeft(word, "#")); tag != "" {
+				query.Hashtags = append(query.Hashtags, tag)
+			}
+		default:
+			query.Terms = append(query.Terms, Tokenize(word)...)
+		}
+	}
+}
+
+// Tokenize separa un texto en palabras en minúsculas. Todo carácter que no
+// sea letra o dígito separa palabras, igual que en el índice de texto.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+}
+
+// SearchHit es un tweet encontrado junto con su relevancia
+type SearchHit struct {
+	Tweet Tweet
+	Score float64
+}
+
+// SearchCursor identifica una posición en resultados ordenados por
+// relevancia descendente, luego por fecha de creación descendente y por
+// último por id descendente
+type SearchCursor struct {
+	Score     float64
+	CreatedAt time.Time
+	ID        string
+}
+
+// Cursor devuelve el cursor que apunta a este resultado
+func (h SearchHit) Cursor() SearchCursor {
+	return SearchCursor{Score: h.Score, CreatedAt: h.Tweet.CreatedAt, ID: h.Tweet.ID}
+}
+
+// After indica si el resultado va después del cursor en el orden de búsqueda
+func (c SearchCursor) After(hit SearchHit) bool {
+	if hit.Score != c.Score {
+		return hit.Score < c.Score
+	}
+	if !hit.Tweet.CreatedAt.Equal(c.CreatedAt) {
+		return hit.Tweet.CreatedAt.Before(c.CreatedAt)
+	}
+	return hit.Tweet.ID < c.ID
+}
+
+// Encode devuelve la representación opaca del cursor
+func (c SearchCursor) Encode() string {
+	raw := strconv.FormatFloat(c.Score, 'g', -1, 64) + ":" + strconv.FormatInt(c.CreatedAt.UnixMilli(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseSearchCursor decodifica el cursor recibido como parámetro de una
+// request. Un valor vacío indica que se pide la primera página y devuelve nil.
+func ParseSearchCursor(encoded string) (*SearchCursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidSearchCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, ErrInvalidSearchCursor
+	}
+	score, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return nil, ErrInvalidSearchCursor
+	}
+	ms, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSearchCursor
+	}
+
+	return &SearchCursor{Score: score, CreatedAt: time.UnixMilli(ms).UTC(), ID: parts[2]}, nil
+}
+
+// SearchPage es una página de resultados de búsqueda ordenada por relevancia
+type SearchPage struct {
+	Tweets     []Tweet `json:"tweets"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+}
+
+// NewSearchPage arma la página a partir de los resultados obtenidos. Una
+// página incompleta indica que no hay más resultados.
+func NewSearchPage(hits []SearchHit, limit int) *SearchPage {
+	page := &SearchPage{Tweets: make([]Tweet, 0, len(hits))}
+	for _, hit := range hits {
+		page.Tweets = append(page.Tweets, hit.Tweet)
+	}
+	if limit > 0 && len(hits) == limit {
+		page.NextCursor = hits[len(hits)-1].Cursor().Encode()
+	}
+	return page
+}
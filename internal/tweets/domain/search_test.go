@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		raw           string
+		expected      SearchQuery
+		expectedError error
+	}{
+		{
+			name:     "keywords are lowercased and split on punctuation",
+			raw:      "Hola, Mundo!",
+			expected: SearchQuery{Terms: []string{"hola", "mundo"}},
+		},
+		{
+			name: "phrases, operators and keywords",
+			raw:  `go "Cache Distribuido" from:@ana #GoLang since:2024-01-01 until:2024-02-01`,
+			expected: SearchQuery{
+				Terms:    []string{"go"},
+				Phrases:  [][]string{{"cache", "distribuido"}},
+				From:     "ana",
+				Hashtags: []string{"golang"},
+				Since:    &since,
+				Until:    &until,
+			},
+		},
+		{
+			name:     "unterminated phrase runs to the end",
+			raw:      `"hola mundo`,
+			expected: SearchQuery{Phrases: [][]string{{"hola", "mundo"}}},
+		},
+		{
+			name:     "empty query",
+			raw:      `  "" # `,
+			expected: SearchQuery{},
+		},
+		{
+			name:          "invalid date",
+			raw:           "since:ayer",
+			expectedError: ErrInvalidSearchDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ParseSearchQuery(tt.raw)
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestSearchCursor(t *testing.T) {
+	cursor := SearchCursor{Score: 1.25, CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ID: "a:b"}
+
+	decoded, err := ParseSearchCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, &cursor, decoded)
+
+	decoded, err = ParseSearchCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+
+	_, err = ParseSearchCursor("!!")
+	assert.Equal(t, ErrInvalidSearchCursor, err)
+}
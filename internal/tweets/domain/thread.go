@@ -0,0 +1,69 @@
+package domain
+
+import "sort"
+
+// ThreadNode es un tweet de una conversación junto con sus respuestas
+type ThreadNode struct {
+	Tweet
+	Deleted bool          `json:"deleted,omitempty"`
+	Replies []*ThreadNode `json:"replies"`
+}
+
+// Thread es el árbol completo de una conversación
+type Thread struct {
+	ConversationID string      `json:"conversationId"`
+	Root           *ThreadNode `json:"root"`
+}
+
+// BuildThread arma el árbol de la conversación a partir de todos sus tweets.
+// Los tweets eliminados se conservan sin contenido para no romper el árbol.
+// En cada nivel se muestran primero las respuestas del autor de la raíz, que
+// continúan su hilo, y luego el resto, ambas en orden cronológico. Las
+// respuestas cuyo padre no está entre los tweets se cuelgan de la raíz.
+func BuildThread(conversationID string, tweets []Tweet) *Thread {
+	nodes := make(map[string]*ThreadNode, len(tweets))
+	for _, tweet := range tweets {
+		node := &ThreadNode{Tweet: tweet, Replies: make([]*ThreadNode, 0)}
+		if tweet.IsDeleted() {
+			node.Deleted = true
+			node.Content = ""
+		}
+		nodes[tweet.ID] = node
+	}
+
+	root, ok := nodes[conversationID]
+	if !ok {
+		return &Thread{ConversationID: conversationID}
+	}
+
+	for _, tweet := range tweets {
+		if tweet.ID == conversationID {
+			continue
+		}
+		parent, ok := nodes[tweet.InReplyToTweetID]
+		if !ok {
+			parent = root
+		}
+		parent.Replies = append(parent.Replies, nodes[tweet.ID])
+	}
+
+	for _, node := range nodes {
+		sortReplies(node.Replies, root.Username)
+	}
+
+	return &Thread{ConversationID: conversationID, Root: root}
+}
+
+func sortReplies(replies []*ThreadNode, rootAuthor string) {
+	sort.SliceStable(replies, func(i, j int) bool {
+		iAuthor := replies[i].Username == rootAuthor
+		jAuthor := replies[j].Username == rootAuthor
+		if iAuthor != jAuthor {
+			return iAuthor
+		}
+		if !replies[i].CreatedAt.Equal(replies[j].CreatedAt) {
+			return replies[i].CreatedAt.Before(replies[j].CreatedAt)
+		}
+		return replies[i].ID < replies[j].ID
+	})
+}
@@ -12,14 +12,101 @@ type Tweet struct {
 	Username  string    `bson:"username" json:"username"`
 	Content   string    `bson:"content" json:"content"`
 	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+
+	// InReplyToTweetID es el tweet al que responde; vacío si no es respuesta.
+	// InReplyToUsername es el autor de ese tweet y ConversationID es el id del
+	// tweet raíz de la conversación.
+	InReplyToTweetID  string `bson:"inReplyToTweetId,omitempty" json:"inReplyToTweetId,omitempty"`
+	InReplyToUsername string `bson:"inReplyToUsername,omitempty" json:"inReplyToUsername,omitempty"`
+	ConversationID    string `bson:"conversationId,omitempty" json:"conversationId,omitempty"`
+	ReplyCount        int64  `bson:"replyCount" json:"replyCount"`
+
+	// RetweetOf es el tweet original de un retweet, que no tiene contenido
+	// propio. QuotedTweetID es el tweet citado por un quote tweet.
+	RetweetOf     string `bson:"retweetOf,omitempty" json:"retweetOf,omitempty"`
+	QuotedTweetID string `bson:"quotedTweetId,omitempty" json:"quotedTweetId,omitempty"`
+	RetweetCount  int64  `bson:"retweetCount" json:"retweetCount"`
+
+	LikeCount int64 `bson:"likeCount" json:"likeCount"`
+
+	// Entities son los hashtags, menciones, cashtags y URLs del contenido;
+	// nil si no tiene ninguno
+	Entities *Entities `bson:"entities,omitempty" json:"entities,omitempty"`
+
+	// Hashtags son los hashtags de Entities en minúsculas y sin repetir, para
+	// buscar tweets por hashtag con un índice
+	Hashtags []string `bson:"hashtags,omitempty" json:"-"`
+
+	// DeletedAt se completa al eliminar el tweet. Los tweets eliminados se
+	// conservan en la base pero no se devuelven en ninguna lectura.
+	DeletedAt *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
 }
 
-// NewTweet crea una nueva instancia de Tweet
+// TweetDraft son los datos con los que un usuario publica un tweet.
+// InReplyToTweetID y QuotedTweetID son opcionales.
+type TweetDraft struct {
+	Username         string
+	Content          string
+	InReplyToTweetID string
+	QuotedTweetID    string
+}
+
+// NewTweet crea una nueva instancia de Tweet que inicia su propia conversación
 func NewTweet(username, content string) *Tweet {
+	id := uuid.New().String()
+	return &Tweet{
+		ID:             id,
+		Username:       username,
+		Content:        content,
+		CreatedAt:      time.Now(),
+		ConversationID: id,
+	}
+}
+
+// NewReply crea una respuesta a parent dentro de su conversación
+func NewReply(username, content string, parent *Tweet) *Tweet {
+	reply := NewTweet(username, content)
+	reply.InReplyToTweetID = parent.ID
+	reply.InReplyToUsername = parent.Username
+	reply.ConversationID = parent.RootID()
+	return reply
+}
+
+// NewRetweet crea el retweet de original por parte de username
+func NewRetweet(username string, original *Tweet) *Tweet {
 	return &Tweet{
 		ID:        uuid.New().String(),
 		Username:  username,
-		Content:   content,
 		CreatedAt: time.Now(),
+		RetweetOf: original.ID,
+	}
+}
+
+// RootID devuelve el id del tweet raíz de la conversación. Los tweets
+// anteriores a las conversaciones no tienen ConversationID y son su propia raíz.
+func (t *Tweet) RootID() string {
+	if t.ConversationID == "" {
+		return t.ID
 	}
+	return t.ConversationID
+}
+
+// IsReply indica si el tweet es una respuesta
+func (t *Tweet) IsReply() bool {
+	return t.InReplyToTweetID != ""
+}
+
+// IsRetweet indica si el tweet es un retweet
+func (t *Tweet) IsRetweet() bool {
+	return t.RetweetOf != ""
+}
+
+// IsQuote indica si el tweet cita a otro tweet
+func (t *Tweet) IsQuote() bool {
+	return t.QuotedTweetID != ""
+}
+
+// IsDeleted indica si el tweet fue eliminado
+func (t *Tweet) IsDeleted() bool {
+	return t.DeletedAt != nil
 }
@@ -1,10 +1,14 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/tweets/application"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
 	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
 )
 
@@ -21,6 +25,11 @@ func NewTweetHandler(service ports.TweetService) *TweetHandler {
 type createTweetRequest struct {
 	Username string `json:"username" binding:"required"`
 	Content  string `json:"content" binding:"required"`
+
+	// InReplyToTweetID es opcional; si se envía el tweet se crea como respuesta
+	InReplyToTweetID string `json:"inReplyToTweetId"`
+	// QuotedTweetID es opcional; si se envía el tweet cita al indicado
+	QuotedTweetID string `json:"quotedTweetId"`
 }
 
 func validateCreateTweetRequest(c *gin.Context) (*createTweetRequest, error) {
@@ -43,10 +52,15 @@ func (h *TweetHandler) CreateTweet(c *gin.Context) {
 		return
 	}
 
-	tweet, err := h.service.CreateTweet(c.Request.Context(), req.Username, req.Content)
+	tweet, err := h.service.CreateTweet(c.Request.Context(), domain.TweetDraft{
+		Username:         req.Username,
+		Content:          req.Content,
+		InReplyToTweetID: req.InReplyToTweetID,
+		QuotedTweetID:    req.QuotedTweetID,
+	})
 	if err != nil {
 		switch err {
-		case application.ErrUserNotFound:
+		case application.ErrUserNotFound, application.ErrParentNotFound, application.ErrQuotedNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -56,3 +70,198 @@ func (h *TweetHandler) CreateTweet(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, tweet)
 }
+
+// usernameHeader identifica al usuario que realiza la request. El servicio no
+// autentica: se espera que el header lo complete el gateway.
+const usernameHeader = "X-Username"
+
+func (h *TweetHandler) GetTweet(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id is required"})
+		return
+	}
+
+	tweet, err := h.service.GetTweet(c.Request.Context(), id)
+	if err != nil {
+		switch err {
+		case application.ErrTweetNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, tweet)
+}
+
+func (h *TweetHandler) GetThread(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id is required"})
+		return
+	}
+
+	thread, err := h.service.GetThread(c.Request.Context(), id)
+	if err != nil {
+		switch err {
+		case application.ErrTweetNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// validateTweetActionRequest obtiene el tweet sobre el que actúa la request y
+// el usuario que la realiza
+func validateTweetActionRequest(c *gin.Context) (string, string, error) {
+	id := c.Param("id")
+	if id == "" {
+		return "", "", errors.New("id is required")
+	}
+
+	username := c.GetHeader(usernameHeader)
+	if username == "" {
+		return "", "", errors.New(usernameHeader + " header is required")
+	}
+	return id, username, nil
+}
+
+func (h *TweetHandler) DeleteTweet(c *gin.Context) {
+	id, username, err := validateTweetActionRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.service.DeleteTweet(c.Request.Context(), id, username)
+	if err != nil {
+		switch err {
+		case application.ErrTweetNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case application.ErrNotTweetAuthor:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TweetHandler) Retweet(c *gin.Context) {
+	id, username, err := validateTweetActionRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	retweet, err := h.service.Retweet(c.Request.Context(), id, username)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound, application.ErrTweetNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case application.ErrAlreadyRetweeted:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, retweet)
+}
+
+func (h *TweetHandler) UndoRetweet(c *gin.Context) {
+	id, username, err := validateTweetActionRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.service.UndoRetweet(c.Request.Context(), id, username)
+	if err != nil {
+		switch err {
+		case application.ErrNotRetweeted:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseListPage obtiene el cursor y el límite de un listado paginado
+func parseListPage(c *gin.Context) (*pagination.Cursor, int, error) {
+	limit, err := pagination.ParseLimit(c.Query("limit"), pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := pagination.ParseCursor(c.Query("cursor"))
+	if err != nil {
+		return nil, 0, err
+	}
+	return cursor, limit, nil
+}
+
+func validateListUserTweetsRequest(c *gin.Context) (string, *domain.TweetListOptions, error) {
+	username := c.Param("username")
+	if username == "" {
+		return "", nil, errors.New("username is required")
+	}
+
+	cursor, limit, err := parseListPage(c)
+	if err != nil {
+		return "", nil, err
+	}
+
+	opts := &domain.TweetListOptions{
+		Cursor:          cursor,
+		Limit:           limit,
+		IncludeReplies:  false,
+		IncludeRetweets: true,
+	}
+
+	if v := c.Query("include_replies"); v != "" {
+		if opts.IncludeReplies, err = strconv.ParseBool(v); err != nil {
+			return "", nil, errors.New("include_replies must be a boolean")
+		}
+	}
+	if v := c.Query("include_retweets"); v != "" {
+		if opts.IncludeRetweets, err = strconv.ParseBool(v); err != nil {
+			return "", nil, errors.New("include_retweets must be a boolean")
+		}
+	}
+
+	return username, opts, nil
+}
+
+func (h *TweetHandler) ListUserTweets(c *gin.Context) {
+	username, opts, err := validateListUserTweetsRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListUserTweets(c.Request.Context(), username, *opts)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
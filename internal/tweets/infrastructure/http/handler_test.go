@@ -21,19 +21,65 @@ type mockTweetService struct {
 	mock.Mock
 }
 
-func (m *mockTweetService) CreateTweet(ctx context.Context, username, content string) (*domain.Tweet, error) {
-	args := m.Called(ctx, username, content)
+func (m *mockTweetService) CreateTweet(ctx context.Context, draft domain.TweetDraft) (*domain.Tweet, error) {
+	args := m.Called(ctx, draft)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Tweet), args.Error(1)
 }
 
+func (m *mockTweetService) GetTweet(ctx context.Context, id string) (*domain.Tweet, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tweet), args.Error(1)
+}
+
+func (m *mockTweetService) DeleteTweet(ctx context.Context, id, username string) error {
+	args := m.Called(ctx, id, username)
+	return args.Error(0)
+}
+
+func (m *mockTweetService) ListUserTweets(ctx context.Context, username string, opts domain.TweetListOptions) (*domain.TweetPage, error) {
+	args := m.Called(ctx, username, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TweetPage), args.Error(1)
+}
+
+func (m *mockTweetService) GetThread(ctx context.Context, id string) (*domain.Thread, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Thread), args.Error(1)
+}
+
+func (m *mockTweetService) Retweet(ctx context.Context, id, username string) (*domain.Tweet, error) {
+	args := m.Called(ctx, id, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tweet), args.Error(1)
+}
+
+func (m *mockTweetService) UndoRetweet(ctx context.Context, id, username string) error {
+	args := m.Called(ctx, id, username)
+	return args.Error(0)
+}
+
 func setupRouter(service ports.TweetService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
 	handler := NewTweetHandler(service)
 	router.POST("/tweets", handler.CreateTweet)
+	router.DELETE("/tweets/:id", handler.DeleteTweet)
+	router.POST("/tweets/:id/retweet", handler.Retweet)
+	router.DELETE("/tweets/:id/retweet", handler.UndoRetweet)
+	router.GET("/users/:username/tweets", handler.ListUserTweets)
 	return router
 }
 
@@ -60,10 +106,10 @@ func TestTweetHandler_CreateTweet(t *testing.T) {
 					Content:   "Hello, world!",
 					CreatedAt: createdAt,
 				}
-				m.On("CreateTweet", mock.Anything, "testuser", "Hello, world!").Return(tweet, nil)
+				m.On("CreateTweet", mock.Anything, domain.TweetDraft{Username: "testuser", Content: "Hello, world!"}).Return(tweet, nil)
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody:   `{"id":"123","username":"testuser","content":"Hello, world!","createdAt":"2024-01-01T00:00:00Z"}`,
+			expectedBody:   `{"id":"123","username":"testuser","content":"Hello, world!","createdAt":"2024-01-01T00:00:00Z","replyCount":0,"retweetCount":0,"likeCount":0}`,
 		},
 		{
 			name: "missing required fields",
@@ -83,7 +129,7 @@ func TestTweetHandler_CreateTweet(t *testing.T) {
 				"content":  "Hello, world!",
 			},
 			serviceSetup: func(m *mockTweetService) {
-				m.On("CreateTweet", mock.Anything, "nonexistent", "Hello, world!").Return(nil, application.ErrUserNotFound)
+				m.On("CreateTweet", mock.Anything, domain.TweetDraft{Username: "nonexistent", Content: "Hello, world!"}).Return(nil, application.ErrUserNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedBody:   `{"error":"user does not exist"}`,
@@ -104,7 +150,7 @@ func TestTweetHandler_CreateTweet(t *testing.T) {
 				"content":  "Hello, world!",
 			},
 			serviceSetup: func(m *mockTweetService) {
-				m.On("CreateTweet", mock.Anything, "testuser", "Hello, world!").Return(nil, assert.AnError)
+				m.On("CreateTweet", mock.Anything, domain.TweetDraft{Username: "testuser", Content: "Hello, world!"}).Return(nil, assert.AnError)
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   `{"error":"assert.AnError general error for testing"}`,
@@ -132,3 +178,218 @@ func TestTweetHandler_CreateTweet(t *testing.T) {
 		})
 	}
 }
+
+func TestTweetHandler_DeleteTweet(t *testing.T) {
+	tests := []struct {
+		name           string
+		username       string
+		serviceSetup   func(*mockTweetService)
+		expectedStatus int
+	}{
+		{
+			name:     "successful deletion",
+			username: "author",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("DeleteTweet", mock.Anything, "123", "author").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "missing username header",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "not the author",
+			username: "other",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("DeleteTweet", mock.Anything, "123", "other").Return(application.ErrNotTweetAuthor)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "tweet not found",
+			username: "author",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("DeleteTweet", mock.Anything, "123", "author").Return(application.ErrTweetNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockTweetService)
+			if tt.serviceSetup != nil {
+				tt.serviceSetup(service)
+			}
+
+			router := setupRouter(service)
+			req := httptest.NewRequest("DELETE", "/tweets/123", nil)
+			if tt.username != "" {
+				req.Header.Set("X-Username", tt.username)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTweetHandler_Retweet(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		method         string
+		username       string
+		serviceSetup   func(*mockTweetService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful retweet",
+			method:   "POST",
+			username: "fan",
+			serviceSetup: func(m *mockTweetService) {
+				retweet := &domain.Tweet{ID: "rt", Username: "fan", CreatedAt: createdAt, RetweetOf: "123"}
+				m.On("Retweet", mock.Anything, "123", "fan").Return(retweet, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"rt","username":"fan","content":"","createdAt":"2024-01-01T00:00:00Z","replyCount":0,"retweetOf":"123","retweetCount":0,"likeCount":0}`,
+		},
+		{
+			name:           "missing username header",
+			method:         "POST",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"X-Username header is required"}`,
+		},
+		{
+			name:     "already retweeted",
+			method:   "POST",
+			username: "fan",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("Retweet", mock.Anything, "123", "fan").Return(nil, application.ErrAlreadyRetweeted)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"tweet already retweeted"}`,
+		},
+		{
+			name:     "tweet not found",
+			method:   "POST",
+			username: "fan",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("Retweet", mock.Anything, "123", "fan").Return(nil, application.ErrTweetNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"tweet not found"}`,
+		},
+		{
+			name:     "successful undo",
+			method:   "DELETE",
+			username: "fan",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("UndoRetweet", mock.Anything, "123", "fan").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:     "undo without retweet",
+			method:   "DELETE",
+			username: "fan",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("UndoRetweet", mock.Anything, "123", "fan").Return(application.ErrNotRetweeted)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"tweet not retweeted"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockTweetService)
+			if tt.serviceSetup != nil {
+				tt.serviceSetup(service)
+			}
+
+			router := setupRouter(service)
+			req := httptest.NewRequest(tt.method, "/tweets/123/retweet", nil)
+			if tt.username != "" {
+				req.Header.Set("X-Username", tt.username)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			service.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTweetHandler_ListUserTweets(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		serviceSetup   func(*mockTweetService)
+		expectedStatus int
+	}{
+		{
+			name: "defaults exclude replies and include retweets",
+			serviceSetup: func(m *mockTweetService) {
+				opts := domain.TweetListOptions{Limit: 20, IncludeRetweets: true}
+				m.On("ListUserTweets", mock.Anything, "author", opts).Return(&domain.TweetPage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "filters from query",
+			query: "?limit=5&include_replies=true&include_retweets=false",
+			serviceSetup: func(m *mockTweetService) {
+				opts := domain.TweetListOptions{Limit: 5, IncludeReplies: true}
+				m.On("ListUserTweets", mock.Anything, "author", opts).Return(&domain.TweetPage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid cursor",
+			query:          "?cursor=%25%25",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "limit out of range",
+			query:          "?limit=500",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "user does not exist",
+			serviceSetup: func(m *mockTweetService) {
+				m.On("ListUserTweets", mock.Anything, "author", mock.Anything).Return(nil, application.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockTweetService)
+			if tt.serviceSetup != nil {
+				tt.serviceSetup(service)
+			}
+
+			router := setupRouter(service)
+			req := httptest.NewRequest("GET", "/users/author/tweets"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/application"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
+)
+
+type LikeHandler struct {
+	service ports.LikeService
+}
+
+func NewLikeHandler(service ports.LikeService) *LikeHandler {
+	return &LikeHandler{
+		service: service,
+	}
+}
+
+func (h *LikeHandler) LikeTweet(c *gin.Context) {
+	id, username, err := validateTweetActionRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	like, err := h.service.LikeTweet(c.Request.Context(), id, username)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound, application.ErrTweetNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case application.ErrAlreadyLiked:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, like)
+}
+
+func (h *LikeHandler) UnlikeTweet(c *gin.Context) {
+	id, username, err := validateTweetActionRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.service.UnlikeTweet(c.Request.Context(), id, username)
+	if err != nil {
+		switch err {
+		case application.ErrNotLiked:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LikeHandler) ListLikers(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id is required"})
+		return
+	}
+
+	cursor, limit, err := parseListPage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := h.service.ListLikers(c.Request.Context(), id, cursor, limit)
+	if err != nil {
+		switch err {
+		case application.ErrTweetNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *LikeHandler) ListUserLikes(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+
+	cursor, limit, err := parseListPage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListUserLikes(c.Request.Context(), username, cursor, limit)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
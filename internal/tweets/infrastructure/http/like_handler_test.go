@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/application"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLikeService struct {
+	mock.Mock
+}
+
+func (m *mockLikeService) LikeTweet(ctx context.Context, id, username string) (*domain.Like, error) {
+	args := m.Called(ctx, id, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Like), args.Error(1)
+}
+
+func (m *mockLikeService) UnlikeTweet(ctx context.Context, id, username string) error {
+	args := m.Called(ctx, id, username)
+	return args.Error(0)
+}
+
+func (m *mockLikeService) ListLikers(ctx context.Context, id string, cursor *pagination.Cursor, limit int) (*domain.LikerList, error) {
+	args := m.Called(ctx, id, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LikerList), args.Error(1)
+}
+
+func (m *mockLikeService) ListUserLikes(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.TweetPage, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TweetPage), args.Error(1)
+}
+
+func setupLikeRouter(service *mockLikeService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	handler := NewLikeHandler(service)
+	router.POST("/tweets/:id/like", handler.LikeTweet)
+	router.DELETE("/tweets/:id/like", handler.UnlikeTweet)
+	router.GET("/tweets/:id/likes", handler.ListLikers)
+	return router
+}
+
+func TestLikeHandler(t *testing.T) {
+	likedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		username       string
+		serviceSetup   func(*mockLikeService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "successful like",
+			method:   "POST",
+			path:     "/tweets/123/like",
+			username: "fan",
+			serviceSetup: func(m *mockLikeService) {
+				like := &domain.Like{ID: "l1", Username: "fan", TweetID: "123", CreatedAt: likedAt}
+				m.On("LikeTweet", mock.Anything, "123", "fan").Return(like, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"l1","username":"fan","tweetId":"123","createdAt":"2024-01-01T00:00:00Z"}`,
+		},
+		{
+			name:           "like without username header",
+			method:         "POST",
+			path:           "/tweets/123/like",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"X-Username header is required"}`,
+		},
+		{
+			name:     "already liked",
+			method:   "POST",
+			path:     "/tweets/123/like",
+			username: "fan",
+			serviceSetup: func(m *mockLikeService) {
+				m.On("LikeTweet", mock.Anything, "123", "fan").Return(nil, application.ErrAlreadyLiked)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"tweet already liked"}`,
+		},
+		{
+			name:     "unlike without like",
+			method:   "DELETE",
+			path:     "/tweets/123/like",
+			username: "fan",
+			serviceSetup: func(m *mockLikeService) {
+				m.On("UnlikeTweet", mock.Anything, "123", "fan").Return(application.ErrNotLiked)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"tweet not liked"}`,
+		},
+		{
+			name:   "list likers",
+			method: "GET",
+			path:   "/tweets/123/likes?limit=1",
+			serviceSetup: func(m *mockLikeService) {
+				list := &domain.LikerList{
+					Users:      []domain.Liker{{Username: "fan", LikedAt: likedAt}},
+					NextCursor: "next",
+				}
+				m.On("ListLikers", mock.Anything, "123", (*pagination.Cursor)(nil), 1).Return(list, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"users":[{"username":"fan","likedAt":"2024-01-01T00:00:00Z"}],"nextCursor":"next"}`,
+		},
+		{
+			name:           "list likers with invalid limit",
+			method:         "GET",
+			path:           "/tweets/123/likes?limit=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"limit must be between 1 and 100"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockLikeService)
+			if tt.serviceSetup != nil {
+				tt.serviceSetup(service)
+			}
+
+			router := setupLikeRouter(service)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.username != "" {
+				req.Header.Set("X-Username", tt.username)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/application"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
+)
+
+type SearchHandler struct {
+	service ports.SearchService
+}
+
+func NewSearchHandler(service ports.SearchService) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+	}
+}
+
+func (h *SearchHandler) SearchTweets(c *gin.Context) {
+	limit, err := pagination.ParseLimit(c.Query("limit"), pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cursor, err := domain.ParseSearchCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.SearchTweets(c.Request.Context(), c.Query("q"), cursor, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrEmptySearchQuery), errors.Is(err, domain.ErrInvalidSearchDate):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
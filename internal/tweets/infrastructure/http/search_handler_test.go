@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/application"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockSearchService struct {
+	mock.Mock
+}
+
+func (m *mockSearchService) SearchTweets(ctx context.Context, query string, cursor *domain.SearchCursor, limit int) (*domain.SearchPage, error) {
+	args := m.Called(ctx, query, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchPage), args.Error(1)
+}
+
+func TestSearchHandler_SearchTweets(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := domain.SearchCursor{Score: 1.5, CreatedAt: createdAt, ID: "123"}
+
+	tests := []struct {
+		name           string
+		path           string
+		serviceSetup   func(*mockSearchService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "successful search",
+			path: "/search/tweets?q=hola&limit=1",
+			serviceSetup: func(m *mockSearchService) {
+				page := &domain.SearchPage{
+					Tweets:     []domain.Tweet{{ID: "123", Username: "ana", Content: "hola", CreatedAt: createdAt}},
+					NextCursor: "next",
+				}
+				m.On("SearchTweets", mock.Anything, "hola", (*domain.SearchCursor)(nil), 1).Return(page, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"tweets":[{"id":"123","username":"ana","content":"hola","createdAt":"2024-01-01T00:00:00Z","replyCount":0,"retweetCount":0,"likeCount":0}],"nextCursor":"next"}`,
+		},
+		{
+			name: "search with cursor",
+			path: "/search/tweets?q=hola&cursor=" + cursor.Encode(),
+			serviceSetup: func(m *mockSearchService) {
+				m.On("SearchTweets", mock.Anything, "hola", &cursor, 20).Return(&domain.SearchPage{Tweets: []domain.Tweet{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"tweets":[]}`,
+		},
+		{
+			name: "empty query",
+			path: "/search/tweets",
+			serviceSetup: func(m *mockSearchService) {
+				m.On("SearchTweets", mock.Anything, "", (*domain.SearchCursor)(nil), 20).Return(nil, application.ErrEmptySearchQuery)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"search query is required"}`,
+		},
+		{
+			name:           "invalid cursor",
+			path:           "/search/tweets?q=hola&cursor=!!",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid cursor"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockSearchService)
+			if tt.serviceSetup != nil {
+				tt.serviceSetup(service)
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.Default()
+			router.GET("/search/tweets", NewSearchHandler(service).SearchTweets)
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			service.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+)
+
+// InvertedIndex es un buscador de tweets en memoria basado en un índice
+// invertido, pensado para los tests del servicio de búsqueda. Comparte con la
+// búsqueda en MongoDB los filtros, la regla de que el tweet debe contener
+// todas las frases o, sin frases, alguna palabra, y el orden de los
+// resultados, pero no es equivalente en todo:
+//   - separa las palabras con domain.Tokenize, mientras que MongoDB usa su
+//     propio tokenizador e ignora además acentos y diéresis
+//   - exige que las palabras de una frase aparezcan seguidas como palabras
+//     completas, mientras que MongoDB busca la frase como subcadena del
+//     contenido sin distinguir mayúsculas
+//   - la relevancia suma las apariciones de cada palabra ponderadas por lo
+//     poco frecuente que es, y no coincide con el textScore de MongoDB
+type InvertedIndex struct {
+	mu sync.RWMutex
+	// tweets guarda cada tweet indexado junto con sus palabras en orden
+	tweets map[string]indexedTweet
+	// postings indica, para cada palabra, cuántas veces aparece en cada tweet
+	postings map[string]map[string]int
+}
+
+type indexedTweet struct {
+	tweet  domain.Tweet
+	tokens []string
+}
+
+// NewInvertedIndex crea un índice vacío
+func NewInvertedIndex() *InvertedIndex {
+	return &InvertedIndex{
+		tweets:   make(map[string]indexedTweet),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// Index agrega un tweet al índice o lo reemplaza si ya estaba. Los retweets
+// y los tweets eliminados se quitan del índice.
+func (idx *InvertedIndex) Index(tweet domain.Tweet) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(tweet.ID)
+	if tweet.IsRetweet() || tweet.IsDeleted() {
+		return
+	}
+
+	// Igual que MongoDB, las fechas se guardan con precisión de milisegundos
+	// para que los cursores codificados coincidan con los tweets
+	tweet.CreatedAt = tweet.CreatedAt.Truncate(time.Millisecond)
+	tokens := domain.Tokenize(tweet.Content)
+	idx.tweets[tweet.ID] = indexedTweet{tweet: tweet, tokens: tokens}
+	for _, token := range tokens {
+		if idx.postings[token] == nil {
+			idx.postings[token] = make(map[string]int)
+		}
+		idx.postings[token][tweet.ID]++
+	}
+}
+
+// Remove quita un tweet del índice
+func (idx *InvertedIndex) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+func (idx *InvertedIndex) remove(id string) {
+	indexed, ok := idx.tweets[id]
+	if !ok {
+		return
+	}
+	for _, token := range indexed.tokens {
+		delete(idx.postings[token], id)
+		if len(idx.postings[token]) == 0 {
+			delete(idx.postings, token)
+		}
+	}
+	delete(idx.tweets, id)
+}
+
+func (idx *InvertedIndex) SearchTweets(ctx context.Context, query domain.SearchQuery, cursor *domain.SearchCursor, limit int) ([]domain.SearchHit, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var hits []domain.SearchHit
+	for _, id := range idx.candidates(query) {
+		indexed := idx.tweets[id]
+		if !idx.matches(indexed, query) {
+			continue
+		}
+		hit := domain.SearchHit{Tweet: indexed.tweet, Score: idx.score(id, query)}
+		if cursor != nil && !cursor.After(hit) {
+			continue
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Cursor().After(hits[j])
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// candidates devuelve los tweets que pueden coincidir con las palabras de la
+// búsqueda: los que contienen la primera palabra de cada frase o, sin
+// frases, alguna de las palabras sueltas
+func (idx *InvertedIndex) candidates(query domain.SearchQuery) []string {
+	var terms []string
+	switch {
+	case len(query.Phrases) > 0:
+		terms = []string{query.Phrases[0][0]}
+	case len(query.Terms) > 0:
+		terms = query.Terms
+	default:
+		ids := make([]string, 0, len(idx.tweets))
+		for id := range idx.tweets {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, term := range terms {
+		for id := range idx.postings[term] {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func (idx *InvertedIndex) matches(indexed indexedTweet, query domain.SearchQuery) bool {
+	tweet := indexed.tweet
+	if query.From != "" && tweet.Username != query.From {
+		return false
+	}
+	if query.Since != nil && tweet.CreatedAt.Before(*query.Since) {
+		return false
+	}
+	if query.Until != nil && !tweet.CreatedAt.Before(*query.Until) {
+		return false
+	}
+	for _, tag := range query.Hashtags {
+		if !slices.Contains(tweet.Hashtags, tag) {
+			return false
+		}
+	}
+	for _, phrase := range query.Phrases {
+		if !containsPhrase(indexed.tokens, phrase) {
+			return false
+		}
+	}
+	return true
+}
+
+// score suma, para cada palabra buscada, sus apariciones en el tweet
+// ponderadas por lo poco frecuente que es la palabra en el índice
+func (idx *InvertedIndex) score(id string, query domain.SearchQuery) float64 {
+	terms := slices.Clone(query.Terms)
+	for _, phrase := range query.Phrases {
+		terms = append(terms, phrase...)
+	}
+
+	var score float64
+	total := float64(len(idx.tweets))
+	for _, term := range terms {
+		postings := idx.postings[term]
+		if count := postings[id]; count > 0 {
+			score += float64(count) * math.Log(1+total/float64(len(postings)))
+		}
+	}
+	return score
+}
+
+// containsPhrase indica si las palabras de la frase aparecen seguidas
+func containsPhrase(tokens, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package mongo
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoLikeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoLikeRepository(client *mongo.Client, dbName, collName string) (ports.LikeRepository, error) {
+	collection := client.Database(dbName).Collection(collName)
+
+	// Crear índices
+	indexModels := []mongo.IndexModel{
+		{
+			// Un usuario puede likear un tweet una sola vez
+			Keys: bson.D{
+				{Key: "username", Value: 1},
+				{Key: "tweetId", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Likes de un usuario paginados por fecha
+			Keys: bson.D{
+				{Key: "username", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			// Likes de un tweet paginados por fecha
+			Keys: bson.D{
+				{Key: "tweetId", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+	}
+
+	_, err := collection.Indexes().CreateMany(context.Background(), indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoLikeRepository{
+		collection: collection,
+	}, nil
+}
+
+// SaveLike inserta el like; el índice único de username y tweetId rechaza un
+// segundo like del mismo usuario
+func (r *mongoLikeRepository) SaveLike(ctx context.Context, like *domain.Like) (bool, error) {
+	_, err := r.collection.InsertOne(ctx, like)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mongoLikeRepository) DeleteLike(ctx context.Context, username, tweetID string) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"username": username, "tweetId": tweetID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *mongoLikeRepository) ListLikers(ctx context.Context, tweetID string, cursor *pagination.Cursor, limit int) ([]domain.Like, error) {
+	return r.list(ctx, bson.M{"tweetId": tweetID}, cursor, limit)
+}
+
+func (r *mongoLikeRepository) ListUserLikes(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Like, error) {
+	return r.list(ctx, bson.M{"username": username}, cursor, limit)
+}
+
+// list devuelve los likes que cumplen el filtro, del más reciente al más
+// antiguo, empezando después del cursor si no es nil
+func (r *mongoLikeRepository) list(ctx context.Context, filter bson.M, cursor *pagination.Cursor, limit int) ([]domain.Like, error) {
+	if cursor != nil {
+		filter["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	result, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	var likes []domain.Like
+	if err := result.All(ctx, &likes); err != nil {
+		return nil, err
+	}
+	return likes, nil
+}
@@ -2,11 +2,13 @@ package mongo
 
 import (
 	"context"
+	"time"
 
 	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
 	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoTweetRepository struct {
@@ -20,11 +22,38 @@ func NewMongoTweetRepository(client *mongo.Client, dbName, collName string) (por
 	// Crear índices
 	indexModels := []mongo.IndexModel{
 		{
+			// Tweets de un usuario por fecha; _id desempata la paginación
 			Keys: bson.D{
 				{Key: "username", Value: 1},
 				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
 			},
 		},
+		{
+			// Tweets de una conversación en orden cronológico
+			Keys: bson.D{
+				{Key: "conversationId", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+		},
+		{
+			// Tweets con un hashtag por fecha; _id desempata la paginación
+			Keys: bson.D{
+				{Key: "hashtags", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			// Un usuario puede retweetear un tweet una sola vez
+			Keys: bson.D{
+				{Key: "retweetOf", Value: 1},
+				{Key: "username", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"retweetOf": bson.M{"$exists": true}}),
+		},
 	}
 
 	_, err := collection.Indexes().CreateMany(context.Background(), indexModels)
@@ -41,3 +70,147 @@ func (r *mongoTweetRepository) SaveTweet(ctx context.Context, tweet *domain.Twee
 	_, err := r.collection.InsertOne(ctx, tweet)
 	return err
 }
+
+func (r *mongoTweetRepository) GetTweet(ctx context.Context, id string) (*domain.Tweet, error) {
+	var tweet domain.Tweet
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&tweet)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tweet, nil
+}
+
+func (r *mongoTweetRepository) ListUserTweets(ctx context.Context, username string, opts domain.TweetListOptions) ([]domain.Tweet, error) {
+	filter := bson.M{"username": username, "deletedAt": bson.M{"$exists": false}}
+	if !opts.IncludeReplies {
+		filter["inReplyToTweetId"] = bson.M{"$exists": false}
+	}
+	if !opts.IncludeRetweets {
+		filter["retweetOf"] = bson.M{"$exists": false}
+	}
+	if opts.Cursor != nil {
+		filter["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$lt": opts.Cursor.CreatedAt}},
+			bson.M{"createdAt": opts.Cursor.CreatedAt, "_id": bson.M{"$lt": opts.Cursor.ID}},
+		}
+	}
+
+	findOpts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(opts.Limit))
+	cursor, err := r.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tweets []domain.Tweet
+	if err := cursor.All(ctx, &tweets); err != nil {
+		return nil, err
+	}
+	return tweets, nil
+}
+
+func (r *mongoTweetRepository) GetConversation(ctx context.Context, conversationID string, limit int) ([]domain.Tweet, error) {
+	// La raíz se busca también por _id por si es anterior a las conversaciones
+	filter := bson.M{"$or": bson.A{
+		bson.M{"conversationId": conversationID},
+		bson.M{"_id": conversationID},
+	}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tweets []domain.Tweet
+	if err := cursor.All(ctx, &tweets); err != nil {
+		return nil, err
+	}
+	return tweets, nil
+}
+
+func (r *mongoTweetRepository) IncrementReplyCount(ctx context.Context, id string, delta int64) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"replyCount": delta}})
+	return err
+}
+
+// SaveRetweet inserta el retweet; el índice único de retweetOf y username
+// rechaza un segundo retweet del mismo usuario
+func (r *mongoTweetRepository) SaveRetweet(ctx context.Context, retweet *domain.Tweet) (bool, error) {
+	_, err := r.collection.InsertOne(ctx, retweet)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mongoTweetRepository) DeleteRetweet(ctx context.Context, username, originalID string) (*domain.Tweet, error) {
+	var retweet domain.Tweet
+	err := r.collection.FindOneAndDelete(ctx, bson.M{"username": username, "retweetOf": originalID}).Decode(&retweet)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &retweet, nil
+}
+
+func (r *mongoTweetRepository) IncrementRetweetCount(ctx context.Context, id string, delta int64) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"retweetCount": delta}})
+	return err
+}
+
+func (r *mongoTweetRepository) IncrementLikeCount(ctx context.Context, id string, delta int64) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"likeCount": delta}})
+	return err
+}
+
+func (r *mongoTweetRepository) GetTweetsByIDs(ctx context.Context, ids []string) ([]domain.Tweet, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "deletedAt": bson.M{"$exists": false}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []domain.Tweet
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]domain.Tweet, len(found))
+	for _, tweet := range found {
+		byID[tweet.ID] = tweet
+	}
+
+	tweets := make([]domain.Tweet, 0, len(ids))
+	for _, id := range ids {
+		if tweet, ok := byID[id]; ok {
+			tweets = append(tweets, tweet)
+		}
+	}
+	return tweets, nil
+}
+
+func (r *mongoTweetRepository) DeleteTweet(ctx context.Context, id string, deletedAt time.Time) (bool, error) {
+	filter := bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"deletedAt": deletedAt}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain"
+	"github.com/nicodelara/microblogging-uala/internal/tweets/domain/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoTweetSearcher busca tweets con el índice de texto de MongoDB sobre la
+// misma colección en la que se guardan
+type mongoTweetSearcher struct {
+	collection *mongo.Collection
+}
+
+// searchHit es un tweet junto con el puntaje calculado por la búsqueda
+type searchHit struct {
+	domain.Tweet `bson:",inline"`
+	Score        float64 `bson:"score"`
+}
+
+func NewMongoTweetSearcher(client *mongo.Client, dbName, collName string) (ports.TweetSearcher, error) {
+	collection := client.Database(dbName).Collection(collName)
+
+	// Una colección admite un único índice de texto. Sin idioma no se aplican
+	// stemming ni stop words, por lo que las palabras se buscan tal cual.
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "content", Value: "text"}},
+		Options: options.Index().SetDefaultLanguage("none"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoTweetSearcher{
+		collection: collection,
+	}, nil
+}
+
+func (s *mongoTweetSearcher) SearchTweets(ctx context.Context, query domain.SearchQuery, cursor *domain.SearchCursor, limit int) ([]domain.SearchHit, error) {
+	filter := bson.M{
+		"deletedAt": bson.M{"$exists": false},
+		"retweetOf": bson.M{"$exists": false},
+	}
+	if query.HasText() {
+		filter["$text"] = bson.M{"$search": textSearch(query)}
+	}
+	if query.From != "" {
+		filter["username"] = query.From
+	}
+	if len(query.Hashtags) > 0 {
+		filter["hashtags"] = bson.M{"$all": query.Hashtags}
+	}
+	if query.Since != nil || query.Until != nil {
+		createdAt := bson.M{}
+		if query.Since != nil {
+			createdAt["$gte"] = *query.Since
+		}
+		if query.Until != nil {
+			createdAt["$lt"] = *query.Until
+		}
+		filter["createdAt"] = createdAt
+	}
+
+	// Sin palabras no hay relevancia y todos los resultados puntúan 0
+	var score any = bson.M{"$literal": 0}
+	if query.HasText() {
+		score = bson.M{"$meta": "textScore"}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.M{"score": score}}},
+	}
+	if cursor != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"score": bson.M{"$lt": cursor.Score}},
+			bson.M{"score": cursor.Score, "createdAt": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"score": cursor.Score, "createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		bson.D{{Key: "$limit", Value: int64(limit)}},
+	)
+
+	result, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	var docs []searchHit
+	if err := result.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	hits := make([]domain.SearchHit, 0, len(docs))
+	for _, doc := range docs {
+		hits = append(hits, domain.SearchHit{Tweet: doc.Tweet, Score: doc.Score})
+	}
+	return hits, nil
+}
+
+// textSearch arma el texto de $search. MongoDB exige que el tweet contenga
+// todas las frases entre comillas, y sin frases alcanza con una palabra.
+func textSearch(query domain.SearchQuery) string {
+	parts := make([]string, 0, len(query.Terms)+len(query.Phrases))
+	parts = append(parts, query.Terms...)
+	for _, phrase := range query.Phrases {
+		parts = append(parts, `"`+strings.Join(phrase, " ")+`"`)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain/ports"
+)
+
+// CounterUpdater mantiene los contadores de los usuarios a partir de eventos
+// de otros servicios. Sus handlers no son idempotentes, por lo que deben
+// registrarse envueltos con inbox.Idempotent.
+type CounterUpdater struct {
+	userRepo ports.UserRepository
+}
+
+func NewCounterUpdater(userRepo ports.UserRepository) *CounterUpdater {
+	return &CounterUpdater{userRepo: userRepo}
+}
+
+// HandleTweetCreated incrementa el contador de tweets del autor
+func (u *CounterUpdater) HandleTweetCreated(ctx context.Context, evt events.Event) error {
+	var payload events.TweetCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return u.userRepo.IncrementCounters(ctx, payload.Username, domain.CounterDelta{Tweets: 1})
+}
+
+// HandleTweetDeleted decrementa el contador de tweets del autor
+func (u *CounterUpdater) HandleTweetDeleted(ctx context.Context, evt events.Event) error {
+	var payload events.TweetDeletedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return u.userRepo.IncrementCounters(ctx, payload.Username, domain.CounterDelta{Tweets: -1})
+}
+
+// counterBackfillBatchSize es la cantidad de usuarios que se leen por lote al
+// recalcular los contadores
+const counterBackfillBatchSize = 500
+
+// CounterBackfill recalcula los contadores de seguidores y seguidos contando
+// los follows, para los usuarios creados antes de que existieran los
+// contadores. Un follow que se cree o elimine mientras se procesa al usuario
+// puede quedar sin contar, por lo que conviene ejecutarlo con poco tráfico; se
+// puede ejecutar más de una vez.
+type CounterBackfill struct {
+	userRepo   ports.UserRepository
+	followRepo ports.FollowRepository
+}
+
+func NewCounterBackfill(userRepo ports.UserRepository, followRepo ports.FollowRepository) *CounterBackfill {
+	return &CounterBackfill{
+		userRepo:   userRepo,
+		followRepo: followRepo,
+	}
+}
+
+// Run recorre todos los usuarios y devuelve cuántos procesó
+func (b *CounterBackfill) Run(ctx context.Context) (int, error) {
+	processed := 0
+	after := ""
+	for {
+		users, err := b.userRepo.ListUsers(ctx, after, counterBackfillBatchSize)
+		if err != nil {
+			return processed, err
+		}
+
+		for _, user := range users {
+			followers, err := b.followRepo.CountFollowers(ctx, user.Username)
+			if err != nil {
+				return processed, err
+			}
+			following, err := b.followRepo.CountFollowings(ctx, user.Username)
+			if err != nil {
+				return processed, err
+			}
+			if followers != user.FollowersCount || following != user.FollowingCount {
+				if err := b.userRepo.SetFollowCounters(ctx, user.Username, followers, following); err != nil {
+					return processed, err
+				}
+			}
+			processed++
+		}
+
+		if len(users) < counterBackfillBatchSize {
+			return processed, nil
+		}
+		after = users[len(users)-1].Username
+	}
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/inbox"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCounterUpdater_HandleTweetCreated(t *testing.T) {
+	evt, err := events.New(events.TopicTweets, events.TweetCreated, events.TweetCreatedVersion, "alice", events.TweetCreatedPayload{
+		TweetID:  "t1",
+		Username: "alice",
+	})
+	assert.NoError(t, err)
+
+	userRepo := new(mockUserRepository)
+	userRepo.On("IncrementCounters", mock.Anything, "alice", domain.CounterDelta{Tweets: 1}).Return(nil).Once()
+
+	updater := NewCounterUpdater(userRepo)
+	handler := inbox.Idempotent(outbox.NopTransactor{}, inbox.NewMemoryStore(), updater.HandleTweetCreated)
+
+	// Una reentrega del mismo evento no vuelve a incrementar el contador
+	assert.NoError(t, handler(context.Background(), evt))
+	assert.NoError(t, handler(context.Background(), evt))
+	userRepo.AssertExpectations(t)
+}
+
+func TestCounterUpdater_HandleTweetDeleted(t *testing.T) {
+	evt, err := events.New(events.TopicTweets, events.TweetDeleted, events.TweetDeletedVersion, "alice", events.TweetDeletedPayload{
+		TweetID:  "t1",
+		Username: "alice",
+	})
+	assert.NoError(t, err)
+
+	userRepo := new(mockUserRepository)
+	userRepo.On("IncrementCounters", mock.Anything, "alice", domain.CounterDelta{Tweets: -1}).Return(nil)
+
+	assert.NoError(t, NewCounterUpdater(userRepo).HandleTweetDeleted(context.Background(), evt))
+	userRepo.AssertExpectations(t)
+}
+
+func TestCounterBackfill_Run(t *testing.T) {
+	userRepo := new(mockUserRepository)
+	userRepo.On("ListUsers", mock.Anything, "", counterBackfillBatchSize).Return([]domain.User{
+		// Un usuario anterior a los contadores y uno ya al día
+		{Username: "alice"},
+		{Username: "bob", FollowersCount: 1, FollowingCount: 2},
+	}, nil)
+	userRepo.On("SetFollowCounters", mock.Anything, "alice", int64(3), int64(1)).Return(nil)
+
+	followRepo := new(mockFollowRepository)
+	followRepo.On("CountFollowers", mock.Anything, "alice").Return(int64(3), nil)
+	followRepo.On("CountFollowings", mock.Anything, "alice").Return(int64(1), nil)
+	followRepo.On("CountFollowers", mock.Anything, "bob").Return(int64(1), nil)
+	followRepo.On("CountFollowings", mock.Anything, "bob").Return(int64(2), nil)
+
+	processed, err := NewCounterBackfill(userRepo, followRepo).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	userRepo.AssertExpectations(t)
+	userRepo.AssertNumberOfCalls(t, "SetFollowCounters", 1)
+	followRepo.AssertExpectations(t)
+}
@@ -7,10 +7,28 @@ var (
 	ErrUserAlreadyExists = errors.New("username already exists")
 	// ErrEmailAlreadyExists is returned when trying to create a user with an email that already exists
 	ErrEmailAlreadyExists = errors.New("email already exists")
+	// ErrUserNotFound is returned when the requested user does not exist
+	ErrUserNotFound = errors.New("user not found")
 	// ErrFollowerNotFound is returned when the user trying to follow does not exist
 	ErrFollowerNotFound = errors.New("follower user not found")
 	// ErrFollowingNotFound is returned when the user to follow does not exist
 	ErrFollowingNotFound = errors.New("user to follow not found")
 	// ErrAlreadyFollowing is returned when already following the user
 	ErrAlreadyFollowing = errors.New("already following this user")
+	// ErrEmptyProfileUpdate is returned when a profile update has no fields
+	ErrEmptyProfileUpdate = errors.New("no profile fields to update")
+	// ErrDisplayNameTooLong is returned when the display name exceeds its maximum length
+	ErrDisplayNameTooLong = errors.New("display name exceeds 50 characters")
+	// ErrBioTooLong is returned when the bio exceeds its maximum length
+	ErrBioTooLong = errors.New("bio exceeds 160 characters")
+	// ErrLocationTooLong is returned when the location exceeds its maximum length
+	ErrLocationTooLong = errors.New("location exceeds 30 characters")
+	// ErrInvalidWebsite is returned when the website is not a valid http(s) URL
+	ErrInvalidWebsite = errors.New("website must be a valid http or https URL of at most 100 characters")
+	// ErrInvalidAvatarURL is returned when the avatar reference is not a valid http(s) URL
+	ErrInvalidAvatarURL = errors.New("avatar must be a valid http or https URL")
+	// ErrNotFollowing is returned when trying to unfollow a user that is not followed
+	ErrNotFollowing = errors.New("not following this user")
+	// ErrEmptySearchQuery is returned when a user search has no text
+	ErrEmptySearchQuery = errors.New("search query is required")
 )
@@ -0,0 +1,47 @@
+package application
+
+import (
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+)
+
+// validateProfileUpdate valida cada campo presente en la actualización
+func validateProfileUpdate(update domain.ProfileUpdate) error {
+	if update.IsEmpty() {
+		return ErrEmptyProfileUpdate
+	}
+	if update.DisplayName != nil && utf8.RuneCountInString(*update.DisplayName) > domain.MaxDisplayNameLength {
+		return ErrDisplayNameTooLong
+	}
+	if update.Bio != nil && utf8.RuneCountInString(*update.Bio) > domain.MaxBioLength {
+		return ErrBioTooLong
+	}
+	if update.Location != nil && utf8.RuneCountInString(*update.Location) > domain.MaxLocationLength {
+		return ErrLocationTooLong
+	}
+	if update.Website != nil && !isValidURL(*update.Website, domain.MaxWebsiteLength) {
+		return ErrInvalidWebsite
+	}
+	if update.AvatarURL != nil && !isValidURL(*update.AvatarURL, domain.MaxAvatarURLLength) {
+		return ErrInvalidAvatarURL
+	}
+	return nil
+}
+
+// isValidURL acepta un valor vacío, que borra el campo, o una URL absoluta
+// http(s) de a lo sumo maxLength caracteres
+func isValidURL(raw string, maxLength int) bool {
+	if raw == "" {
+		return true
+	}
+	if len(raw) > maxLength {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
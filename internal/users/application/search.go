@@ -0,0 +1,189 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain/ports"
+)
+
+type userSearchService struct {
+	userRepo  ports.UserRepository
+	typeahead ports.TypeaheadIndex
+}
+
+// NewUserSearchService crea el servicio de búsqueda de usuarios. La búsqueda
+// completa consulta el repositorio y el typeahead sólo el índice de sugerencias.
+func NewUserSearchService(userRepo ports.UserRepository, typeahead ports.TypeaheadIndex) ports.UserSearchService {
+	return &userSearchService{
+		userRepo:  userRepo,
+		typeahead: typeahead,
+	}
+}
+
+func (s *userSearchService) SearchUsers(ctx context.Context, query string, limit int) ([]domain.UserSearchResult, error) {
+	prefix := domain.NormalizeSearchQuery(query)
+	if prefix == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	users, err := s.userRepo.SearchUsers(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.UserSearchResult, 0, len(users))
+	for _, user := range users {
+		results = append(results, domain.NewUserSearchResult(user))
+	}
+	return results, nil
+}
+
+// Typeahead responde con el índice de sugerencias. Un texto más largo que
+// los prefijos indexados se resuelve con la búsqueda completa, para no
+// sugerir usuarios que sólo coinciden con sus primeros caracteres.
+func (s *userSearchService) Typeahead(ctx context.Context, query string, limit int) ([]string, error) {
+	prefix := domain.NormalizeSearchQuery(query)
+	if prefix == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	if utf8.RuneCountInString(prefix) > domain.MaxTypeaheadPrefixLength {
+		users, err := s.userRepo.SearchUsers(ctx, prefix, limit)
+		if err != nil {
+			return nil, err
+		}
+		usernames := make([]string, 0, len(users))
+		for _, user := range users {
+			usernames = append(usernames, user.Username)
+		}
+		return usernames, nil
+	}
+
+	usernames, err := s.typeahead.Suggest(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	if usernames == nil {
+		usernames = make([]string, 0)
+	}
+	return usernames, nil
+}
+
+// TypeaheadIndexer mantiene el índice de sugerencias a partir de los eventos
+// de usuarios. Cada evento vuelve a indexar al usuario afectado con sus datos
+// actuales, por lo que reprocesar un evento no altera el índice.
+type TypeaheadIndexer struct {
+	userRepo ports.UserRepository
+	index    ports.TypeaheadIndex
+}
+
+func NewTypeaheadIndexer(userRepo ports.UserRepository, index ports.TypeaheadIndex) *TypeaheadIndexer {
+	return &TypeaheadIndexer{
+		userRepo: userRepo,
+		index:    index,
+	}
+}
+
+// HandleUserCreated indexa al usuario nuevo
+func (i *TypeaheadIndexer) HandleUserCreated(ctx context.Context, evt events.Event) error {
+	var payload events.UserCreatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return i.reindex(ctx, payload.Username)
+}
+
+// HandleUserUpdated reindexa al usuario por si cambió su nombre visible
+func (i *TypeaheadIndexer) HandleUserUpdated(ctx context.Context, evt events.Event) error {
+	var payload events.UserUpdatedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return i.reindex(ctx, payload.Username)
+}
+
+// HandleUserFollowed actualiza la cantidad de seguidores del usuario seguido
+func (i *TypeaheadIndexer) HandleUserFollowed(ctx context.Context, evt events.Event) error {
+	var payload events.UserFollowedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return i.reindex(ctx, payload.Following)
+}
+
+// HandleUserUnfollowed actualiza la cantidad de seguidores del usuario que
+// dejó de ser seguido
+func (i *TypeaheadIndexer) HandleUserUnfollowed(ctx context.Context, evt events.Event) error {
+	var payload events.UserUnfollowedPayload
+	if err := evt.Decode(&payload); err != nil {
+		return err
+	}
+	return i.reindex(ctx, payload.Following)
+}
+
+func (i *TypeaheadIndexer) reindex(ctx context.Context, username string) error {
+	user, err := i.userRepo.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+	return i.index.IndexUser(ctx, user)
+}
+
+// searchBackfillBatchSize es la cantidad de usuarios que se leen por lote al
+// completar el índice de búsqueda
+const searchBackfillBatchSize = 500
+
+// SearchBackfill completa los datos de búsqueda de los usuarios creados antes
+// de que existieran: guarda las claves de búsqueda que falten o estén
+// desactualizadas e indexa a cada usuario en el índice de sugerencias. Se
+// puede ejecutar más de una vez.
+type SearchBackfill struct {
+	userRepo ports.UserRepository
+	index    ports.TypeaheadIndex
+}
+
+func NewSearchBackfill(userRepo ports.UserRepository, index ports.TypeaheadIndex) *SearchBackfill {
+	return &SearchBackfill{
+		userRepo: userRepo,
+		index:    index,
+	}
+}
+
+// Run recorre todos los usuarios y devuelve cuántos procesó
+func (b *SearchBackfill) Run(ctx context.Context) (int, error) {
+	processed := 0
+	after := ""
+	for {
+		users, err := b.userRepo.ListUsers(ctx, after, searchBackfillBatchSize)
+		if err != nil {
+			return processed, err
+		}
+
+		for i := range users {
+			user := &users[i]
+			keys := domain.SearchKeys(user.Username, user.DisplayName)
+			if !slices.Equal(user.SearchKeys, keys) {
+				if err := b.userRepo.SetSearchKeys(ctx, user.Username, keys); err != nil {
+					return processed, err
+				}
+				user.SearchKeys = keys
+			}
+			if err := b.index.IndexUser(ctx, user); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+
+		if len(users) < searchBackfillBatchSize {
+			return processed, nil
+		}
+		after = users[len(users)-1].Username
+	}
+}
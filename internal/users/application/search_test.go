@@ -0,0 +1,235 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockTypeaheadIndex struct {
+	mock.Mock
+}
+
+func (m *mockTypeaheadIndex) IndexUser(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *mockTypeaheadIndex) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestUserSearchService_SearchUsers(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		repoSetup     func(*mockUserRepository)
+		expected      []domain.UserSearchResult
+		expectedError error
+	}{
+		{
+			name:  "query is normalized and results keep the repository order",
+			query: " @Ana ",
+			repoSetup: func(m *mockUserRepository) {
+				m.On("SearchUsers", mock.Anything, "ana", 20).Return([]domain.User{
+					{Username: "anabel", Email: "anabel@example.com", DisplayName: "Anabel", FollowersCount: 10},
+					{Username: "juan", DisplayName: "Juan Ana", FollowersCount: 2},
+				}, nil)
+			},
+			expected: []domain.UserSearchResult{
+				{Username: "anabel", DisplayName: "Anabel", FollowersCount: 10},
+				{Username: "juan", DisplayName: "Juan Ana", FollowersCount: 2},
+			},
+		},
+		{
+			name:  "no results",
+			query: "zz",
+			repoSetup: func(m *mockUserRepository) {
+				m.On("SearchUsers", mock.Anything, "zz", 20).Return([]domain.User(nil), nil)
+			},
+			expected: []domain.UserSearchResult{},
+		},
+		{
+			name:          "empty query",
+			query:         " @ ",
+			expectedError: ErrEmptySearchQuery,
+		},
+		{
+			name:  "repository error",
+			query: "ana",
+			repoSetup: func(m *mockUserRepository) {
+				m.On("SearchUsers", mock.Anything, "ana", 20).Return([]domain.User(nil), errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mockUserRepository)
+			if tt.repoSetup != nil {
+				tt.repoSetup(userRepo)
+			}
+
+			service := NewUserSearchService(userRepo, new(mockTypeaheadIndex))
+			results, err := service.SearchUsers(context.Background(), tt.query, 20)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, results)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, results)
+			}
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserSearchService_Typeahead(t *testing.T) {
+	index := new(mockTypeaheadIndex)
+	index.On("Suggest", mock.Anything, "an", 5).Return([]string{"anabel", "juan"}, nil)
+	index.On("Suggest", mock.Anything, "zz", 5).Return([]string(nil), nil)
+
+	service := NewUserSearchService(new(mockUserRepository), index)
+
+	usernames, err := service.Typeahead(context.Background(), "An", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"anabel", "juan"}, usernames)
+
+	usernames, err = service.Typeahead(context.Background(), "zz", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, usernames)
+
+	_, err = service.Typeahead(context.Background(), "", 5)
+	assert.Equal(t, ErrEmptySearchQuery, err)
+
+	index.AssertExpectations(t)
+}
+
+func TestUserSearchService_Typeahead_LongQueryUsesFullSearch(t *testing.T) {
+	query := "abcdefghijklmnopqrstuvwxyz"
+	userRepo := new(mockUserRepository)
+	userRepo.On("SearchUsers", mock.Anything, query, 5).Return([]domain.User{{Username: query}}, nil)
+	index := new(mockTypeaheadIndex)
+
+	service := NewUserSearchService(userRepo, index)
+	usernames, err := service.Typeahead(context.Background(), query, 5)
+
+	// El índice sólo tiene los primeros 20 caracteres, por lo que sugeriría
+	// usuarios que no coinciden con el resto del texto
+	assert.NoError(t, err)
+	assert.Equal(t, []string{query}, usernames)
+	userRepo.AssertExpectations(t)
+	index.AssertNotCalled(t, "Suggest", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchBackfill_Run(t *testing.T) {
+	userRepo := new(mockUserRepository)
+	batch := make([]domain.User, searchBackfillBatchSize)
+	for i := range batch {
+		username := fmt.Sprintf("user%03d", i)
+		batch[i] = domain.User{Username: username, SearchKeys: []string{username}}
+	}
+	// Un usuario anterior a las claves de búsqueda y uno con un nombre visible
+	// agregado después
+	batch[0].SearchKeys = nil
+	batch[1].DisplayName = "Ana"
+	userRepo.On("ListUsers", mock.Anything, "", searchBackfillBatchSize).Return(batch, nil)
+	userRepo.On("ListUsers", mock.Anything, batch[len(batch)-1].Username, searchBackfillBatchSize).Return([]domain.User{
+		{Username: "zoe", SearchKeys: []string{"zoe"}},
+	}, nil)
+	userRepo.On("SetSearchKeys", mock.Anything, "user000", []string{"user000"}).Return(nil)
+	userRepo.On("SetSearchKeys", mock.Anything, "user001", []string{"user001", "ana"}).Return(nil)
+
+	index := new(mockTypeaheadIndex)
+	index.On("IndexUser", mock.Anything, mock.Anything).Return(nil)
+
+	processed, err := NewSearchBackfill(userRepo, index).Run(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, searchBackfillBatchSize+1, processed)
+	userRepo.AssertExpectations(t)
+	index.AssertNumberOfCalls(t, "IndexUser", searchBackfillBatchSize+1)
+	index.AssertCalled(t, "IndexUser", mock.Anything, &domain.User{Username: "user001", DisplayName: "Ana", SearchKeys: []string{"user001", "ana"}})
+}
+
+func TestTypeaheadIndexer(t *testing.T) {
+	ana := &domain.User{Username: "ana", DisplayName: "Ana", FollowersCount: 3}
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   any
+		handle    func(*TypeaheadIndexer) events.Handler
+		repoSetup func(*mockUserRepository)
+		indexed   *domain.User
+	}{
+		{
+			name:      "created user is indexed",
+			eventType: events.UserCreated,
+			payload:   events.UserCreatedPayload{Username: "ana"},
+			handle:    func(i *TypeaheadIndexer) events.Handler { return i.HandleUserCreated },
+			repoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "ana").Return(ana, nil)
+			},
+			indexed: ana,
+		},
+		{
+			name:      "follow reindexes the followed user",
+			eventType: events.UserFollowed,
+			payload:   events.UserFollowedPayload{Username: "beto", Following: "ana"},
+			handle:    func(i *TypeaheadIndexer) events.Handler { return i.HandleUserFollowed },
+			repoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "ana").Return(ana, nil)
+			},
+			indexed: ana,
+		},
+		{
+			name:      "unfollow reindexes the unfollowed user",
+			eventType: events.UserUnfollowed,
+			payload:   events.UserUnfollowedPayload{Username: "beto", Following: "ana"},
+			handle:    func(i *TypeaheadIndexer) events.Handler { return i.HandleUserUnfollowed },
+			repoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "ana").Return(ana, nil)
+			},
+			indexed: ana,
+		},
+		{
+			name:      "missing user is ignored",
+			eventType: events.UserUpdated,
+			payload:   events.UserUpdatedPayload{Username: "ghost"},
+			handle:    func(i *TypeaheadIndexer) events.Handler { return i.HandleUserUpdated },
+			repoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "ghost").Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mockUserRepository)
+			index := new(mockTypeaheadIndex)
+			tt.repoSetup(userRepo)
+			if tt.indexed != nil {
+				index.On("IndexUser", mock.Anything, tt.indexed).Return(nil)
+			}
+
+			evt, err := events.New(events.TopicUsers, tt.eventType, 1, "key", tt.payload)
+			assert.NoError(t, err)
+
+			indexer := NewTypeaheadIndexer(userRepo, index)
+			assert.NoError(t, tt.handle(indexer)(context.Background(), evt))
+
+			userRepo.AssertExpectations(t)
+			index.AssertExpectations(t)
+		})
+	}
+}
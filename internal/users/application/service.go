@@ -3,7 +3,11 @@ package application
 import (
 	"context"
 	"slices"
+	"time"
 
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 	"github.com/nicodelara/microblogging-uala/internal/users/domain/ports"
 )
@@ -11,12 +15,18 @@ import (
 type userService struct {
 	userRepo   ports.UserRepository
 	followRepo ports.FollowRepository
+	tx         outbox.Transactor
+	publisher  events.Publisher
 }
 
-func NewUserService(userRepo ports.UserRepository, followRepo ports.FollowRepository) ports.UserService {
+// NewUserService crea el servicio de usuarios. publisher debe escribir en el
+// outbox para que los eventos se persistan en la misma transacción que la entidad.
+func NewUserService(userRepo ports.UserRepository, followRepo ports.FollowRepository, tx outbox.Transactor, publisher events.Publisher) ports.UserService {
 	return &userService{
 		userRepo:   userRepo,
 		followRepo: followRepo,
+		tx:         tx,
+		publisher:  publisher,
 	}
 }
 
@@ -38,7 +48,63 @@ func (s *userService) CreateUser(ctx context.Context, username, email string) (*
 	}
 
 	user := domain.NewUser(username, email)
-	err = s.userRepo.SaveUser(ctx, user)
+	evt, err := events.New(events.TopicUsers, events.UserCreated, events.UserCreatedVersion, user.Username, events.UserCreatedPayload{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.SaveUser(ctx, user); err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) UpdateUser(ctx context.Context, username string, update domain.ProfileUpdate) (*domain.User, error) {
+	if err := validateProfileUpdate(update); err != nil {
+		return nil, err
+	}
+
+	// El evento lleva el perfil resultante y su versión, que sólo se conocen
+	// después de aplicar la actualización
+	var user *domain.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		updated, err := s.userRepo.UpdateProfile(ctx, username, update)
+		if err != nil {
+			return err
+		}
+		if updated == nil {
+			return ErrUserNotFound
+		}
+
+		evt, err := events.New(events.TopicUsers, events.UserUpdated, events.UserUpdatedVersion, updated.Username, events.UserUpdatedPayload{
+			UserID:      updated.ID,
+			Username:    updated.Username,
+			DisplayName: updated.DisplayName,
+			Bio:         updated.Bio,
+			Location:    updated.Location,
+			Website:     updated.Website,
+			AvatarURL:   updated.AvatarURL,
+			Version:     updated.ProfileVersion,
+			UpdatedAt:   time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		user = updated
+		return s.publisher.Publish(ctx, evt)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -75,10 +141,135 @@ func (s *userService) FollowUser(ctx context.Context, username, followUsername s
 	}
 
 	follow := domain.NewFollow(username, followUsername)
-	err = s.followRepo.FollowUser(ctx, follow)
+	evt, err := events.New(events.TopicUsers, events.UserFollowed, events.UserFollowedVersion, follow.Username, events.UserFollowedPayload{
+		FollowID:  follow.ID,
+		Username:  follow.Username,
+		Following: follow.Following,
+		CreatedAt: follow.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.followRepo.FollowUser(ctx, follow); err != nil {
+			return err
+		}
+		if err := s.updateFollowCounters(ctx, username, followUsername, 1); err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return follow, nil
 }
+
+func (s *userService) UnfollowUser(ctx context.Context, username, unfollowUsername string) error {
+	// Verificar que el usuario que deja de seguir exista
+	follower, err := s.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if follower == nil {
+		return ErrFollowerNotFound
+	}
+
+	// Verificar que el usuario a dejar de seguir exista
+	following, err := s.GetUser(ctx, unfollowUsername)
+	if err != nil {
+		return err
+	}
+	if following == nil {
+		return ErrFollowingNotFound
+	}
+
+	evt, err := events.New(events.TopicUsers, events.UserUnfollowed, events.UserUnfollowedVersion, username, events.UserUnfollowedPayload{
+		Username:     username,
+		Following:    unfollowUsername,
+		UnfollowedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.followRepo.UnfollowUser(ctx, username, unfollowUsername)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrNotFollowing
+		}
+		if err := s.updateFollowCounters(ctx, username, unfollowUsername, -1); err != nil {
+			return err
+		}
+		return s.publisher.Publish(ctx, evt)
+	})
+}
+
+// updateFollowCounters actualiza los contadores de ambos extremos de una
+// relación de seguimiento. Debe llamarse dentro de la transacción del follow.
+func (s *userService) updateFollowCounters(ctx context.Context, username, following string, delta int64) error {
+	if err := s.userRepo.IncrementCounters(ctx, username, domain.CounterDelta{Following: delta}); err != nil {
+		return err
+	}
+	return s.userRepo.IncrementCounters(ctx, following, domain.CounterDelta{Followers: delta})
+}
+
+func (s *userService) ListFollowings(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.FollowList, error) {
+	if err := s.ensureUserExists(ctx, username); err != nil {
+		return nil, err
+	}
+
+	follows, err := s.followRepo.ListFollowings(ctx, username, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFollowList(follows, limit, func(f domain.Follow) string { return f.Following }), nil
+}
+
+func (s *userService) ListFollowers(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.FollowList, error) {
+	if err := s.ensureUserExists(ctx, username); err != nil {
+		return nil, err
+	}
+
+	follows, err := s.followRepo.ListFollowers(ctx, username, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFollowList(follows, limit, func(f domain.Follow) string { return f.Username }), nil
+}
+
+func (s *userService) ensureUserExists(ctx context.Context, username string) error {
+	user, err := s.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// newFollowList arma la página a partir de los follows obtenidos. other
+// indica qué extremo de la relación se muestra en el listado.
+func newFollowList(follows []domain.Follow, limit int, other func(domain.Follow) string) *domain.FollowList {
+	list := &domain.FollowList{Users: make([]domain.UserSummary, 0, len(follows))}
+	for _, follow := range follows {
+		list.Users = append(list.Users, domain.UserSummary{
+			Username:   other(follow),
+			FollowedAt: follow.CreatedAt,
+		})
+	}
+
+	// Una página incompleta indica que no hay más resultados
+	if limit > 0 && len(follows) == limit {
+		list.NextCursor = follows[len(follows)-1].Cursor().Encode()
+	}
+	return list
+}
@@ -2,8 +2,13 @@ package application
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/nicodelara/microblogging-uala/internal/common/events"
+	"github.com/nicodelara/microblogging-uala/internal/common/outbox"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -34,6 +39,39 @@ func (m *mockUserRepository) SaveUser(ctx context.Context, user *domain.User) er
 	return args.Error(0)
 }
 
+func (m *mockUserRepository) UpdateProfile(ctx context.Context, username string, update domain.ProfileUpdate) (*domain.User, error) {
+	args := m.Called(ctx, username, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *mockUserRepository) IncrementCounters(ctx context.Context, username string, delta domain.CounterDelta) error {
+	args := m.Called(ctx, username, delta)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) SearchUsers(ctx context.Context, prefix string, limit int) ([]domain.User, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *mockUserRepository) ListUsers(ctx context.Context, after string, limit int) ([]domain.User, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *mockUserRepository) SetFollowCounters(ctx context.Context, username string, followers, following int64) error {
+	args := m.Called(ctx, username, followers, following)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) SetSearchKeys(ctx context.Context, username string, keys []string) error {
+	args := m.Called(ctx, username, keys)
+	return args.Error(0)
+}
+
 type mockFollowRepository struct {
 	mock.Mock
 }
@@ -43,11 +81,41 @@ func (m *mockFollowRepository) GetFollowings(ctx context.Context, username strin
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *mockFollowRepository) GetFollowers(ctx context.Context, username string) ([]string, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockFollowRepository) CountFollowers(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockFollowRepository) CountFollowings(ctx context.Context, username string) (int64, error) {
+	args := m.Called(ctx, username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *mockFollowRepository) FollowUser(ctx context.Context, follow *domain.Follow) error {
 	args := m.Called(ctx, follow)
 	return args.Error(0)
 }
 
+func (m *mockFollowRepository) UnfollowUser(ctx context.Context, username, following string) (bool, error) {
+	args := m.Called(ctx, username, following)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockFollowRepository) ListFollowings(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Follow, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	return args.Get(0).([]domain.Follow), args.Error(1)
+}
+
+func (m *mockFollowRepository) ListFollowers(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Follow, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	return args.Get(0).([]domain.Follow), args.Error(1)
+}
+
 func TestUserService_CreateUser(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -94,7 +162,9 @@ func TestUserService_CreateUser(t *testing.T) {
 			followRepo := new(mockFollowRepository)
 			tt.userRepoSetup(userRepo)
 
-			service := NewUserService(userRepo, followRepo)
+			publisher := events.NewMemoryPublisher()
+
+			service := NewUserService(userRepo, followRepo, outbox.NopTransactor{}, publisher)
 			user, err := service.CreateUser(context.Background(), tt.username, tt.email)
 
 			if tt.expectedError != nil {
@@ -105,6 +175,11 @@ func TestUserService_CreateUser(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.username, user.Username)
 				assert.Equal(t, tt.email, user.Email)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.UserCreated, published[0].Type)
+				assert.Equal(t, events.TopicUsers, published[0].Topic)
 			}
 			userRepo.AssertExpectations(t)
 		})
@@ -127,6 +202,8 @@ func TestUserService_FollowUser(t *testing.T) {
 			userRepoSetup: func(m *mockUserRepository) {
 				m.On("GetUser", mock.Anything, "follower").Return(&domain.User{}, nil)
 				m.On("GetUser", mock.Anything, "following").Return(&domain.User{}, nil)
+				m.On("IncrementCounters", mock.Anything, "follower", domain.CounterDelta{Following: 1}).Return(nil)
+				m.On("IncrementCounters", mock.Anything, "following", domain.CounterDelta{Followers: 1}).Return(nil)
 			},
 			followRepoSetup: func(m *mockFollowRepository) {
 				m.On("GetFollowings", mock.Anything, "follower").Return([]string{}, nil)
@@ -177,20 +254,325 @@ func TestUserService_FollowUser(t *testing.T) {
 			tt.userRepoSetup(userRepo)
 			tt.followRepoSetup(followRepo)
 
-			service := NewUserService(userRepo, followRepo)
+			publisher := events.NewMemoryPublisher()
+
+			service := NewUserService(userRepo, followRepo, outbox.NopTransactor{}, publisher)
 			follow, err := service.FollowUser(context.Background(), tt.username, tt.followUsername)
 
 			if tt.expectedError != nil {
 				assert.Nil(t, follow)
 				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Empty(t, publisher.Events())
 			} else {
 				assert.NotNil(t, follow)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.username, follow.Username)
 				assert.Equal(t, tt.followUsername, follow.Following)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.UserFollowed, published[0].Type)
+
+				var payload events.UserFollowedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, tt.username, payload.Username)
+				assert.Equal(t, tt.followUsername, payload.Following)
+			}
+			userRepo.AssertExpectations(t)
+			followRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_UnfollowUser(t *testing.T) {
+	tests := []struct {
+		name             string
+		username         string
+		unfollowUsername string
+		userRepoSetup    func(*mockUserRepository)
+		followRepoSetup  func(*mockFollowRepository)
+		expectedError    error
+	}{
+		{
+			name:             "successful unfollow",
+			username:         "follower",
+			unfollowUsername: "following",
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "follower").Return(&domain.User{}, nil)
+				m.On("GetUser", mock.Anything, "following").Return(&domain.User{}, nil)
+				m.On("IncrementCounters", mock.Anything, "follower", domain.CounterDelta{Following: -1}).Return(nil)
+				m.On("IncrementCounters", mock.Anything, "following", domain.CounterDelta{Followers: -1}).Return(nil)
+			},
+			followRepoSetup: func(m *mockFollowRepository) {
+				m.On("UnfollowUser", mock.Anything, "follower", "following").Return(true, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:             "follower does not exist",
+			username:         "nonexistent",
+			unfollowUsername: "following",
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "nonexistent").Return(nil, nil)
+			},
+			followRepoSetup: func(m *mockFollowRepository) {},
+			expectedError:   ErrFollowerNotFound,
+		},
+		{
+			name:             "following does not exist",
+			username:         "follower",
+			unfollowUsername: "nonexistent",
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "follower").Return(&domain.User{}, nil)
+				m.On("GetUser", mock.Anything, "nonexistent").Return(nil, nil)
+			},
+			followRepoSetup: func(m *mockFollowRepository) {},
+			expectedError:   ErrFollowingNotFound,
+		},
+		{
+			name:             "not following",
+			username:         "follower",
+			unfollowUsername: "following",
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "follower").Return(&domain.User{}, nil)
+				m.On("GetUser", mock.Anything, "following").Return(&domain.User{}, nil)
+			},
+			followRepoSetup: func(m *mockFollowRepository) {
+				m.On("UnfollowUser", mock.Anything, "follower", "following").Return(false, nil)
+			},
+			expectedError: ErrNotFollowing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mockUserRepository)
+			followRepo := new(mockFollowRepository)
+			tt.userRepoSetup(userRepo)
+			tt.followRepoSetup(followRepo)
+
+			publisher := events.NewMemoryPublisher()
+
+			service := NewUserService(userRepo, followRepo, outbox.NopTransactor{}, publisher)
+			err := service.UnfollowUser(context.Background(), tt.username, tt.unfollowUsername)
+
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Empty(t, publisher.Events())
+			} else {
+				assert.NoError(t, err)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.UserUnfollowed, published[0].Type)
+
+				var payload events.UserUnfollowedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, tt.username, payload.Username)
+				assert.Equal(t, tt.unfollowUsername, payload.Following)
+			}
+			userRepo.AssertExpectations(t)
+			followRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_ListFollows(t *testing.T) {
+	now := time.Now()
+	cursor := &pagination.Cursor{CreatedAt: now, ID: "f0"}
+	follows := []domain.Follow{
+		{ID: "f2", Username: "alice", Following: "bob", CreatedAt: now.Add(-1 * time.Minute)},
+		{ID: "f1", Username: "carol", Following: "bob", CreatedAt: now.Add(-2 * time.Minute)},
+	}
+
+	tests := []struct {
+		name              string
+		followers         bool
+		cursor            *pagination.Cursor
+		limit             int
+		userRepoSetup     func(*mockUserRepository)
+		followRepoSetup   func(*mockFollowRepository)
+		expectedUsernames []string
+		expectedNext      string
+		expectedError     error
+	}{
+		{
+			name:  "lists followings with next cursor",
+			limit: 2,
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "bob").Return(&domain.User{}, nil)
+			},
+			followRepoSetup: func(m *mockFollowRepository) {
+				m.On("ListFollowings", mock.Anything, "bob", (*pagination.Cursor)(nil), 2).Return([]domain.Follow{
+					{ID: "f3", Username: "bob", Following: "dave", CreatedAt: now},
+					{ID: "f4", Username: "bob", Following: "erin", CreatedAt: now.Add(-1 * time.Minute)},
+				}, nil)
+			},
+			expectedUsernames: []string{"dave", "erin"},
+			expectedNext:      pagination.Cursor{CreatedAt: now.Add(-1 * time.Minute), ID: "f4"}.Encode(),
+		},
+		{
+			name:      "lists followers after cursor",
+			followers: true,
+			cursor:    cursor,
+			limit:     10,
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "bob").Return(&domain.User{}, nil)
+			},
+			followRepoSetup: func(m *mockFollowRepository) {
+				m.On("ListFollowers", mock.Anything, "bob", cursor, 10).Return(follows, nil)
+			},
+			expectedUsernames: []string{"alice", "carol"},
+		},
+		{
+			name:  "user does not exist",
+			limit: 10,
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("GetUser", mock.Anything, "bob").Return(nil, nil)
+			},
+			followRepoSetup: func(m *mockFollowRepository) {},
+			expectedError:   ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mockUserRepository)
+			followRepo := new(mockFollowRepository)
+			tt.userRepoSetup(userRepo)
+			tt.followRepoSetup(followRepo)
+
+			service := NewUserService(userRepo, followRepo, outbox.NopTransactor{}, events.NewMemoryPublisher())
+			list := service.ListFollowings
+			if tt.followers {
+				list = service.ListFollowers
+			}
+			result, err := list(context.Background(), "bob", tt.cursor, tt.limit)
+
+			if tt.expectedError != nil {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				usernames := make([]string, len(result.Users))
+				for i, user := range result.Users {
+					usernames[i] = user.Username
+				}
+				assert.Equal(t, tt.expectedUsernames, usernames)
+				assert.Equal(t, tt.expectedNext, result.NextCursor)
 			}
 			userRepo.AssertExpectations(t)
 			followRepo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestUserService_UpdateUser(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name          string
+		update        domain.ProfileUpdate
+		userRepoSetup func(*mockUserRepository)
+		expectedUser  *domain.User
+		expectedError error
+	}{
+		{
+			name: "only the requested fields are sent to the repository",
+			update: domain.ProfileUpdate{
+				DisplayName: strPtr("Alice"),
+				Website:     strPtr("https://alice.dev"),
+				Location:    strPtr(""),
+			},
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("UpdateProfile", mock.Anything, "alice", domain.ProfileUpdate{
+					DisplayName: strPtr("Alice"),
+					Website:     strPtr("https://alice.dev"),
+					Location:    strPtr(""),
+				}).Return(&domain.User{
+					ID:             "u1",
+					Username:       "alice",
+					DisplayName:    "Alice",
+					Bio:            "hola",
+					Website:        "https://alice.dev",
+					SearchKeys:     []string{"alice"},
+					ProfileVersion: 3,
+				}, nil)
+			},
+			expectedUser: &domain.User{
+				ID:             "u1",
+				Username:       "alice",
+				DisplayName:    "Alice",
+				Bio:            "hola",
+				Website:        "https://alice.dev",
+				SearchKeys:     []string{"alice"},
+				ProfileVersion: 3,
+			},
+		},
+		{
+			name:          "empty update",
+			update:        domain.ProfileUpdate{},
+			userRepoSetup: func(m *mockUserRepository) {},
+			expectedError: ErrEmptyProfileUpdate,
+		},
+		{
+			name:          "bio too long",
+			update:        domain.ProfileUpdate{Bio: strPtr(strings.Repeat("á", domain.MaxBioLength+1))},
+			userRepoSetup: func(m *mockUserRepository) {},
+			expectedError: ErrBioTooLong,
+		},
+		{
+			name:          "website without scheme",
+			update:        domain.ProfileUpdate{Website: strPtr("alice.dev")},
+			userRepoSetup: func(m *mockUserRepository) {},
+			expectedError: ErrInvalidWebsite,
+		},
+		{
+			name:          "avatar with unsupported scheme",
+			update:        domain.ProfileUpdate{AvatarURL: strPtr("ftp://cdn.example.com/a.png")},
+			userRepoSetup: func(m *mockUserRepository) {},
+			expectedError: ErrInvalidAvatarURL,
+		},
+		{
+			name:   "user does not exist",
+			update: domain.ProfileUpdate{Bio: strPtr("hola")},
+			userRepoSetup: func(m *mockUserRepository) {
+				m.On("UpdateProfile", mock.Anything, "alice", domain.ProfileUpdate{Bio: strPtr("hola")}).Return(nil, nil)
+			},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mockUserRepository)
+			followRepo := new(mockFollowRepository)
+			tt.userRepoSetup(userRepo)
+
+			publisher := events.NewMemoryPublisher()
+
+			service := NewUserService(userRepo, followRepo, outbox.NopTransactor{}, publisher)
+			user, err := service.UpdateUser(context.Background(), "alice", tt.update)
+
+			if tt.expectedError != nil {
+				assert.Nil(t, user)
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Empty(t, publisher.Events())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedUser, user)
+
+				published := publisher.Events()
+				assert.Len(t, published, 1)
+				assert.Equal(t, events.UserUpdated, published[0].Type)
+
+				var payload events.UserUpdatedPayload
+				assert.NoError(t, published[0].Decode(&payload))
+				assert.Equal(t, tt.expectedUser.DisplayName, payload.DisplayName)
+				assert.Equal(t, tt.expectedUser.Website, payload.Website)
+				assert.Equal(t, tt.expectedUser.ProfileVersion, payload.Version)
+			}
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
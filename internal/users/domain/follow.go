@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 )
 
 // Follow es una entidad del dominio que representa una relación de seguimiento entre usuarios
@@ -23,3 +24,21 @@ func NewFollow(username, following string) *Follow {
 		CreatedAt: time.Now(),
 	}
 }
+
+// Cursor devuelve el cursor que apunta a este follow dentro de un listado
+func (f *Follow) Cursor() pagination.Cursor {
+	return pagination.Cursor{CreatedAt: f.CreatedAt, ID: f.ID}
+}
+
+// UserSummary resume un usuario dentro de un listado de seguidores o seguidos
+type UserSummary struct {
+	Username   string    `json:"username"`
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+// FollowList es una página de un listado de seguidores o seguidos, ordenada
+// del follow más reciente al más antiguo
+type FollowList struct {
+	Users      []UserSummary `json:"users"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
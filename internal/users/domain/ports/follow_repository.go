@@ -3,10 +3,23 @@ package ports
 import (
 	"context"
 
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 )
 
 type FollowRepository interface {
 	GetFollowings(ctx context.Context, username string) ([]string, error)
+	GetFollowers(ctx context.Context, username string) ([]string, error)
+	CountFollowers(ctx context.Context, username string) (int64, error)
+	// CountFollowings cuenta los usuarios que sigue el usuario
+	CountFollowings(ctx context.Context, username string) (int64, error)
+	// ListFollowings devuelve una página de los follows creados por el usuario,
+	// del más reciente al más antiguo, siguientes al cursor si no es nil
+	ListFollowings(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Follow, error)
+	// ListFollowers devuelve una página de los follows recibidos por el usuario,
+	// del más reciente al más antiguo, siguientes al cursor si no es nil
+	ListFollowers(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Follow, error)
 	FollowUser(ctx context.Context, follow *domain.Follow) error
+	// UnfollowUser elimina la relación de seguimiento e indica si existía
+	UnfollowUser(ctx context.Context, username, following string) (bool, error)
 }
@@ -14,4 +14,33 @@ type UserRepository interface {
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 	// SaveUser guarda un nuevo usuario
 	SaveUser(ctx context.Context, user *domain.User) error
+	// UpdateProfile modifica sólo los campos presentes en la actualización,
+	// junto con las claves de búsqueda si cambia el nombre visible, e
+	// incrementa la versión del perfil. Devuelve el usuario resultante, o nil
+	// si no existe.
+	UpdateProfile(ctx context.Context, username string, update domain.ProfileUpdate) (*domain.User, error)
+	// IncrementCounters aplica la variación sobre los contadores del usuario.
+	// Un decremento no se aplica si el contador ya está en cero.
+	IncrementCounters(ctx context.Context, username string, delta domain.CounterDelta) error
+	// SetFollowCounters reemplaza los contadores de seguidores y seguidos
+	SetFollowCounters(ctx context.Context, username string, followers, following int64) error
+	// SearchUsers obtiene hasta limit usuarios cuyo username o alguna palabra
+	// del nombre visible empiece con prefix, de más a menos seguidores
+	SearchUsers(ctx context.Context, prefix string, limit int) ([]domain.User, error)
+	// ListUsers obtiene hasta limit usuarios con username mayor que after,
+	// ordenados por username, para recorrer todos los usuarios por lotes
+	ListUsers(ctx context.Context, after string, limit int) ([]domain.User, error)
+	// SetSearchKeys guarda las claves de búsqueda del usuario
+	SetSearchKeys(ctx context.Context, username string, keys []string) error
+}
+
+// TypeaheadIndex define la interfaz del índice de sugerencias de usuarios
+// mientras se escribe, que responde sin consultar la base de usuarios
+type TypeaheadIndex interface {
+	// IndexUser agrega o actualiza al usuario en el índice con sus datos actuales
+	IndexUser(ctx context.Context, user *domain.User) error
+	// Suggest obtiene hasta limit usernames cuyo username o alguna palabra del
+	// nombre visible empiece con prefix, de más a menos seguidores. prefix no
+	// supera domain.MaxTypeaheadPrefixLength caracteres.
+	Suggest(ctx context.Context, prefix string, limit int) ([]string, error)
 }
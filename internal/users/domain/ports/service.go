@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 )
 
@@ -12,6 +13,22 @@ type UserService interface {
 	GetUser(ctx context.Context, username string) (*domain.User, error)
 	// CreateUser crea un nuevo usuario
 	CreateUser(ctx context.Context, username, email string) (*domain.User, error)
+	// UpdateUser actualiza parcialmente el perfil de un usuario
+	UpdateUser(ctx context.Context, username string, update domain.ProfileUpdate) (*domain.User, error)
 	// FollowUser crea una relación de seguimiento entre usuarios
 	FollowUser(ctx context.Context, username, followUsername string) (*domain.Follow, error)
+	// ListFollowings lista los usuarios que sigue un usuario
+	ListFollowings(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.FollowList, error)
+	// ListFollowers lista los seguidores de un usuario
+	ListFollowers(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.FollowList, error)
+	// UnfollowUser elimina una relación de seguimiento entre usuarios
+	UnfollowUser(ctx context.Context, username, unfollowUsername string) error
+}
+
+// UserSearchService define las operaciones de búsqueda de usuarios
+type UserSearchService interface {
+	// SearchUsers busca usuarios por prefijo del username o del nombre visible
+	SearchUsers(ctx context.Context, query string, limit int) ([]domain.UserSearchResult, error)
+	// Typeahead sugiere usernames para el texto que el usuario está escribiendo
+	Typeahead(ctx context.Context, query string, limit int) ([]string, error)
 }
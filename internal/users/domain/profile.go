@@ -0,0 +1,25 @@
+package domain
+
+// Longitudes máximas, en caracteres, de los campos del perfil
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 160
+	MaxLocationLength    = 30
+	MaxWebsiteLength     = 100
+	MaxAvatarURLLength   = 2048
+)
+
+// ProfileUpdate describe una actualización parcial del perfil. Los campos nil
+// no se modifican; un string vacío borra el valor actual.
+type ProfileUpdate struct {
+	DisplayName *string
+	Bio         *string
+	Location    *string
+	Website     *string
+	AvatarURL   *string
+}
+
+// IsEmpty indica si la actualización no modifica ningún campo
+func (u ProfileUpdate) IsEmpty() bool {
+	return u.DisplayName == nil && u.Bio == nil && u.Location == nil && u.Website == nil && u.AvatarURL == nil
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MaxTypeaheadPrefixLength es la longitud máxima, en caracteres, de los
+// prefijos del índice de sugerencias. Un texto más largo no se resuelve con
+// el índice sino con la búsqueda completa.
+const MaxTypeaheadPrefixLength = 20
+
+// SearchKeys devuelve las claves por las que se encuentra a un usuario al
+// buscar por prefijo: el username y cada palabra del nombre visible, en
+// minúsculas y sin repetir
+func SearchKeys(username, displayName string) []string {
+	keys := []string{strings.ToLower(username)}
+	for _, word := range strings.FieldsFunc(strings.ToLower(displayName), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r) && r != '_'
+	}) {
+		if !slices.Contains(keys, word) {
+			keys = append(keys, word)
+		}
+	}
+	return keys
+}
+
+// NormalizeSearchQuery lleva el texto buscado a la forma de las claves de
+// búsqueda: sin espacios alrededor, sin el prefijo @ y en minúsculas
+func NormalizeSearchQuery(query string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+}
+
+// UserSearchResult resume un usuario encontrado en una búsqueda
+type UserSearchResult struct {
+	Username       string `json:"username"`
+	DisplayName    string `json:"displayName,omitempty"`
+	AvatarURL      string `json:"avatarUrl,omitempty"`
+	FollowersCount int64  `json:"followersCount"`
+}
+
+// NewUserSearchResult arma el resultado de búsqueda de un usuario
+func NewUserSearchResult(user User) UserSearchResult {
+	return UserSearchResult{
+		Username:       user.Username,
+		DisplayName:    user.DisplayName,
+		AvatarURL:      user.AvatarURL,
+		FollowersCount: user.FollowersCount,
+	}
+}
@@ -12,14 +12,43 @@ type User struct {
 	Username  string    `bson:"username" json:"username"`
 	Email     string    `bson:"email" json:"email"`
 	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+
+	// Datos de perfil editables por el usuario
+	DisplayName string `bson:"displayName,omitempty" json:"displayName,omitempty"`
+	Bio         string `bson:"bio,omitempty" json:"bio,omitempty"`
+	Location    string `bson:"location,omitempty" json:"location,omitempty"`
+	Website     string `bson:"website,omitempty" json:"website,omitempty"`
+	AvatarURL   string `bson:"avatarUrl,omitempty" json:"avatarUrl,omitempty"`
+
+	// Contadores mantenidos incrementalmente al seguir, dejar de seguir y
+	// publicar tweets
+	FollowersCount int64 `bson:"followersCount" json:"followersCount"`
+	FollowingCount int64 `bson:"followingCount" json:"followingCount"`
+	TweetsCount    int64 `bson:"tweetsCount" json:"tweetsCount"`
+
+	// SearchKeys son el username y las palabras del nombre visible en
+	// minúsculas, para buscar usuarios por prefijo con un índice
+	SearchKeys []string `bson:"searchKeys,omitempty" json:"-"`
+
+	// ProfileVersion se incrementa con cada actualización del perfil y viaja
+	// en UserUpdated para que los consumidores descarten eventos atrasados
+	ProfileVersion int64 `bson:"profileVersion,omitempty" json:"-"`
+}
+
+// CounterDelta es la variación a aplicar sobre los contadores de un usuario
+type CounterDelta struct {
+	Followers int64
+	Following int64
+	Tweets    int64
 }
 
 // NewUser crea una nueva instancia de User
 func NewUser(username, email string) *User {
 	return &User{
-		ID:        uuid.New().String(),
-		Username:  username,
-		Email:     email,
-		CreatedAt: time.Now(),
+		ID:         uuid.New().String(),
+		Username:   username,
+		Email:      email,
+		CreatedAt:  time.Now(),
+		SearchKeys: SearchKeys(username, ""),
 	}
 }
@@ -1,12 +1,15 @@
 package http
 
 import (
-	"net/http"
-
+	"context"
 	"errors"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/users/application"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 	"github.com/nicodelara/microblogging-uala/internal/users/domain/ports"
 )
 
@@ -54,6 +57,93 @@ func (h *userHandler) CreateUser(c *gin.Context) {
 	c.JSON(http.StatusCreated, user)
 }
 
+// userProfileResponse es el perfil público de un usuario. No incluye el
+// email, que sólo conoce quien crea la cuenta.
+type userProfileResponse struct {
+	ID             string    `json:"id"`
+	Username       string    `json:"username"`
+	CreatedAt      time.Time `json:"createdAt"`
+	DisplayName    string    `json:"displayName,omitempty"`
+	Bio            string    `json:"bio,omitempty"`
+	Location       string    `json:"location,omitempty"`
+	Website        string    `json:"website,omitempty"`
+	AvatarURL      string    `json:"avatarUrl,omitempty"`
+	FollowersCount int64     `json:"followersCount"`
+	FollowingCount int64     `json:"followingCount"`
+	TweetsCount    int64     `json:"tweetsCount"`
+}
+
+func newUserProfileResponse(user *domain.User) userProfileResponse {
+	return userProfileResponse{
+		ID:             user.ID,
+		Username:       user.Username,
+		CreatedAt:      user.CreatedAt,
+		DisplayName:    user.DisplayName,
+		Bio:            user.Bio,
+		Location:       user.Location,
+		Website:        user.Website,
+		AvatarURL:      user.AvatarURL,
+		FollowersCount: user.FollowersCount,
+		FollowingCount: user.FollowingCount,
+		TweetsCount:    user.TweetsCount,
+	}
+}
+
+type updateUserRequest struct {
+	DisplayName *string `json:"displayName"`
+	Bio         *string `json:"bio"`
+	Location    *string `json:"location"`
+	Website     *string `json:"website"`
+	AvatarURL   *string `json:"avatarUrl"`
+}
+
+func validateUpdateUserRequest(c *gin.Context) (*updateUserRequest, string, error) {
+	username := c.Param("username")
+	if username == "" {
+		return nil, "", errors.New("username is required")
+	}
+
+	var req updateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, "", err
+	}
+	return &req, username, nil
+}
+
+func (h *userHandler) UpdateUser(c *gin.Context) {
+	req, username, err := validateUpdateUserRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.service.UpdateUser(c.Request.Context(), username, domain.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		Location:    req.Location,
+		Website:     req.Website,
+		AvatarURL:   req.AvatarURL,
+	})
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case application.ErrEmptyProfileUpdate,
+			application.ErrDisplayNameTooLong,
+			application.ErrBioTooLong,
+			application.ErrLocationTooLong,
+			application.ErrInvalidWebsite,
+			application.ErrInvalidAvatarURL:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserProfileResponse(user))
+}
+
 type followRequest struct {
 	FollowUsername string `json:"followUsername" binding:"required"`
 }
@@ -93,3 +183,115 @@ func (h *userHandler) FollowUser(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, follow)
 }
+
+func validateUnfollowRequest(c *gin.Context) (string, string, error) {
+	username := c.Param("username")
+	if username == "" {
+		return "", "", errors.New("username is required")
+	}
+
+	target := c.Param("target")
+	if target == "" {
+		return "", "", errors.New("target is required")
+	}
+	return username, target, nil
+}
+
+func (h *userHandler) UnfollowUser(c *gin.Context) {
+	username, target, err := validateUnfollowRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.service.UnfollowUser(c.Request.Context(), username, target)
+	if err != nil {
+		switch err {
+		case application.ErrFollowerNotFound, application.ErrFollowingNotFound, application.ErrNotFollowing:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+type listFollowsRequest struct {
+	Username string
+	Cursor   *pagination.Cursor
+	Limit    int
+}
+
+func validateListFollowsRequest(c *gin.Context) (*listFollowsRequest, error) {
+	username := c.Param("username")
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+
+	limit, err := pagination.ParseLimit(c.Query("limit"), pagination.DefaultLimit, pagination.MaxLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := pagination.ParseCursor(c.Query("cursor"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &listFollowsRequest{
+		Username: username,
+		Cursor:   cursor,
+		Limit:    limit,
+	}, nil
+}
+
+func (h *userHandler) ListFollowings(c *gin.Context) {
+	h.listFollows(c, h.service.ListFollowings)
+}
+
+func (h *userHandler) ListFollowers(c *gin.Context) {
+	h.listFollows(c, h.service.ListFollowers)
+}
+
+func (h *userHandler) listFollows(c *gin.Context, list func(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.FollowList, error)) {
+	req, err := validateListFollowsRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	follows, err := list(c.Request.Context(), req.Username, req.Cursor, req.Limit)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, follows)
+}
+
+func (h *userHandler) GetUser(c *gin.Context) {
+	username := c.Param("username")
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": application.ErrUserNotFound.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserProfileResponse(user))
+}
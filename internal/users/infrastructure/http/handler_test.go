@@ -0,0 +1,127 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUserService struct {
+	mock.Mock
+}
+
+func (m *mockUserService) GetUser(ctx context.Context, username string) (*domain.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *mockUserService) CreateUser(ctx context.Context, username, email string) (*domain.User, error) {
+	args := m.Called(ctx, username, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *mockUserService) UpdateUser(ctx context.Context, username string, update domain.ProfileUpdate) (*domain.User, error) {
+	args := m.Called(ctx, username, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *mockUserService) FollowUser(ctx context.Context, username, followUsername string) (*domain.Follow, error) {
+	args := m.Called(ctx, username, followUsername)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Follow), args.Error(1)
+}
+
+func (m *mockUserService) ListFollowings(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.FollowList, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FollowList), args.Error(1)
+}
+
+func (m *mockUserService) ListFollowers(ctx context.Context, username string, cursor *pagination.Cursor, limit int) (*domain.FollowList, error) {
+	args := m.Called(ctx, username, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FollowList), args.Error(1)
+}
+
+func (m *mockUserService) UnfollowUser(ctx context.Context, username, unfollowUsername string) error {
+	args := m.Called(ctx, username, unfollowUsername)
+	return args.Error(0)
+}
+
+func TestUserHandler_ProfileDoesNotExposeEmail(t *testing.T) {
+	user := &domain.User{ID: "1", Username: "alice", Email: "alice@example.com", DisplayName: "Alice", FollowersCount: 3}
+	displayName := "Alice"
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		service func(*mockUserService)
+	}{
+		{
+			name:   "get user",
+			method: http.MethodGet,
+			service: func(m *mockUserService) {
+				m.On("GetUser", mock.Anything, "alice").Return(user, nil)
+			},
+		},
+		{
+			name:   "update user",
+			method: http.MethodPatch,
+			body:   `{"displayName": "Alice"}`,
+			service: func(m *mockUserService) {
+				m.On("UpdateUser", mock.Anything, "alice", domain.ProfileUpdate{DisplayName: &displayName}).Return(user, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(mockUserService)
+			tt.service(service)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			handler := NewUserHandler(service)
+			router.GET("/users/:username", handler.GetUser)
+			router.PATCH("/users/:username", handler.UpdateUser)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, "/users/alice", bytes.NewBufferString(tt.body))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var body map[string]any
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.NotContains(t, body, "email")
+			assert.Equal(t, "alice", body["username"])
+			assert.Equal(t, "Alice", body["displayName"])
+			assert.Equal(t, float64(3), body["followersCount"])
+			service.AssertExpectations(t)
+		})
+	}
+}
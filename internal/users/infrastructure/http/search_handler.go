@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
+	"github.com/nicodelara/microblogging-uala/internal/users/application"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain/ports"
+)
+
+const (
+	defaultSearchLimit    = 20
+	maxSearchLimit        = 50
+	defaultTypeaheadLimit = 5
+	maxTypeaheadLimit     = 10
+)
+
+type searchHandler struct {
+	service ports.UserSearchService
+}
+
+func NewSearchHandler(service ports.UserSearchService) *searchHandler {
+	return &searchHandler{
+		service: service,
+	}
+}
+
+type searchUsersResponse struct {
+	Users []domain.UserSearchResult `json:"users"`
+}
+
+type typeaheadResponse struct {
+	Usernames []string `json:"usernames"`
+}
+
+func (h *searchHandler) SearchUsers(c *gin.Context) {
+	limit, err := pagination.ParseLimit(c.Query("limit"), defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := h.service.SearchUsers(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		switch err {
+		case application.ErrEmptySearchQuery:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, searchUsersResponse{Users: users})
+}
+
+func (h *searchHandler) Typeahead(c *gin.Context) {
+	limit, err := pagination.ParseLimit(c.Query("limit"), defaultTypeaheadLimit, maxTypeaheadLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	usernames, err := h.service.Typeahead(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		switch err {
+		case application.ErrEmptySearchQuery:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, typeaheadResponse{Usernames: usernames})
+}
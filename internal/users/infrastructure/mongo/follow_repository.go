@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/nicodelara/microblogging-uala/internal/common/pagination"
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -37,6 +38,22 @@ func NewMongoFollowRepository(client *mongo.Client, dbName, collection string) (
 			},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			// Listado de seguidos paginado por fecha
+			Keys: bson.D{
+				{Key: "username", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
+		{
+			// Conteo y listado de seguidores paginado por fecha
+			Keys: bson.D{
+				{Key: "following", Value: 1},
+				{Key: "createdAt", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+		},
 	}
 
 	_, err := coll.Indexes().CreateMany(context.Background(), indexModels)
@@ -72,8 +89,84 @@ func (r *mongoFollowRepository) GetFollowings(ctx context.Context, username stri
 	return followingUsernames, nil
 }
 
+func (r *mongoFollowRepository) GetFollowers(ctx context.Context, username string) ([]string, error) {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	opts := options.Find().SetProjection(bson.M{"username": 1})
+	cursor, err := collection.Find(ctx, bson.M{"following": username}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var follows []domain.Follow
+	if err := cursor.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+
+	followerUsernames := make([]string, len(follows))
+	for i, follow := range follows {
+		followerUsernames[i] = follow.Username
+	}
+
+	return followerUsernames, nil
+}
+
+func (r *mongoFollowRepository) ListFollowings(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Follow, error) {
+	return r.list(ctx, bson.M{"username": username}, cursor, limit)
+}
+
+func (r *mongoFollowRepository) ListFollowers(ctx context.Context, username string, cursor *pagination.Cursor, limit int) ([]domain.Follow, error) {
+	return r.list(ctx, bson.M{"following": username}, cursor, limit)
+}
+
+// list devuelve los follows que cumplen el filtro, del más reciente al más
+// antiguo, empezando después del cursor si no es nil
+func (r *mongoFollowRepository) list(ctx context.Context, filter bson.M, cursor *pagination.Cursor, limit int) ([]domain.Follow, error) {
+	if cursor != nil {
+		filter["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+	result, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	var follows []domain.Follow
+	if err := result.All(ctx, &follows); err != nil {
+		return nil, err
+	}
+	return follows, nil
+}
+
+func (r *mongoFollowRepository) CountFollowers(ctx context.Context, username string) (int64, error) {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	return collection.CountDocuments(ctx, bson.M{"following": username})
+}
+
+func (r *mongoFollowRepository) CountFollowings(ctx context.Context, username string) (int64, error) {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	return collection.CountDocuments(ctx, bson.M{"username": username})
+}
+
 func (r *mongoFollowRepository) FollowUser(ctx context.Context, follow *domain.Follow) error {
 	collection := r.client.Database(r.dbName).Collection(r.collection)
 	_, err := collection.InsertOne(ctx, follow)
 	return err
 }
+
+func (r *mongoFollowRepository) UnfollowUser(ctx context.Context, username, following string) (bool, error) {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	result, err := collection.DeleteOne(ctx, bson.M{"username": username, "following": following})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 	"errors"
+	"regexp"
 
 	"github.com/nicodelara/microblogging-uala/internal/users/domain"
 	"go.mongodb.org/mongo-driver/bson"
@@ -38,6 +39,10 @@ func NewMongoUserRepository(client *mongo.Client, dbName, collection string) (*m
 			Keys:    bson.D{{Key: "email", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			// Búsqueda por prefijo del username o de una palabra del nombre
+			Keys: bson.D{{Key: "searchKeys", Value: 1}, {Key: "followersCount", Value: -1}},
+		},
 	}
 
 	_, err := coll.Indexes().CreateMany(context.Background(), indexModels)
@@ -83,3 +88,126 @@ func (r *mongoUserRepository) SaveUser(ctx context.Context, user *domain.User) e
 	_, err := collection.InsertOne(ctx, user)
 	return err
 }
+
+// UpdateProfile aplica la actualización con un único FindOneAndUpdate, por lo
+// que dos actualizaciones concurrentes de campos distintos no se pisan
+func (r *mongoUserRepository) UpdateProfile(ctx context.Context, username string, update domain.ProfileUpdate) (*domain.User, error) {
+	set := bson.M{}
+	if update.DisplayName != nil {
+		set["displayName"] = *update.DisplayName
+		set["searchKeys"] = domain.SearchKeys(username, *update.DisplayName)
+	}
+	if update.Bio != nil {
+		set["bio"] = *update.Bio
+	}
+	if update.Location != nil {
+		set["location"] = *update.Location
+	}
+	if update.Website != nil {
+		set["website"] = *update.Website
+	}
+	if update.AvatarURL != nil {
+		set["avatarUrl"] = *update.AvatarURL
+	}
+
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var user domain.User
+	err := collection.FindOneAndUpdate(ctx, bson.M{"username": username}, bson.M{
+		"$set": set,
+		"$inc": bson.M{"profileVersion": 1},
+	}, opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// IncrementCounters aplica la variación con $inc. Los decrementos exigen que
+// el contador sea positivo, para que un contador sin inicializar o
+// desfasado no quede negativo.
+func (r *mongoUserRepository) IncrementCounters(ctx context.Context, username string, delta domain.CounterDelta) error {
+	filter := bson.M{"username": username}
+	inc := bson.M{}
+	for field, value := range map[string]int64{
+		"followersCount": delta.Followers,
+		"followingCount": delta.Following,
+		"tweetsCount":    delta.Tweets,
+	} {
+		if value == 0 {
+			continue
+		}
+		inc[field] = value
+		if value < 0 {
+			filter[field] = bson.M{"$gt": 0}
+		}
+	}
+	if len(inc) == 0 {
+		return nil
+	}
+
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$inc": inc})
+	return err
+}
+
+func (r *mongoUserRepository) SetFollowCounters(ctx context.Context, username string, followers, following int64) error {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	_, err := collection.UpdateOne(ctx, bson.M{"username": username}, bson.M{"$set": bson.M{
+		"followersCount": followers,
+		"followingCount": following,
+	}})
+	return err
+}
+
+// SearchUsers busca los usuarios con alguna clave de búsqueda que empiece con
+// prefix, de más a menos seguidores. Una expresión regular anclada al inicio y
+// sin opciones se resuelve con un rango sobre el índice de searchKeys.
+func (r *mongoUserRepository) SearchUsers(ctx context.Context, prefix string, limit int) ([]domain.User, error) {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	filter := bson.M{"searchKeys": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "followersCount", Value: -1}, {Key: "username", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListUsers recorre los usuarios por username, que tiene un índice único
+func (r *mongoUserRepository) ListUsers(ctx context.Context, after string, limit int) ([]domain.User, error) {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "username", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, bson.M{"username": bson.M{"$gt": after}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *mongoUserRepository) SetSearchKeys(ctx context.Context, username string, keys []string) error {
+	collection := r.client.Database(r.dbName).Collection(r.collection)
+	_, err := collection.UpdateOne(ctx, bson.M{"username": username}, bson.M{"$set": bson.M{"searchKeys": keys}})
+	return err
+}
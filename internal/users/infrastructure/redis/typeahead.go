@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/nicodelara/microblogging-uala/internal/users/domain"
+)
+
+const (
+	// typeaheadKeyPrefix es el prefijo de los sorted sets de sugerencias, uno
+	// por prefijo: typeahead:<prefijo>, con los usernames y sus seguidores
+	// como puntaje
+	typeaheadKeyPrefix = "typeahead:"
+
+	// typeaheadUserKeyPrefix guarda los prefijos en los que está indexado
+	// cada usuario, para quitarlo de los que dejan de aplicar
+	typeaheadUserKeyPrefix = "typeahead:user:"
+
+	// typeaheadMaxEntries es la cantidad de usuarios que se conservan por
+	// prefijo; los de menos seguidores se descartan
+	typeaheadMaxEntries = 100
+)
+
+// redisTypeaheadIndex precalcula, para cada prefijo de las claves de
+// búsqueda de los usuarios, los usernames con más seguidores. Una sugerencia
+// se resuelve con un único ZREVRANGE.
+type redisTypeaheadIndex struct {
+	client *redis.Client
+}
+
+func NewRedisTypeaheadIndex(client *redis.Client) *redisTypeaheadIndex {
+	return &redisTypeaheadIndex{client: client}
+}
+
+// typeaheadPrefixes devuelve los prefijos de las claves, sin repetir
+func typeaheadPrefixes(keys []string) []string {
+	var prefixes []string
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		runes := []rune(key)
+		for i := 1; i <= len(runes) && i <= domain.MaxTypeaheadPrefixLength; i++ {
+			prefix := string(runes[:i])
+			if !seen[prefix] {
+				seen[prefix] = true
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	return prefixes
+}
+
+func (r *redisTypeaheadIndex) IndexUser(ctx context.Context, user *domain.User) error {
+	userKey := typeaheadUserKeyPrefix + user.Username
+	previous, err := r.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	prefixes := typeaheadPrefixes(domain.SearchKeys(user.Username, user.DisplayName))
+	current := make(map[string]bool, len(prefixes))
+	for _, prefix := range prefixes {
+		current[prefix] = true
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// Quitar al usuario de los prefijos de un nombre visible anterior
+		for _, prefix := range previous {
+			if !current[prefix] {
+				pipe.ZRem(ctx, typeaheadKeyPrefix+prefix, user.Username)
+			}
+		}
+
+		members := make([]interface{}, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			key := typeaheadKeyPrefix + prefix
+			pipe.ZAdd(ctx, key, &redis.Z{Score: float64(user.FollowersCount), Member: user.Username})
+			pipe.ZRemRangeByRank(ctx, key, 0, -typeaheadMaxEntries-1)
+			members = append(members, prefix)
+		}
+
+		pipe.Del(ctx, userKey)
+		if len(members) > 0 {
+			pipe.SAdd(ctx, userKey, members...)
+		}
+		return nil
+	})
+	return err
+}
+
+// Suggest sólo encuentra prefijos de hasta domain.MaxTypeaheadPrefixLength
+// caracteres, que son los indexados
+func (r *redisTypeaheadIndex) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return r.client.ZRevRange(ctx, typeaheadKeyPrefix+prefix, 0, int64(limit-1)).Result()
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,6 +17,34 @@ type Config struct {
 	RedisAddr    string
 	KafkaBrokers string
 	CacheTTL     time.Duration
+
+	CacheStaleTTL  time.Duration
+	CacheLeaseTTL  time.Duration
+	CacheLeaseWait time.Duration
+
+	LocalCacheTTL      time.Duration
+	LocalCacheMaxBytes int
+
+	OutboxPollInterval time.Duration
+	OutboxMaxAttempts  int
+
+	TimelineMaxLength          int
+	CelebrityFollowerThreshold int64
+
+	TimelineAdminAddr string
+
+	NotificationsPort string
+
+	StreamHeartbeatInterval time.Duration
+	StreamBufferSize        int
+	StreamResumeLimit       int
+
+	GatewayPort                  string
+	GatewayAuthSecret            string
+	GatewayMaxConnections        int
+	GatewayMaxConnectionsPerUser int
+	GatewayMaxSubscriptions      int
+	GatewayConsumerGroup         string
 }
 
 func LoadConfig() (*Config, error) {
@@ -34,6 +63,28 @@ func LoadConfig() (*Config, error) {
 		timelinePort = "8083"
 	}
 
+	// El listener de administración del timeline expone métricas del proceso,
+	// por lo que por defecto sólo escucha en loopback
+	timelineAdminAddr := os.Getenv("TIMELINE_ADMIN_ADDR")
+	if timelineAdminAddr == "" {
+		timelineAdminAddr = "127.0.0.1:9083"
+	}
+
+	notificationsPort := os.Getenv("NOTIFICATIONS_PORT")
+	if notificationsPort == "" {
+		notificationsPort = "8084"
+	}
+
+	gatewayPort := os.Getenv("GATEWAY_PORT")
+	if gatewayPort == "" {
+		gatewayPort = "8086"
+	}
+
+	gatewayConsumerGroup := os.Getenv("GATEWAY_CONSUMER_GROUP")
+	if gatewayConsumerGroup == "" {
+		gatewayConsumerGroup = "gateway"
+	}
+
 	mongoURI := os.Getenv("MONGO_URI")
 	if mongoURI == "" {
 		mongoURI = "mongodb://localhost:27017"
@@ -67,6 +118,126 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	cacheStaleTTLStr := os.Getenv("CACHE_STALE_TTL_SECONDS")
+	cacheStaleTTL := 60 * time.Second // valor por defecto
+	if cacheStaleTTLStr != "" {
+		if seconds, err := strconv.Atoi(cacheStaleTTLStr); err == nil && seconds >= 0 {
+			cacheStaleTTL = time.Duration(seconds) * time.Second
+		}
+	}
+
+	cacheLeaseTTLStr := os.Getenv("CACHE_LEASE_TTL_SECONDS")
+	cacheLeaseTTL := 10 * time.Second // valor por defecto
+	if cacheLeaseTTLStr != "" {
+		if seconds, err := strconv.Atoi(cacheLeaseTTLStr); err == nil && seconds > 0 {
+			cacheLeaseTTL = time.Duration(seconds) * time.Second
+		}
+	}
+
+	cacheLeaseWaitStr := os.Getenv("CACHE_LEASE_WAIT_MS")
+	cacheLeaseWait := 500 * time.Millisecond // valor por defecto
+	if cacheLeaseWaitStr != "" {
+		if ms, err := strconv.Atoi(cacheLeaseWaitStr); err == nil && ms > 0 {
+			cacheLeaseWait = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	localCacheTTLStr := os.Getenv("LOCAL_CACHE_TTL_MS")
+	localCacheTTL := time.Second // valor por defecto; 0 desactiva el caché local
+	if localCacheTTLStr != "" {
+		if ms, err := strconv.Atoi(localCacheTTLStr); err == nil {
+			localCacheTTL = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	localCacheMaxMBStr := os.Getenv("LOCAL_CACHE_MAX_MB")
+	localCacheMaxBytes := 64 << 20 // valor por defecto
+	if localCacheMaxMBStr != "" {
+		if mb, err := strconv.Atoi(localCacheMaxMBStr); err == nil {
+			localCacheMaxBytes = mb << 20
+		}
+	}
+
+	outboxPollIntervalStr := os.Getenv("OUTBOX_POLL_INTERVAL_MS")
+	outboxPollInterval := 500 * time.Millisecond // valor por defecto
+	if outboxPollIntervalStr != "" {
+		if ms, err := strconv.Atoi(outboxPollIntervalStr); err == nil {
+			outboxPollInterval = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	outboxMaxAttemptsStr := os.Getenv("OUTBOX_MAX_ATTEMPTS")
+	outboxMaxAttempts := 10 // valor por defecto
+	if outboxMaxAttemptsStr != "" {
+		if attempts, err := strconv.Atoi(outboxMaxAttemptsStr); err == nil {
+			outboxMaxAttempts = attempts
+		}
+	}
+
+	timelineMaxLengthStr := os.Getenv("TIMELINE_MAX_LENGTH")
+	timelineMaxLength := 800 // valor por defecto
+	if timelineMaxLengthStr != "" {
+		if length, err := strconv.Atoi(timelineMaxLengthStr); err == nil {
+			timelineMaxLength = length
+		}
+	}
+
+	celebrityThresholdStr := os.Getenv("CELEBRITY_FOLLOWER_THRESHOLD")
+	celebrityThreshold := int64(10000) // valor por defecto
+	if celebrityThresholdStr != "" {
+		if threshold, err := strconv.ParseInt(celebrityThresholdStr, 10, 64); err == nil {
+			celebrityThreshold = threshold
+		}
+	}
+
+	streamHeartbeatStr := os.Getenv("STREAM_HEARTBEAT_SECONDS")
+	streamHeartbeat := 15 * time.Second // valor por defecto
+	if streamHeartbeatStr != "" {
+		if seconds, err := strconv.Atoi(streamHeartbeatStr); err == nil && seconds > 0 {
+			streamHeartbeat = time.Duration(seconds) * time.Second
+		}
+	}
+
+	streamBufferSizeStr := os.Getenv("STREAM_BUFFER_SIZE")
+	streamBufferSize := 64 // valor por defecto
+	if streamBufferSizeStr != "" {
+		if size, err := strconv.Atoi(streamBufferSizeStr); err == nil && size > 0 {
+			streamBufferSize = size
+		}
+	}
+
+	streamResumeLimitStr := os.Getenv("STREAM_RESUME_LIMIT")
+	streamResumeLimit := 100 // valor por defecto
+	if streamResumeLimitStr != "" {
+		if limit, err := strconv.Atoi(streamResumeLimitStr); err == nil && limit > 0 {
+			streamResumeLimit = limit
+		}
+	}
+
+	gatewayMaxConnectionsStr := os.Getenv("GATEWAY_MAX_CONNECTIONS")
+	gatewayMaxConnections := 10000 // valor por defecto
+	if gatewayMaxConnectionsStr != "" {
+		if limit, err := strconv.Atoi(gatewayMaxConnectionsStr); err == nil && limit > 0 {
+			gatewayMaxConnections = limit
+		}
+	}
+
+	gatewayMaxPerUserStr := os.Getenv("GATEWAY_MAX_CONNECTIONS_PER_USER")
+	gatewayMaxPerUser := 5 // valor por defecto
+	if gatewayMaxPerUserStr != "" {
+		if limit, err := strconv.Atoi(gatewayMaxPerUserStr); err == nil && limit > 0 {
+			gatewayMaxPerUser = limit
+		}
+	}
+
+	gatewayMaxSubscriptionsStr := os.Getenv("GATEWAY_MAX_SUBSCRIPTIONS")
+	gatewayMaxSubscriptions := 100 // valor por defecto
+	if gatewayMaxSubscriptionsStr != "" {
+		if limit, err := strconv.Atoi(gatewayMaxSubscriptionsStr); err == nil && limit > 0 {
+			gatewayMaxSubscriptions = limit
+		}
+	}
+
 	return &Config{
 		TweetsPort:   tweetsPort,
 		UsersPort:    usersPort,
@@ -77,5 +248,45 @@ func LoadConfig() (*Config, error) {
 		RedisAddr:    redisAddr,
 		KafkaBrokers: kafkaBrokers,
 		CacheTTL:     cacheTTL,
+
+		CacheStaleTTL:  cacheStaleTTL,
+		CacheLeaseTTL:  cacheLeaseTTL,
+		CacheLeaseWait: cacheLeaseWait,
+
+		LocalCacheTTL:      localCacheTTL,
+		LocalCacheMaxBytes: localCacheMaxBytes,
+
+		OutboxPollInterval: outboxPollInterval,
+		OutboxMaxAttempts:  outboxMaxAttempts,
+
+		TimelineMaxLength:          timelineMaxLength,
+		CelebrityFollowerThreshold: celebrityThreshold,
+
+		TimelineAdminAddr: timelineAdminAddr,
+
+		NotificationsPort: notificationsPort,
+
+		StreamHeartbeatInterval: streamHeartbeat,
+		StreamBufferSize:        streamBufferSize,
+		StreamResumeLimit:       streamResumeLimit,
+
+		GatewayPort:                  gatewayPort,
+		GatewayAuthSecret:            os.Getenv("GATEWAY_AUTH_SECRET"),
+		GatewayMaxConnections:        gatewayMaxConnections,
+		GatewayMaxConnectionsPerUser: gatewayMaxPerUser,
+		GatewayMaxSubscriptions:      gatewayMaxSubscriptions,
+		GatewayConsumerGroup:         gatewayConsumerGroup,
 	}, nil
 }
+
+// Brokers devuelve la lista de brokers de Kafka configurada en KAFKA_BROKERS,
+// separada por comas
+func (c *Config) Brokers() []string {
+	var brokers []string
+	for _, broker := range strings.Split(c.KafkaBrokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	return brokers
+}
@@ -2,11 +2,20 @@ package logger
 
 import (
 	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// sensitiveParams son los parámetros de query cuyo valor no se escribe en los
+// logs, como el token con el que se abren los WebSockets
+var sensitiveParams = map[string]bool{
+	"token":        true,
+	"access_token": true,
+}
+
 func Init() {
 	// Configuración básica del logger.
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
@@ -26,7 +35,7 @@ func GinLogger() gin.HandlerFunc {
 		// Inicio del tiempo
 		start := time.Now()
 		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
+		raw := RedactQuery(c.Request.URL.RawQuery)
 
 		// Procesar la petición
 		c.Next()
@@ -56,3 +65,23 @@ func GinLogger() gin.HandlerFunc {
 		)
 	}
 }
+
+// RedactQuery reemplaza el valor de los parámetros sensibles de una query
+// string, conservando el resto tal como llegó
+func RedactQuery(raw string) string {
+	if raw == "" {
+		return raw
+	}
+
+	params := strings.Split(raw, "&")
+	for i, param := range params {
+		name, _, found := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if found && sensitiveParams[strings.ToLower(name)] {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	return strings.Join(params, "&")
+}
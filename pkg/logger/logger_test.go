@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			name:     "empty query",
+			raw:      "",
+			expected: "",
+		},
+		{
+			name:     "no sensitive params",
+			raw:      "limit=10&cursor=abc",
+			expected: "limit=10&cursor=abc",
+		},
+		{
+			name:     "token",
+			raw:      "token=alice.1700000000.c2lnbmF0dXJl&topic=timeline",
+			expected: "token=REDACTED&topic=timeline",
+		},
+		{
+			name:     "encoded and upper case name",
+			raw:      "limit=10&%54OKEN=secret&access_token=secret",
+			expected: "limit=10&TOKEN=REDACTED&access_token=REDACTED",
+		},
+		{
+			name:     "repeated token",
+			raw:      "token=a&token=b",
+			expected: "token=REDACTED&token=REDACTED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RedactQuery(tt.raw))
+		})
+	}
+}